@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion script",
+	Long: `Generate a shell completion script for lxc-dev-manager.
+
+Bash:
+  source <(lxc-dev-manager completion bash)
+  # or, to load for every session:
+  lxc-dev-manager completion bash > /etc/bash_completion.d/lxc-dev-manager
+
+Zsh:
+  lxc-dev-manager completion zsh > "${fpath[1]}/_lxc-dev-manager"
+
+Fish:
+  lxc-dev-manager completion fish > ~/.config/fish/completions/lxc-dev-manager.fish
+
+PowerShell:
+  lxc-dev-manager completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Annotations:           map[string]string{noProjectSearch: "true"},
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func init() {
+	// Replace cobra's default completion command with ours
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+
+	// Commands whose first argument is a container name
+	for _, c := range []*cobra.Command{
+		upCmd, downCmd, restartCmd, freezeCmd, unfreezeCmd, statusCmd, removeCmd, sshCmd, runCmd, proxyCmd, logsCmd,
+		containerResetCmd, containerCloneCmd, containerRenameCmd, containerInspectCmd, containerInfoCmd, containerLogsCmd, containerMountCmd, containerUnmountCmd, containerAutostartCmd,
+		containerEnvSetCmd, containerEnvListCmd, containerEnvUnsetCmd,
+		containerPortAddCmd, containerPortRemoveCmd, containerPortListCmd,
+		containerSnapshotCreateCmd, containerSnapshotListCmd, containerSnapshotPruneCmd,
+		imageCreateCmd, configShowCmd, configGetCmd,
+	} {
+		c.ValidArgsFunction = completeContainerNames
+	}
+
+	// <container> <snapshot>
+	containerSnapshotDeleteCmd.ValidArgsFunction = completeContainerSnapshots
+	containerResetCmd.ValidArgsFunction = completeContainerSnapshots
+	containerSnapshotRestoreCmd.ValidArgsFunction = completeContainerSnapshots
+
+	// Commands whose first argument is an image alias
+	imageDeleteCmd.ValidArgsFunction = completeImageNames
+	imageRenameCmd.ValidArgsFunction = completeImageNames
+	imageExportCmd.ValidArgsFunction = completeImageNames
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	root := cmd.Root()
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return root.GenZshCompletion(os.Stdout)
+	case "fish":
+		return root.GenFishCompletion(os.Stdout, true)
+	default:
+		return root.GenPowerShellCompletionWithDesc(os.Stdout)
+	}
+}
+
+// completeContainerNames completes the first argument with container names from config
+func completeContainerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.Load()
+	if err != nil || cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return containerNames(cfg), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContainerSnapshots completes a container name, then one of its snapshots from config
+func completeContainerSnapshots(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeContainerNames(cmd, args, toComplete)
+	}
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.Load()
+	if err != nil || cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for name := range cfg.GetSnapshots(args[0]) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeImageNames completes the first argument with local image aliases
+func completeImageNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// Images live on the project's remote, if one is configured
+	if cfg, err := config.Load(); err == nil && cfg != nil {
+		lxc.SetRemote(cfg.Defaults.Remote)
+	}
+	images, err := lxc.ListImages(false)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, img := range images {
+		if img.Alias != "" {
+			names = append(names, img.Alias)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
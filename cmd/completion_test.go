@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteContainerNames(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  web:
+    image: ubuntu:24.04
+  api:
+    image: ubuntu:24.04
+`)
+
+	names, directive := completeContainerNames(nil, nil, "")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("unexpected directive: %v", directive)
+	}
+	if strings.Join(names, ",") != "api,web" {
+		t.Errorf("expected sorted container names, got %v", names)
+	}
+
+	// Only the first argument is a container name
+	names, _ = completeContainerNames(nil, []string{"web"}, "")
+	if len(names) != 0 {
+		t.Errorf("expected no completions for second argument, got %v", names)
+	}
+}
+
+func TestCompleteContainerNames_NoProject(t *testing.T) {
+	_ = setupTestEnv(t)
+
+	names, directive := completeContainerNames(nil, nil, "")
+	if len(names) != 0 {
+		t.Errorf("expected no completions, got %v", names)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("unexpected directive: %v", directive)
+	}
+}
+
+func TestCompleteContainerSnapshots(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      initial-state:
+        created_at: "2024-01-15T10:30:00Z"
+      checkpoint:
+        created_at: "2024-01-16T10:30:00Z"
+`)
+
+	names, _ := completeContainerSnapshots(nil, nil, "")
+	if strings.Join(names, ",") != "dev1" {
+		t.Errorf("expected container names first, got %v", names)
+	}
+
+	names, _ = completeContainerSnapshots(nil, []string{"dev1"}, "")
+	if strings.Join(names, ",") != "checkpoint,initial-state" {
+		t.Errorf("expected snapshot names, got %v", names)
+	}
+}
+
+func TestCompleteImageNames(t *testing.T) {
+	env := setupTestEnv(t)
+	env.mock.SetOutput("image list --format=csv -c lfsd", "my-base,abc123,100MB,\n,def456,200MB,cached\nnode-ready,789abc,300MB,\n")
+
+	names, _ := completeImageNames(nil, nil, "")
+	if strings.Join(names, ",") != "my-base,node-ready" {
+		t.Errorf("expected image aliases, got %v", names)
+	}
+}
+
+func TestCompletion_Bash(t *testing.T) {
+	env := setupTestEnv(t)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runCompletion(completionCmd, []string{"bash"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "lxc-dev-manager") {
+		t.Error("expected bash completion script")
+	}
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/lxc"
+	"lxc-dev-manager/internal/proxy"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the project configuration",
+	Long:  `Commands for inspecting containers.yaml and the LXC config of containers.`,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check containers.yaml for errors",
+	Long: `Check containers.yaml without touching any containers.
+
+Every problem is reported with the field it belongs to, not just the first.
+Exits with status 1 if the file is invalid.
+
+Example:
+  lxc-dev-manager config validate`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show [container]",
+	Short: "Print the effective configuration",
+	Long: `Print the configuration containers actually get once project defaults
+are merged in: ports, user, environment and limits.
+
+With a container name, only that container is shown. Settings inherited
+from defaults are marked "(default)".
+
+Examples:
+  lxc-dev-manager config show
+  lxc-dev-manager config show dev1
+  lxc-dev-manager config show dev1 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigShow,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <container> <key>",
+	Short: "Print an LXC config key of a container",
+	Long: `Print the value of an LXC config key such as security.nesting or
+limits.memory, as set on the running instance. An unset key prints an
+empty line.
+
+Examples:
+  lxc-dev-manager config get dev1 security.nesting
+  lxc-dev-manager config get dev1 limits.memory`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigGet,
+}
+
+var configShowJSON bool
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowJSON, "json", false, "Output JSON")
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadUnvalidated()
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return fmt.Errorf("no %s found in current directory", config.ConfigFile)
+	}
+
+	problems := cfg.Check()
+	if len(problems) == 0 {
+		fmt.Printf("✓ %s is valid (%d containers)\n", config.ConfigFile, len(cfg.Containers))
+		return nil
+	}
+
+	fmt.Printf("✗ %s has %d problem(s):\n", config.ConfigFile, len(problems))
+	for _, p := range problems {
+		fmt.Printf("  %s: %s\n", p.Field, p.Message)
+	}
+
+	// The problems are already listed; only the exit status is left to report
+	if cmd != nil {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+	}
+	return &exitCodeError{code: 1}
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	_, lxcName, err := requireContainer(args[0])
+	if err != nil {
+		return err
+	}
+
+	value, err := lxc.ConfigGet(lxcName, args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+// resolvedContainer is a container's configuration with defaults merged in
+type resolvedContainer struct {
+	Name          string               `json:"name"`
+	LXCName       string               `json:"lxc_name"`
+	Image         string               `json:"image"`
+	Ports         []config.PortMapping `json:"ports"`
+	User          string               `json:"user"`
+	Env           map[string]string    `json:"env"`
+	Limits        config.Limits        `json:"limits"`
+	Profile       string               `json:"profile,omitempty"`
+	ReadyStrategy string               `json:"ready_strategy,omitempty"`
+	Mounts        []config.Mount       `json:"mounts"`
+	Autostart     bool                 `json:"autostart"`
+	Nesting       bool                 `json:"nesting"`
+	WaitTimeout   int                  `json:"wait_timeout"` // Seconds
+	Setup         []string             `json:"setup"`
+
+	// Which settings come from the project defaults
+	inheritedPorts       bool
+	inheritedUser        bool
+	inheritedWaitTimeout bool
+}
+
+// resolvedConfig is the whole project with defaults merged into each container
+type resolvedConfig struct {
+	Project    string              `json:"project"`
+	Remote     string              `json:"remote,omitempty"`
+	Bind       string              `json:"bind"`
+	Containers []resolvedContainer `json:"containers"`
+}
+
+func resolveContainer(cfg *config.Config, name string) resolvedContainer {
+	c := cfg.Containers[name]
+
+	ports := cfg.GetPortMappings(name)
+	if ports == nil {
+		ports = []config.PortMapping{}
+	}
+	mounts := c.Mounts
+	if mounts == nil {
+		mounts = []config.Mount{}
+	}
+
+	return resolvedContainer{
+		Name:           name,
+		LXCName:        cfg.GetLXCName(name),
+		Image:          c.Image,
+		Ports:          ports,
+		User:           cfg.GetUser(name).Name,
+		Env:            cfg.GetEnv(name),
+		Limits:         cfg.GetLimits(name),
+		Profile:        c.Profile,
+		ReadyStrategy:  c.ReadyStrategy,
+		Mounts:         mounts,
+		Autostart:      cfg.GetAutostart(name),
+		Nesting:        cfg.NestingEnabled(),
+		WaitTimeout:    int(resolveWaitTimeout(cfg, name, 0).Seconds()),
+		Setup:          cfg.GetSetup(name),
+		inheritedPorts: len(c.Ports) == 0 && len(ports) > 0,
+		inheritedUser:  c.User.Name == "",
+
+		inheritedWaitTimeout: c.WaitTimeout == 0,
+	}
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	format := formatTable
+	if configShowJSON {
+		format = formatJSON
+	}
+
+	if len(args) == 1 {
+		cfg, _, err := requireContainer(args[0])
+		if err != nil {
+			return err
+		}
+		result := resolveContainer(cfg, args[0])
+		return formatOutput(format, result, func() {
+			printResolvedContainer(result)
+		})
+	}
+
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	bind := cfg.Defaults.Bind
+	if bind == "" {
+		bind = proxy.DefaultBindAddr
+	}
+	result := resolvedConfig{
+		Project:    cfg.Project,
+		Remote:     cfg.Defaults.Remote,
+		Bind:       bind,
+		Containers: []resolvedContainer{},
+	}
+	names := make([]string, 0, len(cfg.Containers))
+	for name := range cfg.Containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result.Containers = append(result.Containers, resolveContainer(cfg, name))
+	}
+
+	return formatOutput(format, result, func() {
+		remote := result.Remote
+		if remote == "" {
+			remote = "(local)"
+		}
+		fmt.Printf("Project: %s\n", result.Project)
+		fmt.Printf("Remote: %s\n", remote)
+		fmt.Printf("Proxy bind: %s\n", result.Bind)
+		for _, c := range result.Containers {
+			fmt.Println()
+			printResolvedContainer(c)
+		}
+	})
+}
+
+func printResolvedContainer(c resolvedContainer) {
+	inherited := func(value string, fromDefaults bool) string {
+		if fromDefaults {
+			return value + " (default)"
+		}
+		return value
+	}
+
+	fmt.Printf("Container: %s\n", c.Name)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  lxc_name:\t%s\n", c.LXCName)
+	fmt.Fprintf(w, "  image:\t%s\n", c.Image)
+	fmt.Fprintf(w, "  ports:\t%s\n", inherited(formatPorts(c.Ports), c.inheritedPorts))
+	fmt.Fprintf(w, "  user:\t%s\n", inherited(c.User, c.inheritedUser))
+	fmt.Fprintf(w, "  limits.cpu:\t%s\n", orDash(c.Limits.CPU))
+	fmt.Fprintf(w, "  limits.memory:\t%s\n", orDash(c.Limits.Memory))
+	if c.Autostart {
+		fmt.Fprintln(w, "  autostart:\ton")
+	}
+	if !c.Nesting {
+		fmt.Fprintln(w, "  nesting:\toff")
+	}
+	fmt.Fprintf(w, "  wait_timeout:\t%s\n", inherited(fmt.Sprintf("%ds", c.WaitTimeout), c.inheritedWaitTimeout))
+	if c.Profile != "" {
+		fmt.Fprintf(w, "  profile:\t%s\n", c.Profile)
+	}
+	if c.ReadyStrategy != "" {
+		fmt.Fprintf(w, "  ready_strategy:\t%s\n", c.ReadyStrategy)
+	}
+	for _, m := range c.Mounts {
+		fmt.Fprintf(w, "  mount:\t%s\n", m)
+	}
+	for _, command := range c.Setup {
+		fmt.Fprintf(w, "  setup:\t%s\n", command)
+	}
+
+	keys := make([]string, 0, len(c.Env))
+	for k := range c.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "  env.%s:\t%s\n", k, c.Env[k])
+	}
+	w.Flush()
+}
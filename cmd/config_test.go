@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate_Valid(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: myapp
+defaults:
+  ports: [5173, 8000]
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      initial-state:
+        created_at: "2024-01-15T10:30:00Z"
+`)
+
+	out := env.captureStdout(func() {
+		if err := runConfigValidate(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "is valid") {
+		t.Errorf("expected success message, got:\n%s", out)
+	}
+}
+
+func TestConfigValidate_ReportsAllProblems(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: myapp
+defaults:
+  ports: [70000]
+containers:
+  dev1:
+    image: ""
+  dev2:
+    image: ubuntu
+    limits:
+      memory: lots
+    snapshots:
+      snap1:
+        created_at: yesterday
+`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runConfigValidate(nil, nil)
+	})
+
+	var exitErr *exitCodeError
+	if !errors.As(err, &exitErr) || exitErr.code != 1 {
+		t.Fatalf("expected exit code 1, got: %v", err)
+	}
+
+	for _, want := range []string{
+		"defaults.ports:",
+		"containers.dev1.image: image cannot be empty",
+		"containers.dev2.limits:",
+		"containers.dev2.snapshots.snap1.created_at:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestConfigValidate_BlankSetupCommand(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: myapp
+defaults:
+  setup:
+    - ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+    setup:
+      - apt-get update
+      - "  "
+`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runConfigValidate(nil, nil)
+	})
+
+	var exitErr *exitCodeError
+	if !errors.As(err, &exitErr) || exitErr.code != 1 {
+		t.Fatalf("expected exit code 1, got: %v", err)
+	}
+	for _, want := range []string{"defaults.setup", "containers.dev1.setup"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "is valid") {
+		t.Errorf("blank setup commands should not pass validation:\n%s", out)
+	}
+}
+
+func TestConfigValidate_InvalidYAML(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig("containers: [not: a map")
+
+	err := runConfigValidate(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid YAML") {
+		t.Errorf("expected YAML error, got: %v", err)
+	}
+}
+
+func TestConfigValidate_NoConfig(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runConfigValidate(nil, nil); err == nil {
+		t.Fatal("expected error without containers.yaml")
+	}
+}
+
+const configShowYAML = `project: myapp
+defaults:
+  ports: [5173, 8000]
+  user:
+    name: dev
+  env:
+    NODE_ENV: development
+    LOG_LEVEL: info
+  limits:
+    cpu: "2"
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: debian:12
+    ports: ["3000:80"]
+    user:
+      name: alice
+    env:
+      LOG_LEVEL: debug
+    limits:
+      memory: 1GB
+`
+
+func withConfigShowJSON(t *testing.T) {
+	t.Helper()
+	configShowJSON = true
+	t.Cleanup(func() { configShowJSON = false })
+}
+
+func TestConfigShow_ContainerUsesDefaults(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(configShowYAML)
+	withConfigShowJSON(t)
+
+	out := env.captureStdout(func() {
+		if err := runConfigShow(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	var got resolvedContainer
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.LXCName != "myapp-dev1" || got.User != "dev" {
+		t.Errorf("unexpected container: %+v", got)
+	}
+	if formatPorts(got.Ports) != "5173,8000" {
+		t.Errorf("ports = %v, want defaults", got.Ports)
+	}
+	if got.Env["NODE_ENV"] != "development" || got.Env["LOG_LEVEL"] != "info" {
+		t.Errorf("env = %v, want defaults", got.Env)
+	}
+	if got.Limits.CPU != "2" || got.Limits.Memory != "" {
+		t.Errorf("limits = %+v", got.Limits)
+	}
+}
+
+func TestConfigShow_ContainerOverrides(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(configShowYAML)
+	withConfigShowJSON(t)
+
+	out := env.captureStdout(func() {
+		if err := runConfigShow(nil, []string{"dev2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	var got resolvedContainer
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.User != "alice" {
+		t.Errorf("user = %q, want alice", got.User)
+	}
+	if formatPorts(got.Ports) != "3000:80" {
+		t.Errorf("ports = %v, want override", got.Ports)
+	}
+	// Per-container env wins, other defaults are still merged in
+	if got.Env["LOG_LEVEL"] != "debug" || got.Env["NODE_ENV"] != "development" {
+		t.Errorf("env = %v", got.Env)
+	}
+	// Each limit falls back to the default separately
+	if got.Limits.CPU != "2" || got.Limits.Memory != "1GB" {
+		t.Errorf("limits = %+v", got.Limits)
+	}
+}
+
+func TestConfigShow_CreateSettings(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: myapp
+defaults:
+  nesting: false
+  wait_timeout: 120
+  setup:
+    - apt-get update
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+    wait_timeout: 300
+    setup:
+      - npm ci
+`)
+	withConfigShowJSON(t)
+
+	out := env.captureStdout(func() {
+		if err := runConfigShow(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	var got resolvedConfig
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	dev1, dev2 := got.Containers[0], got.Containers[1]
+	if dev1.Nesting || dev2.Nesting {
+		t.Error("expected nesting off from defaults.nesting")
+	}
+	if dev1.WaitTimeout != 120 || dev2.WaitTimeout != 300 {
+		t.Errorf("wait_timeout = %d, %d; want 120, 300", dev1.WaitTimeout, dev2.WaitTimeout)
+	}
+	if strings.Join(dev1.Setup, ";") != "apt-get update" || strings.Join(dev2.Setup, ";") != "apt-get update;npm ci" {
+		t.Errorf("setup = %v, %v", dev1.Setup, dev2.Setup)
+	}
+}
+
+func TestConfigShow_WaitTimeoutFallback(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(configShowYAML)
+
+	out := env.captureStdout(func() {
+		if err := runConfigShow(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "60s (default)") {
+		t.Errorf("expected the built-in wait timeout, got:\n%s", out)
+	}
+	if strings.Contains(out, "nesting:") || strings.Contains(out, "setup:") {
+		t.Errorf("nesting on and no setup should not be listed, got:\n%s", out)
+	}
+}
+
+func TestConfigShow_TableMarksDefaults(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(configShowYAML)
+
+	out := env.captureStdout(func() {
+		if err := runConfigShow(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for _, want := range []string{
+		"Project: myapp",
+		"Proxy bind: 127.0.0.1",
+		"Container: dev1",
+		"5173,8000 (default)",
+		"dev (default)",
+		"Container: dev2",
+		"3000:80\n",
+		"alice\n",
+		"env.LOG_LEVEL:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "Container: dev1") > strings.Index(out, "Container: dev2") {
+		t.Error("containers should be sorted by name")
+	}
+}
+
+func TestConfigShow_ContainerNotFound(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(configShowYAML)
+
+	err := runConfigShow(nil, []string{"nope"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestConfigGet_PrintsValue(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("config get dev1 security.nesting", "true\n")
+
+	out := env.captureStdout(func() {
+		if err := runConfigGet(nil, []string{"dev1", "security.nesting"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if out != "true\n" {
+		t.Errorf("expected %q, got %q", "true\n", out)
+	}
+}
+
+func TestConfigGet_UnsetKey(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("config get dev1 limits.memory", "")
+
+	out := env.captureStdout(func() {
+		if err := runConfigGet(nil, []string{"dev1", "limits.memory"}); err != nil {
+			t.Fatalf("unset key should not be an error: %v", err)
+		}
+	})
+
+	if out != "\n" {
+		t.Errorf("expected an empty line, got %q", out)
+	}
+}
+
+func TestConfigGet_ContainerNotFound(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	err := runConfigGet(nil, []string{"nope", "security.nesting"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
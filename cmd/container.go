@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"os"
+	"strings"
 	"time"
 
+	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 	"lxc-dev-manager/internal/validation"
 
@@ -22,21 +28,62 @@ the actual LXC container will be named "webapp-dev1".`,
 }
 
 var containerCreateCmd = &cobra.Command{
-	Use:   "create <name> <image>",
+	Use:   "create <name> [image]",
 	Short: "Create a new container in the current project",
 	Long: `Create a new container from an image and configure it for development.
 
+The image can be omitted if defaults.image is set in containers.yaml.
+Use --clone-from <container> instead of an image to start from a copy of
+another container in the project (its snapshots are left behind).
+Use --from <container> to give the new container the ports, env and user
+settings of another one, e.g. when launching an image made from it.
+
+Remote images are downloaded only when LXD has no cached copy
+(--image-pull-policy if-not-present). Use --image-pull-policy always to
+download a fresh copy first, or never to launch a local alias or fingerprint
+and fail fast when the image is not there. On flaky networks, use
+--retries N to try the launch again after network errors and timeouts.
+
 The container will be set up with:
-  - Nesting enabled (Docker support)
+  - Nesting enabled (Docker support), unless --no-nesting or defaults.nesting: false
   - User with passwordless sudo (configurable in containers.yaml, default: dev/dev)
   - SSH enabled
+  - Optional CPU and memory limits (--cpu, --memory, or defaults.limits)
+  - Optional bind mounts of host directories (--mount SOURCE:TARGET)
+  - Optional LXD profile (--profile), e.g. docker or macvlan
+  - Optional start on host boot (--autostart, or defaults.autostart)
+
+Setup continues once the container is ready. By default that means
+cloud-init has finished; use --ready systemd for images without cloud-init,
+or --ready custom with --ready-command to wait for your own health check.
+The choice is saved as ready_strategy, and 'up' waits the same way.
+The wait gives up after --wait-timeout, or wait_timeout from containers.yaml
+(default 60s); a --wait-timeout is saved as the container's wait_timeout.
+
+Once SSH is enabled, the setup commands from containers.yaml
+(defaults.setup, then the container's own setup) run as root, in order.
+Setup stops at the first failing command; the container is kept.
 
 The container name will be prefixed with the project name in LXC.
 
+Creating a container that already exists is an error. In setup scripts, use
+--if-not-exists to succeed without changes instead, or --recreate to delete
+the existing container (and its snapshots) and create it again. Both create
+the container from its containers.yaml entry, which is kept as it is (only
+its snapshots are dropped); flags override the entry's values.
+
 Examples:
   lxc-dev-manager container create dev1 ubuntu:24.04
+  lxc-dev-manager container create dev1 ubuntu:24.04 --if-not-exists
+  lxc-dev-manager container create dev1 ubuntu:24.04 --cpu 2 --memory 2GiB
+  lxc-dev-manager container create dev1 images:debian/12 --ready systemd
+  lxc-dev-manager container create dev1 ubuntu:24.04 --mount ./src:/home/dev/app
+  lxc-dev-manager container create dev1 ubuntu:24.04 --image-pull-policy always
+  lxc-dev-manager container create dev2 --clone-from dev1
+  lxc-dev-manager container create dev2 my-dev1-image --from dev1
+  lxc-dev-manager container create dev3   # uses defaults.image
   lxc-dev-manager c create myapp my-custom-base`,
-	Args: cobra.ExactArgs(2),
+	Args: cobra.RangeArgs(1, 2),
 	RunE: runContainerCreate,
 }
 
@@ -45,14 +92,18 @@ var containerResetCmd = &cobra.Command{
 	Short: "Reset container to a snapshot",
 	Long: `Reset a container to a snapshot state.
 
-If no snapshot is specified, resets to 'initial-state'.
-Uses ZFS snapshots - the operation is instant.
+Deprecated: use 'container snapshot restore' instead.
+
+Changes made since the snapshot are lost, so reset asks for confirmation
+unless --yes is given.
 
 Examples:
   lxc-dev-manager container reset dev1                    # reset to initial-state
-  lxc-dev-manager container reset dev1 before-refactor    # reset to named snapshot`,
-	Args: cobra.RangeArgs(1, 2),
-	RunE: runContainerReset,
+  lxc-dev-manager container reset dev1 before-refactor    # reset to named snapshot
+  lxc-dev-manager container reset dev1 -y                 # don't ask`,
+	Deprecated: "use 'container snapshot restore' instead",
+	Args:       cobra.RangeArgs(1, 2),
+	RunE:       runContainerReset,
 }
 
 var containerCloneCmd = &cobra.Command{
@@ -63,35 +114,187 @@ var containerCloneCmd = &cobra.Command{
 By default, clones the current state of the container. Use --snapshot to clone
 from a specific snapshot instead.
 
+The copy is stateless by default: only the filesystem and config are copied.
+Use --stateful to also carry over the running state of a container with
+migration.stateful enabled (requires CRIU on the host).
+
 The cloned container will:
   - Have all the same data as the source
   - Get a new 'initial-state' snapshot
   - Be registered in the project config
+  - Be started, unless --no-start is given
 
 Examples:
   lxc-dev-manager container clone dev dev2                     # clone current state
-  lxc-dev-manager container clone dev dev2 --snapshot checkpoint  # clone from snapshot`,
+  lxc-dev-manager container clone dev dev2 --snapshot checkpoint  # clone from snapshot
+  lxc-dev-manager container clone dev dev2 --no-start          # leave the clone stopped`,
 	Args: cobra.ExactArgs(2),
 	RunE: runContainerClone,
 }
 
-var cloneSnapshot string
+var containerRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a container",
+	Long: `Rename a container in both LXC and the project config.
+
+LXC can only rename stopped containers, so a running container is stopped,
+renamed (snapshots included), and started again. Ports, user settings, and
+snapshot metadata in containers.yaml are moved to the new name.
+
+If the rename fails, the original container is restarted.
+
+With --copy, the container is copied to the new name (snapshots included)
+and the old one is deleted, instead of being renamed in place. A running
+container is stopped before the copy and the copy is started. --stateful
+copies a running container without stopping it, keeping its memory state;
+this needs CRIU on the LXD host and migration.stateful=true. If a copy
+fails part way, the error says which steps were done.
+
+Example:
+  lxc-dev-manager container rename dev1 api
+  lxc-dev-manager container rename dev1 api --copy
+  lxc-dev-manager container rename dev1 api --stateful`,
+	Args: cobra.ExactArgs(2),
+	RunE: runContainerRename,
+}
+
+var (
+	renameCopy     bool
+	renameStateful bool
+)
+
+var (
+	cloneSnapshot string
+	cloneStateful bool
+	cloneNoStart  bool
+)
+
+// readyTimeout bounds how long create and up wait for a container to be
+// ready when neither --wait-timeout nor wait_timeout is set
+var readyTimeout = 60 * time.Second
+
+// resolveWaitTimeout picks the ready timeout for a container:
+// flag > per-container wait_timeout > defaults.wait_timeout > readyTimeout
+func resolveWaitTimeout(cfg *config.Config, name string, flag time.Duration) time.Duration {
+	if flag > 0 {
+		return flag
+	}
+	if seconds := cfg.GetWaitTimeout(name); seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return readyTimeout
+}
+
+var (
+	createCPULimit     string
+	createMemoryLimit  string
+	createReady        string
+	createReadyCommand string
+	createMounts       []string
+	createProfile      string
+	createAutostart    bool
+	createRecreate     bool
+	createIfNotExists  bool
+	createCloneFrom    string
+	createWaitTimeout  time.Duration
+	createNoNesting    bool
+	createSSHKey       string
+	resetYes           bool
+	createFrom         string
+	createPullPolicy   string
+	createRetries      int
+)
+
+// launchRetryBackoff is the wait before the first --retries attempt; it
+// doubles after each failure
+var launchRetryBackoff = 2 * time.Second
+
+// Image pull policies for container create --image-pull-policy
+const (
+	pullAlways       = "always"
+	pullNever        = "never"
+	pullIfNotPresent = "if-not-present"
+)
 
 func init() {
 	rootCmd.AddCommand(containerCmd)
 	containerCmd.AddCommand(containerCreateCmd)
 	containerCmd.AddCommand(containerResetCmd)
 	containerCmd.AddCommand(containerCloneCmd)
+	containerCmd.AddCommand(containerRenameCmd)
+
+	// Reset flags
+	containerResetCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "Reset without asking for confirmation")
+
+	// Create flags
+	containerCreateCmd.Flags().StringVar(&createCPULimit, "cpu", "", "CPU limit (limits.cpu), e.g. 2")
+	containerCreateCmd.Flags().StringVar(&createMemoryLimit, "memory", "", "Memory limit (limits.memory), e.g. 2GiB")
+	containerCreateCmd.Flags().StringVar(&createReady, "ready", "", "How to detect the container is ready: cloud-init (default), systemd, custom")
+	containerCreateCmd.Flags().StringVar(&createReadyCommand, "ready-command", "", "Command that exits 0 once ready (with --ready custom)")
+	containerCreateCmd.Flags().StringVar(&createProfile, "profile", "", "LXD profile to apply, e.g. docker")
+	containerCreateCmd.Flags().StringArrayVar(&createMounts, "mount", nil, "Bind-mount a host directory, SOURCE:TARGET (repeatable)")
+	containerCreateCmd.Flags().BoolVar(&createAutostart, "autostart", false, "Start the container when the LXD host boots")
+	containerCreateCmd.Flags().BoolVar(&createRecreate, "recreate", false, "Delete the container first if it already exists")
+	containerCreateCmd.Flags().BoolVar(&createIfNotExists, "if-not-exists", false, "Do nothing if the container already exists")
+	containerCreateCmd.Flags().StringVar(&createCloneFrom, "clone-from", "", "Copy an existing container instead of launching an image")
+	containerCreateCmd.Flags().IntVar(&createRetries, "retries", 0, "Retry the launch this many times on network errors or timeouts")
+	containerCreateCmd.Flags().StringVar(&createPullPolicy, "image-pull-policy", pullIfNotPresent, "When to download the image: always, never, if-not-present")
+	containerCreateCmd.Flags().StringVar(&createFrom, "from", "", "Copy ports, env and user settings from another container in the config")
+	containerCreateCmd.Flags().BoolVar(&createNoNesting, "no-nesting", false, "Do not enable nesting (no Docker inside the container)")
+	containerCreateCmd.Flags().StringVar(&createSSHKey, "ssh-key", "", "Public key file to add to the user's authorized_keys, e.g. ~/.ssh/id_ed25519.pub")
+	containerCreateCmd.Flags().DurationVar(&createWaitTimeout, "wait-timeout", 0, "How long to wait for the container to be ready (default: wait_timeout, or 60s)")
+	containerCreateCmd.RegisterFlagCompletionFunc("clone-from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeContainerNames(cmd, nil, toComplete)
+	})
+
+	// Rename flags
+	containerRenameCmd.Flags().BoolVar(&renameCopy, "copy", false, "Copy the container to the new name and delete the old one")
+	containerRenameCmd.Flags().BoolVar(&renameStateful, "stateful", false, "Copy a running container with its memory state (implies --copy, needs CRIU)")
 
 	// Clone flags
 	containerCloneCmd.Flags().StringVarP(&cloneSnapshot, "snapshot", "s", "", "Clone from a specific snapshot instead of current state")
+	containerCloneCmd.Flags().BoolVar(&cloneStateful, "stateful", false, "Also copy the running state (needs migration.stateful)")
+	containerCloneCmd.Flags().BoolVar(&cloneNoStart, "no-start", false, "Leave the clone stopped")
 }
 
 func runContainerCreate(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	image := args[1]
+	var image string
+	if len(args) > 1 {
+		image = args[1]
+	}
 
-	// Validate container name first
+	switch {
+	case createCloneFrom != "" && image != "":
+		return fmt.Errorf("--clone-from replaces the image argument; pass one or the other")
+	case createCloneFrom != "":
+		if createCloneFrom == name {
+			return fmt.Errorf("container cannot be cloned from itself")
+		}
+	case image == "":
+		// Resolved from defaults.image once the config is loaded
+	default:
+		if err := validation.ValidateImageName(image); err != nil {
+			return err
+		}
+	}
+
+	if createRecreate && createIfNotExists {
+		return fmt.Errorf("--recreate and --if-not-exists cannot be used together")
+	}
+
+	if createRetries < 0 {
+		return fmt.Errorf("--retries must be 0 or more, got %d", createRetries)
+	}
+
+	if err := validation.ValidatePullPolicy(createPullPolicy); err != nil {
+		return err
+	}
+	if createCloneFrom != "" && createPullPolicy != pullIfNotPresent {
+		return fmt.Errorf("--image-pull-policy cannot be combined with --clone-from")
+	}
+
+	// Validate container name
 	if err := validation.ValidateContainerName(name); err != nil {
 		return fmt.Errorf("invalid container name: %w", err)
 	}
@@ -108,35 +311,216 @@ func runContainerCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Check if already exists in config
+	// Get full LXC name with prefix
+	lxcName := cfg.GetLXCName(name)
+	existsInLXC := lxc.Exists(lxcName)
+
+	// The entry the container is created from. --if-not-exists and --recreate
+	// keep an existing one (ports, env, setup and all), minus the snapshots of
+	// the instance it described.
+	var entry config.Container
 	if cfg.HasContainer(name) {
-		return fmt.Errorf("container '%s' already exists in config", name)
+		switch {
+		case createIfNotExists && existsInLXC:
+			printInfo("Container '%s' already exists, nothing to do\n", name)
+			return nil
+		case createIfNotExists, createRecreate:
+			entry = cfg.Containers[name]
+			entry.Snapshots = nil
+		default:
+			return fmt.Errorf("container '%s' already exists in config", name)
+		}
 	}
 
-	// Get full LXC name with prefix
-	lxcName := cfg.GetLXCName(name)
+	// Settings inherited with --from
+	if createFrom != "" {
+		if !cfg.HasContainer(createFrom) {
+			return fmt.Errorf("container '%s' not found in project config", createFrom)
+		}
+		source := cfg.Containers[createFrom]
+		entry.Ports = append(config.PortList(nil), source.Ports...)
+		entry.Env = maps.Clone(source.Env)
+		entry.User = source.User
+	}
+
+	// Stage the entry so the user, env, limits, setup and ~ in mount targets
+	// resolve from it below; the old one stays on disk until the new container is saved
+	cfg.Containers[name] = entry
+	setup := cfg.GetSetup(name)
+	waitTimeout := resolveWaitTimeout(cfg, name, createWaitTimeout)
+
+	if image == "" && createCloneFrom == "" {
+		image = entry.Image
+		if image == "" {
+			image = cfg.GetDefaultImage()
+		}
+		if image == "" {
+			return fmt.Errorf("an image is required: pass one, set defaults.image in %s, or use --clone-from <container>", config.ConfigFile)
+		}
+	}
+
+	// Resolve the source container for --clone-from
+	var sourceLXC string
+	if createCloneFrom != "" {
+		if !cfg.HasContainer(createCloneFrom) {
+			return fmt.Errorf("container '%s' not found in project config", createCloneFrom)
+		}
+		sourceLXC = cfg.GetLXCName(createCloneFrom)
+		if !lxc.Exists(sourceLXC) {
+			return fmt.Errorf("container '%s' does not exist in LXC (expected: %s)", createCloneFrom, sourceLXC)
+		}
+		image = createCloneFrom + ":cloned"
+	}
+
+	// Resolve resource limits: flags override project defaults
+	limits := cfg.GetLimits(name)
+	if createCPULimit != "" {
+		if err := validation.ValidateCPULimit(createCPULimit); err != nil {
+			return err
+		}
+		limits.CPU = createCPULimit
+	}
+	if createMemoryLimit != "" {
+		if err := validation.ValidateMemoryLimit(createMemoryLimit); err != nil {
+			return err
+		}
+		limits.Memory = createMemoryLimit
+	}
 
-	// Check if already exists in LXC
-	if lxc.Exists(lxcName) {
-		return fmt.Errorf("container '%s' already exists in LXC", lxcName)
+	// Flags override the kept entry's ready check and profile
+	ready, readyCommand := entry.ReadyStrategy, entry.ReadyCommand
+	if createReady != "" || createReadyCommand != "" {
+		ready, readyCommand = createReady, createReadyCommand
+	}
+	if err := validation.ValidateReadyStrategy(ready, readyCommand); err != nil {
+		return err
+	}
+
+	profile := entry.Profile
+	if createProfile != "" {
+		if err := validation.ValidateProfileName(createProfile); err != nil {
+			return err
+		}
+		if !validation.IsKnownProfile(createProfile) {
+			fmt.Printf("Warning: '%s' is not a built-in profile; it must already exist (see 'lxc profile list')\n", createProfile)
+		}
+		profile = createProfile
+	}
+
+	// Read the SSH public key before anything is created
+	sshKey := entry.SSHKey
+	if createSSHKey != "" {
+		if sshKey, err = readSSHPublicKey(createSSHKey); err != nil {
+			return err
+		}
 	}
 
-	// Launch container
-	fmt.Printf("Creating container '%s' (LXC: %s) from image '%s'...\n", name, lxcName, image)
-	if err := lxc.Launch(lxcName, image); err != nil {
+	// Resolve bind mounts (--mount replaces the kept entry's)
+	mounts := entry.Mounts
+	if len(createMounts) > 0 {
+		mounts = nil
+	}
+	for _, arg := range createMounts {
+		m, err := config.ParseMount(arg)
+		if err != nil {
+			return err
+		}
+		if m, err = resolveMount(cfg, name, m.Source, m.Target); err != nil {
+			return err
+		}
+		mounts = append(mounts, m)
+	}
+	if err := config.ValidateMounts(mounts); err != nil {
 		return err
 	}
 
-	// Enable nesting for Docker support
-	fmt.Println("Enabling nesting (Docker support)...")
-	if err := lxc.EnableNesting(lxcName); err != nil {
-		// Non-fatal, just warn
-		fmt.Printf("Warning: could not enable nesting: %v\n", err)
+	// Apply the pull policy before anything is deleted, so a missing image
+	// or failed download leaves an existing container alone
+	if createCloneFrom == "" {
+		if err := applyPullPolicy(image, createPullPolicy); err != nil {
+			return err
+		}
 	}
 
-	// Wait for container to be ready
-	fmt.Println("Waiting for container to be ready...")
-	if err := lxc.WaitForReady(lxcName, 60*time.Second); err != nil {
+	// Check if already exists in LXC (the config lock is held until the new one is saved)
+	if existsInLXC {
+		if !createRecreate {
+			return fmt.Errorf("container '%s' already exists in LXC", lxcName)
+		}
+		printInfo("Deleting existing container '%s'...\n", name)
+		if err := lxc.Delete(lxcName); err != nil {
+			return err
+		}
+	}
+
+	if createCloneFrom != "" {
+		printInfo("Creating container '%s' (LXC: %s) as a copy of '%s'...\n", name, lxcName, createCloneFrom)
+		// Without the source's snapshots: its initial-state would block the
+		// new container's own, and 'reset' would restore the source's state
+		if err := lxc.CopyWithOptions(sourceLXC, lxcName, lxc.CopyOptions{InstanceOnly: true}); err != nil {
+			return err
+		}
+		// Unlike launch, copy leaves the new container stopped
+		if err := lxc.Start(lxcName); err != nil {
+			return err
+		}
+	} else {
+		printInfo("Creating container '%s' (LXC: %s) from image '%s'...\n", name, lxcName, image)
+		if err := lxc.LaunchWithRetry(lxcName, image, createRetries+1, launchRetryBackoff); err != nil {
+			return err
+		}
+	}
+
+	// Apply the LXD profile before anything that depends on its config
+	if profile != "" {
+		printInfo("Applying profile '%s'...\n", profile)
+		if err := lxc.AddProfile(lxcName, profile); err != nil {
+			return err
+		}
+	}
+
+	// Enable nesting for Docker support (flag > project default)
+	nesting := "disabled"
+	if !createNoNesting && cfg.NestingEnabled() {
+		printInfo("Enabling nesting (Docker support)...\n")
+		nesting = "enabled"
+		if err := lxc.EnableNesting(lxcName); err != nil {
+			// Non-fatal, just warn
+			fmt.Printf("Warning: could not enable nesting: %v\n", err)
+			nesting = "failed (see warning above)"
+		}
+	}
+
+	// Apply resource limits (flags > project defaults)
+	if limits.CPU != "" || limits.Memory != "" {
+		printInfo("Applying resource limits...\n")
+		if err := lxc.SetLimits(lxcName, limits.CPU, limits.Memory); err != nil {
+			return err
+		}
+	}
+
+	// Bind-mount host directories
+	if len(mounts) > 0 {
+		printInfo("Mounting %d host path(s)...\n", len(mounts))
+		if err := applyMounts(lxcName, mounts); err != nil {
+			return err
+		}
+	}
+
+	// Start on host boot (flag > project default)
+	autostart := createAutostart || cfg.Defaults.Autostart
+	if autostart {
+		printInfo("Enabling autostart...\n")
+		if err := lxc.ConfigSet(lxcName, "boot.autostart", "true"); err != nil {
+			return err
+		}
+	}
+
+	// Wait for container to be ready (Ctrl+C aborts the wait)
+	printInfo("Waiting for container to be ready...\n")
+	ctx, stop := interruptContext()
+	defer stop()
+	if err := lxc.WaitForReady(ctx, lxcName, lxc.ReadyStrategy(ready), readyCommand, waitTimeout); err != nil {
 		return err
 	}
 
@@ -144,17 +528,32 @@ func runContainerCreate(cmd *cobra.Command, args []string) error {
 	user := cfg.GetUser(name)
 
 	// Set up user
-	fmt.Printf("Setting up '%s' user...\n", user.Name)
+	printInfo("Setting up '%s' user...\n", user.Name)
 	if err := lxc.SetupUser(lxcName, user.Name, user.Password); err != nil {
 		return fmt.Errorf("failed to set up user: %w", err)
 	}
 
+	// Apply environment variables (defaults, merged with per-container overrides)
+	if env := cfg.GetEnv(name); len(env) > 0 {
+		printInfo("Setting %d environment variable(s)...\n", len(env))
+		if err := lxc.SetEnv(lxcName, env); err != nil {
+			return fmt.Errorf("failed to set environment: %w", err)
+		}
+	}
+
 	// Enable SSH
-	fmt.Println("Enabling SSH...")
+	printInfo("Enabling SSH...\n")
 	if err := lxc.EnableSSH(lxcName); err != nil {
 		return fmt.Errorf("failed to enable SSH: %w", err)
 	}
 
+	if sshKey != "" {
+		printInfo("Adding SSH key for '%s'...\n", user.Name)
+		if err := lxc.DeploySSHKey(lxcName, user.Name, sshKey); err != nil {
+			return fmt.Errorf("failed to deploy SSH key: %w", err)
+		}
+	}
+
 	// Get IP
 	ip, err := lxc.GetIP(lxcName)
 	if err != nil {
@@ -162,13 +561,44 @@ func runContainerCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add to config with short name
-	cfg.AddContainer(name, image)
+	container := entry
+	container.Image = image
+	if createCPULimit != "" {
+		container.Limits.CPU = createCPULimit
+	}
+	if createMemoryLimit != "" {
+		container.Limits.Memory = createMemoryLimit
+	}
+	container.ReadyStrategy = ready
+	container.ReadyCommand = readyCommand
+	container.Mounts = mounts
+	container.Profile = profile
+	if createWaitTimeout > 0 {
+		container.WaitTimeout = int(math.Ceil(createWaitTimeout.Seconds()))
+	}
+	container.SSHKey = sshKey
+	cfg.Containers[name] = container
+	if createAutostart {
+		cfg.SetAutostart(name, true)
+	}
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
+	// Run the user's setup commands; the container is kept if one fails
+	if len(setup) > 0 {
+		printInfo("Running %d setup command(s)...\n", len(setup))
+		for i, command := range setup {
+			printInfo("  [%d/%d] %s\n", i+1, len(setup), command)
+			if err := lxc.ExecScript(lxcName, command); err != nil {
+				fmt.Printf("\nContainer '%s' was created but not fully set up (no initial-state snapshot was taken)\n", name)
+				return fmt.Errorf("setup command %d failed: %s: %w", i+1, command, err)
+			}
+		}
+	}
+
 	// Create initial snapshot for reset (instant with ZFS)
-	fmt.Println("Creating initial state snapshot...")
+	printInfo("Creating initial state snapshot...\n")
 	if err := lxc.Snapshot(lxcName, "initial-state"); err != nil {
 		fmt.Printf("Warning: could not create initial snapshot: %v\n", err)
 	} else {
@@ -177,15 +607,79 @@ func runContainerCreate(cmd *cobra.Command, args []string) error {
 		cfg.Save()
 	}
 
-	fmt.Printf("\nContainer '%s' created successfully!\n", name)
-	fmt.Printf("  LXC name: %s\n", lxcName)
-	fmt.Printf("  IP: %s\n", ip)
-	fmt.Printf("  User: %s / Password: %s\n", user.Name, user.Password)
-	fmt.Printf("\nConnect with: lxc-dev-manager ssh %s\n", name)
+	printInfo("\nContainer '%s' created successfully!\n", name)
+	printInfo("  LXC name: %s\n", lxcName)
+	printInfo("  IP: %s\n", ip)
+	printInfo("  User: %s / Password: %s\n", user.Name, user.Password)
+	printInfo("  Nesting: %s\n", nesting)
+	printInfo("\nConnect with: lxc-dev-manager ssh %s\n", name)
+
+	return nil
+}
 
+// applyPullPolicy checks or refreshes the image before launch.
+// if-not-present leaves caching to LXD, which downloads remote images only
+// when they are not cached. never launches from the local store only, so it
+// takes a local alias or fingerprint, which is what gets recorded in config;
+// always downloads a fresh copy of a remote image first.
+func applyPullPolicy(image, policy string) error {
+	server, _, remote := strings.Cut(image, ":")
+
+	switch policy {
+	case pullNever:
+		if remote {
+			return fmt.Errorf("--image-pull-policy never launches from the local image store: pass a local alias or fingerprint instead of '%s' (see 'image list --all'), or pull it first with 'image pull %s --alias <name>'", image, image)
+		}
+		if !lxc.ImageExists(image) {
+			return fmt.Errorf("image '%s' is not available locally and --image-pull-policy is never (see 'image list --all', or pull it with 'image pull')", image)
+		}
+	case pullAlways:
+		if !remote || server == "" {
+			return fmt.Errorf("--image-pull-policy always needs a remote image such as ubuntu:24.04, got '%s'", image)
+		}
+		printInfo("Pulling image '%s'...\n", image)
+		if err := lxc.PullImage(image); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// readSSHPublicKey reads and checks a public key file given to --ssh-key
+func readSSHPublicKey(path string) (string, error) {
+	data, err := os.ReadFile(hostPath(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to read SSH key: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if err := validation.ValidateSSHPublicKey(key); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// applyLimits sets limits.cpu and limits.memory on a container,
+// skipping values that are empty or already in effect.
+func applyLimits(lxcName string, limits config.Limits) error {
+	cpu, memory := limits.CPU, limits.Memory
+	if current, _ := lxc.ConfigGet(lxcName, "limits.cpu"); current == cpu {
+		cpu = ""
+	}
+	if current, _ := lxc.ConfigGet(lxcName, "limits.memory"); current == memory {
+		memory = ""
+	}
+	if cpu == "" && memory == "" {
+		return nil
+	}
+
+	printInfo("Applying resource limits...\n")
+	return lxc.SetLimits(lxcName, cpu, memory)
+}
+
+// resetStopTimeout is how long reset gives a running container to shut
+// down before restoring the snapshot
+const resetStopTimeout = 10 * time.Second
+
 func runContainerReset(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	snapshotName := "initial-state"
@@ -193,10 +687,12 @@ func runContainerReset(cmd *cobra.Command, args []string) error {
 		snapshotName = args[1]
 	}
 
-	_, lxcName, err := requireContainer(name)
+	// Hold the lock so two restores of the same container can't interleave
+	_, lxcName, lock, err := requireContainerWithLock(name)
 	if err != nil {
 		return err
 	}
+	defer lock.Release()
 
 	// Check if snapshot exists
 	if !lxc.SnapshotExists(lxcName, snapshotName) {
@@ -206,6 +702,14 @@ func runContainerReset(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("snapshot '%s' does not exist", snapshotName)
 	}
 
+	// Ask for confirmation unless --yes; nothing has been touched yet
+	if !resetYes {
+		if !confirmPrompt(fmt.Sprintf("Reset container '%s' to '%s'? Changes since the snapshot will be lost.", name, snapshotName)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
 	// Check if running
 	status, err := lxc.GetStatus(lxcName)
 	if err != nil {
@@ -215,21 +719,21 @@ func runContainerReset(cmd *cobra.Command, args []string) error {
 
 	// Stop if running
 	if wasRunning {
-		fmt.Printf("Stopping container '%s'...\n", name)
-		if err := lxc.Stop(lxcName); err != nil {
+		printInfo("Stopping container '%s'...\n", name)
+		if err := lxc.Stop(lxcName, resetStopTimeout); err != nil {
 			return err
 		}
 	}
 
 	// Restore from snapshot
-	fmt.Printf("Restoring container '%s' to snapshot '%s'...\n", name, snapshotName)
+	printInfo("Restoring container '%s' to snapshot '%s'...\n", name, snapshotName)
 	if err := lxc.Restore(lxcName, snapshotName); err != nil {
 		return err
 	}
 
 	// Restart if was running
 	if wasRunning {
-		fmt.Printf("Starting container '%s'...\n", name)
+		printInfo("Starting container '%s'...\n", name)
 		if err := lxc.Start(lxcName); err != nil {
 			return err
 		}
@@ -237,12 +741,12 @@ func runContainerReset(cmd *cobra.Command, args []string) error {
 		// Get new IP
 		ip, _ := lxc.GetIP(lxcName)
 		if ip != "" {
-			fmt.Printf("\nContainer '%s' reset to '%s' successfully! IP: %s\n", name, snapshotName, ip)
+			printInfo("\nContainer '%s' reset to '%s' successfully! IP: %s\n", name, snapshotName, ip)
 		} else {
-			fmt.Printf("\nContainer '%s' reset to '%s' successfully!\n", name, snapshotName)
+			printInfo("\nContainer '%s' reset to '%s' successfully!\n", name, snapshotName)
 		}
 	} else {
-		fmt.Printf("\nContainer '%s' reset to '%s' successfully! (kept stopped)\n", name, snapshotName)
+		printInfo("\nContainer '%s' reset to '%s' successfully! (kept stopped)\n", name, snapshotName)
 	}
 
 	return nil
@@ -289,16 +793,16 @@ func runContainerClone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Perform the clone
+	opts := lxc.CopyOptions{Snapshot: cloneSnapshot}
 	if cloneSnapshot != "" {
-		fmt.Printf("Cloning container '%s' (snapshot: %s) to '%s'...\n", sourceName, cloneSnapshot, newName)
-		if err := lxc.CopySnapshot(sourceLXC, cloneSnapshot, newLXC); err != nil {
-			return err
-		}
+		printInfo("Cloning container '%s' (snapshot: %s) to '%s'...\n", sourceName, cloneSnapshot, newName)
 	} else {
-		fmt.Printf("Cloning container '%s' to '%s'...\n", sourceName, newName)
-		if err := lxc.Copy(sourceLXC, newLXC); err != nil {
-			return err
-		}
+		// Snapshots are copied as they were taken; state only applies to the live container
+		opts.Stateless = !cloneStateful
+		printInfo("Cloning container '%s' to '%s'...\n", sourceName, newName)
+	}
+	if err := lxc.CopyWithOptions(sourceLXC, newLXC, opts); err != nil {
+		return err
 	}
 
 	// Get source container config to copy image info
@@ -314,7 +818,7 @@ func runContainerClone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create initial snapshot for reset
-	fmt.Println("Creating initial state snapshot...")
+	printInfo("Creating initial state snapshot...\n")
 	if err := lxc.Snapshot(newLXC, "initial-state"); err != nil {
 		fmt.Printf("Warning: could not create initial snapshot: %v\n", err)
 	} else {
@@ -323,30 +827,144 @@ func runContainerClone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Start the cloned container
-	fmt.Println("Starting cloned container...")
-	if err := lxc.Start(newLXC); err != nil {
-		fmt.Printf("Warning: could not start container: %v\n", err)
-	}
+	ip := "(stopped)"
+	if !cloneNoStart {
+		printInfo("Starting cloned container...\n")
+		if err := lxc.Start(newLXC); err != nil {
+			fmt.Printf("Warning: could not start container: %v\n", err)
+		}
 
-	// Get IP
-	ip, _ := lxc.GetIP(newLXC)
-	if ip == "" {
-		ip = "(pending)"
+		ip, _ = lxc.GetIP(newLXC)
+		if ip == "" {
+			ip = "(pending)"
+		}
 	}
 
 	// Get user config
 	user := cfg.GetUser(newName)
 
-	fmt.Printf("\nContainer '%s' cloned successfully!\n", newName)
-	fmt.Printf("  LXC name: %s\n", newLXC)
-	fmt.Printf("  Source: %s", sourceName)
+	printInfo("\nContainer '%s' cloned successfully!\n", newName)
+	printInfo("  LXC name: %s\n", newLXC)
+	printInfo("  Source: %s", sourceName)
 	if cloneSnapshot != "" {
-		fmt.Printf(" (snapshot: %s)", cloneSnapshot)
+		printInfo(" (snapshot: %s)", cloneSnapshot)
+	}
+	printInfo("\n")
+	printInfo("  IP: %s\n", ip)
+	printInfo("  User: %s\n", user.Name)
+	if !cloneNoStart {
+		printInfo("  SSH: ssh %s@%s\n", user.Name, ip)
+	} else {
+		printInfo("\nStart it with: lxc-dev-manager up %s\n", newName)
+	}
+
+	return nil
+}
+
+func runContainerRename(cmd *cobra.Command, args []string) error {
+	oldName := args[0]
+	newName := args[1]
+
+	// Validate new container name first
+	if err := validation.ValidateContainerName(newName); err != nil {
+		return fmt.Errorf("invalid container name: %w", err)
+	}
+
+	// Load config with lock to prevent race conditions
+	cfg, oldLXC, lock, err := requireContainerWithLock(oldName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// Validate combined name (project + container)
+	if err := validation.ValidateFullContainerName(cfg.Project, newName); err != nil {
+		return err
+	}
+
+	if oldName == newName {
+		return fmt.Errorf("container is already named '%s'", newName)
+	}
+
+	// Check if new name already exists
+	if cfg.HasContainer(newName) {
+		return fmt.Errorf("container '%s' already exists in config", newName)
+	}
+
+	newLXC := cfg.GetLXCName(newName)
+	if lxc.Exists(newLXC) {
+		return fmt.Errorf("container '%s' already exists in LXC", newLXC)
+	}
+
+	if renameCopy || renameStateful {
+		return copyRenameContainer(cfg, oldName, newName)
+	}
+
+	// Stop if running
+	status, err := lxc.GetStatus(oldLXC)
+	if err != nil {
+		return err
+	}
+	wasRunning := status == "RUNNING"
+
+	if wasRunning {
+		printInfo("Stopping container '%s'...\n", oldName)
+		if err := lxc.Stop(oldLXC, 0); err != nil {
+			return err
+		}
+	}
+
+	// LXC only renames stopped containers; snapshots move with the container
+	printInfo("Renaming container '%s' to '%s'...\n", oldName, newName)
+	if err := lxc.Rename(oldLXC, newLXC); err != nil {
+		if wasRunning {
+			if startErr := lxc.Start(oldLXC); startErr != nil {
+				fmt.Printf("Warning: could not restart '%s': %v\n", oldName, startErr)
+			}
+		}
+		return err
+	}
+
+	// Move config entry (ports, user, snapshots) to the new name
+	cfg.RenameContainer(oldName, newName)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config (container was renamed in LXC to '%s'): %w", newLXC, err)
+	}
+
+	// Restart if was running
+	if wasRunning {
+		printInfo("Starting container '%s'...\n", newName)
+		if err := lxc.Start(newLXC); err != nil {
+			fmt.Printf("Warning: could not start container: %v\n", err)
+		}
+	}
+
+	printInfo("\nContainer '%s' renamed to '%s'\n", oldName, newName)
+	printInfo("  LXC name: %s\n", newLXC)
+
+	return nil
+}
+
+// copyRenameContainer renames a container by copying it to the new name and
+// deleting the old one, then moves its config entry
+func copyRenameContainer(cfg *config.Config, oldName, newName string) error {
+	oldLXC, newLXC := cfg.GetLXCName(oldName), cfg.GetLXCName(newName)
+
+	printInfo("Copying container '%s' to '%s'...\n", oldName, newName)
+	opts := lxc.RenameOptions{Copy: true, Stateful: renameStateful}
+	if err := lxc.RenameWithOptions(oldLXC, newLXC, opts); err != nil {
+		if errors.Is(err, lxc.ErrStatefulUnsupported) {
+			return fmt.Errorf("cannot copy the state of '%s': install CRIU on the LXD host and run 'lxc config set %s migration.stateful=true', or rename without --stateful (%w)", oldName, oldLXC, err)
+		}
+		return err
+	}
+
+	cfg.RenameContainer(oldName, newName)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config (container was renamed in LXC to '%s'): %w", newLXC, err)
 	}
-	fmt.Println()
-	fmt.Printf("  IP: %s\n", ip)
-	fmt.Printf("  User: %s\n", user.Name)
-	fmt.Printf("  SSH: ssh %s@%s\n", user.Name, ip)
 
+	printInfo("\nContainer '%s' renamed to '%s'\n", oldName, newName)
+	printInfo("  LXC name: %s\n", newLXC)
 	return nil
 }
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var containerAutostartCmd = &cobra.Command{
+	Use:   "autostart <container> [on|off]",
+	Short: "Start a container when the LXD host boots",
+	Long: `Turn boot.autostart on or off for a container, or show its current
+setting when no state is given.
+
+The choice is saved as autostart in containers.yaml and overrides
+defaults.autostart.
+
+Examples:
+  lxc-dev-manager container autostart dev1 on
+  lxc-dev-manager container autostart dev1 off
+  lxc-dev-manager container autostart dev1`,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: []string{"on", "off"},
+	RunE:      runContainerAutostart,
+}
+
+func init() {
+	containerCmd.AddCommand(containerAutostartCmd)
+}
+
+// parseOnOff parses an on/off command argument
+func parseOnOff(arg string) (bool, error) {
+	switch arg {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid state %q (must be on or off)", arg)
+	}
+}
+
+func runContainerAutostart(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	if len(args) == 1 {
+		cfg, _, err := requireContainer(containerName)
+		if err != nil {
+			return err
+		}
+		state := "off"
+		if cfg.GetAutostart(containerName) {
+			state = "on"
+		}
+		fmt.Printf("Autostart for '%s' is %s\n", containerName, state)
+		return nil
+	}
+
+	enabled, err := parseOnOff(args[1])
+	if err != nil {
+		return err
+	}
+
+	cfg, lxcName, lock, err := requireContainerWithLock(containerName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if err := lxc.ConfigSet(lxcName, "boot.autostart", strconv.FormatBool(enabled)); err != nil {
+		return err
+	}
+
+	cfg.SetAutostart(containerName, enabled)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	printInfo("Autostart for '%s' turned %s\n", containerName, args[1])
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/config"
+)
+
+func TestContainerAutostart_On(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runContainerAutostart(nil, []string{"dev1", "on"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("config", "set", "dev1", "boot.autostart", "true") {
+		t.Errorf("expected boot.autostart=true, got calls: %v", env.mock.Calls)
+	}
+	if !strings.Contains(out, "Autostart for 'dev1' turned on") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	cfg, _ := config.Load()
+	if !cfg.GetAutostart("dev1") {
+		t.Error("expected autostart saved to config")
+	}
+}
+
+func TestContainerAutostart_OffOverridesDefaults(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+defaults:
+  autostart: true
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+
+	env.captureStdout(func() {
+		if err := runContainerAutostart(nil, []string{"dev1", "off"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("config", "set", "dev1", "boot.autostart", "false") {
+		t.Errorf("expected boot.autostart=false, got calls: %v", env.mock.Calls)
+	}
+	if !strings.Contains(env.readConfig(), "autostart: false") {
+		t.Errorf("expected explicit autostart: false in config:\n%s", env.readConfig())
+	}
+
+	cfg, _ := config.Load()
+	if cfg.GetAutostart("dev1") {
+		t.Error("per-container off should override defaults")
+	}
+}
+
+func TestContainerAutostart_Show(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+defaults:
+  autostart: true
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runContainerAutostart(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Autostart for 'dev1' is on") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if env.mock.HasCallPrefix("config", "set") {
+		t.Error("showing the setting should not change it")
+	}
+}
+
+func TestContainerAutostart_InvalidState(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	err := runContainerAutostart(nil, []string{"dev1", "maybe"})
+	if err == nil || !strings.Contains(err.Error(), "must be on or off") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if env.mock.HasCallPrefix("config", "set") {
+		t.Error("should not touch LXC for an invalid state")
+	}
+}
+
+func TestContainerAutostart_LXCError(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetError("config set dev1 boot.autostart", "permission denied")
+
+	if err := runContainerAutostart(nil, []string{"dev1", "on"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(env.readConfig(), "autostart") {
+		t.Error("config should not change when LXC fails")
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/lxc"
+	"lxc-dev-manager/internal/validation"
+
+	"github.com/spf13/cobra"
+)
+
+var containerEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage container environment variables",
+}
+
+var containerEnvSetCmd = &cobra.Command{
+	Use:   "set <container> KEY=VALUE [KEY=VALUE...]",
+	Short: "Set environment variables",
+	Long: `Set one or more environment variables for a container.
+
+Variables are saved to the project config and written to /etc/environment
+in the container if it is running.
+
+Examples:
+  lxc-dev-manager container env set dev1 NODE_ENV=development
+  lxc-dev-manager container env set dev1 TZ=UTC LANG=C.UTF-8`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runEnvSet,
+}
+
+var containerEnvListCmd = &cobra.Command{
+	Use:   "list <container>",
+	Short: "List environment variables",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEnvList,
+}
+
+var containerEnvUnsetCmd = &cobra.Command{
+	Use:   "unset <container> KEY [KEY...]",
+	Short: "Remove environment variables",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runEnvUnset,
+}
+
+func init() {
+	containerCmd.AddCommand(containerEnvCmd)
+	containerEnvCmd.AddCommand(containerEnvSetCmd)
+	containerEnvCmd.AddCommand(containerEnvListCmd)
+	containerEnvCmd.AddCommand(containerEnvUnsetCmd)
+}
+
+func runEnvSet(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	vars := make(map[string]string)
+	for _, arg := range args[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid argument '%s': expected KEY=VALUE", arg)
+		}
+		vars[key] = value
+	}
+	if err := validation.ValidateEnv(vars); err != nil {
+		return err
+	}
+
+	cfg, lxcName, lock, err := requireContainerWithLock(containerName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	for key, value := range vars {
+		cfg.SetEnv(containerName, key, value)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if err := applyEnv(cfg, containerName, lxcName); err != nil {
+		return err
+	}
+
+	printInfo("Set %d environment variable(s) on '%s'\n", len(vars), containerName)
+	return nil
+}
+
+func runEnvUnset(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	cfg, lxcName, lock, err := requireContainerWithLock(containerName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	for _, key := range args[1:] {
+		if !cfg.UnsetEnv(containerName, key) {
+			if _, ok := cfg.Defaults.Env[key]; ok {
+				return fmt.Errorf("'%s' is set in project defaults, not on container '%s'", key, containerName)
+			}
+			return fmt.Errorf("'%s' is not set on container '%s'", key, containerName)
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if err := applyEnv(cfg, containerName, lxcName); err != nil {
+		return err
+	}
+
+	printInfo("Removed %d environment variable(s) from '%s'\n", len(args)-1, containerName)
+	return nil
+}
+
+func runEnvList(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+	if !cfg.HasContainer(containerName) {
+		return fmt.Errorf("container '%s' not found in project config", containerName)
+	}
+
+	env := cfg.GetEnv(containerName)
+	if len(env) == 0 {
+		fmt.Printf("No environment variables set for '%s'\n", containerName)
+		return nil
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	own := cfg.Containers[containerName].Env
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVALUE\tSOURCE")
+	for _, k := range keys {
+		source := "defaults"
+		if _, ok := own[k]; ok {
+			source = "container"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", k, env[k], source)
+	}
+	return w.Flush()
+}
+
+// applyEnv rewrites /etc/environment in a running container from config.
+// Stopped containers are left alone; their environment is applied on next start.
+func applyEnv(cfg *config.Config, name, lxcName string) error {
+	status, err := lxc.GetStatus(lxcName)
+	if err != nil {
+		return err
+	}
+	if status != "RUNNING" {
+		printInfo("Container '%s' is not running; environment will be applied on next 'up'\n", name)
+		return nil
+	}
+
+	if err := lxc.SetEnv(lxcName, cfg.GetEnv(name)); err != nil {
+		return fmt.Errorf("failed to write environment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvSet_Running(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", true)
+
+	err := runEnvSet(nil, []string{"dev1", "NODE_ENV=development", "TZ=UTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := env.readConfig()
+	if !strings.Contains(cfg, "NODE_ENV: development") || !strings.Contains(cfg, "TZ: UTC") {
+		t.Errorf("expected env in config, got:\n%s", cfg)
+	}
+	if !env.mock.HasCallPrefix("exec", "test-dev1", "--", "bash", "-c") {
+		t.Error("expected /etc/environment to be written")
+	}
+}
+
+func TestEnvSet_Stopped(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", false)
+
+	err := runEnvSet(nil, []string{"dev1", "TZ=UTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(env.readConfig(), "TZ: UTC") {
+		t.Error("expected env in config")
+	}
+	if env.mock.HasCallPrefix("exec", "test-dev1") {
+		t.Error("should not exec in stopped container")
+	}
+}
+
+func TestEnvSet_InvalidArgument(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runEnvSet(nil, []string{"dev1", "NOVALUE"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "expected KEY=VALUE") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEnvSet_InvalidName(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runEnvSet(nil, []string{"dev1", "1BAD=x"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(env.readConfig(), "1BAD") {
+		t.Error("invalid variable should not be saved")
+	}
+}
+
+func TestEnvSet_ContainerNotFound(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	err := runEnvSet(nil, []string{"dev1", "TZ=UTC"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEnvUnset_Success(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    env:
+      TZ: UTC
+      DEBUG: "1"
+`)
+	env.setContainerExists("test-dev1", true)
+
+	err := runEnvUnset(nil, []string{"dev1", "DEBUG"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := env.readConfig()
+	if strings.Contains(cfg, "DEBUG") {
+		t.Error("expected DEBUG to be removed from config")
+	}
+	if !strings.Contains(cfg, "TZ: UTC") {
+		t.Error("expected TZ to be kept")
+	}
+	if !env.mock.HasCallPrefix("exec", "test-dev1", "--", "bash", "-c") {
+		t.Error("expected /etc/environment to be rewritten")
+	}
+}
+
+func TestEnvUnset_NotSet(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+defaults:
+  env:
+    TZ: UTC
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", true)
+
+	err := runEnvUnset(nil, []string{"dev1", "TZ"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "project defaults") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err = runEnvUnset(nil, []string{"dev1", "MISSING"})
+	if err == nil || !strings.Contains(err.Error(), "is not set") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEnvList(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+defaults:
+  env:
+    TZ: UTC
+    NODE_ENV: development
+containers:
+  dev1:
+    image: ubuntu:24.04
+    env:
+      NODE_ENV: test
+`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runEnvList(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out, "NODE_ENV  test") || !strings.Contains(out, "container") {
+		t.Errorf("expected container override in output:\n%s", out)
+	}
+	if !strings.Contains(out, "TZ") || !strings.Contains(out, "defaults") {
+		t.Errorf("expected default variable in output:\n%s", out)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var containerExecAllCmd = &cobra.Command{
+	Use:   "exec-all -- <command> [args...]",
+	Short: "Run a command in every running container",
+	Long: `Run the same command in every running container of the project.
+
+Containers run one after another and each one's output is printed under
+its name. Stopped containers are skipped. A failure does not stop the
+remaining containers; the command exits with an error if any failed.
+
+Example:
+  lxc-dev-manager container exec-all -- apt-get update
+  lxc-dev-manager container exec-all -- sh -c 'df -h /'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runContainerExecAll,
+}
+
+func init() {
+	containerCmd.AddCommand(containerExecAllCmd)
+}
+
+func runContainerExecAll(cmd *cobra.Command, args []string) error {
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	names := containerNames(cfg)
+	if len(names) == 0 {
+		printInfo("No containers defined in config\n")
+		return nil
+	}
+
+	var succeeded, skipped, failures []string
+	for _, name := range names {
+		lxcName := cfg.GetLXCName(name)
+
+		if !lxc.Exists(lxcName) {
+			skipped = append(skipped, fmt.Sprintf("%s (does not exist in LXC)", name))
+			continue
+		}
+		status, err := lxc.GetStatus(lxcName)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if status != "RUNNING" {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", name, strings.ToLower(status)))
+			continue
+		}
+
+		fmt.Printf("=== %s ===\n", name)
+		output, err := lxc.ExecOutput(lxcName, args...)
+		if output != "" {
+			fmt.Println(output)
+		}
+		if err != nil {
+			fmt.Printf("✗ %v\n", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		} else {
+			succeeded = append(succeeded, name)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("Summary:")
+	for _, name := range succeeded {
+		fmt.Printf("  ✓ %s\n", name)
+	}
+	for _, failure := range failures {
+		fmt.Printf("  ✗ %s\n", failure)
+	}
+	for _, skip := range skipped {
+		fmt.Printf("  - %s skipped\n", skip)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed for %d container(s):\n  %s", len(failures), strings.Join(failures, "\n  "))
+	}
+	if len(succeeded) == 0 {
+		return fmt.Errorf("no running containers")
+	}
+	return nil
+}
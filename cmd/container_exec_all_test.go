@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+const execAllConfig = `project: ""
+containers:
+  api:
+    image: ubuntu:24.04
+  db:
+    image: ubuntu:24.04
+  web:
+    image: ubuntu:24.04
+`
+
+func TestContainerExecAll(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(execAllConfig)
+	env.setContainerExists("api", true)
+	env.setContainerExists("db", true)
+	env.setContainerExists("web", true)
+	env.mock.SetOutput("exec api -- uname -r", "6.1.0-api")
+	env.mock.SetOutput("exec db -- uname -r", "6.1.0-db")
+	env.mock.SetOutput("exec web -- uname -r", "6.1.0-web")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runContainerExecAll(nil, []string{"uname", "-r"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"api", "db", "web"} {
+		if !env.mock.HasCall("exec", name, "--", "uname", "-r") {
+			t.Errorf("expected exec call for %s", name)
+		}
+		if !strings.Contains(out, "=== "+name+" ===\n6.1.0-"+name) {
+			t.Errorf("expected output grouped under %s, got:\n%s", name, out)
+		}
+		if !strings.Contains(out, "✓ "+name) {
+			t.Errorf("expected %s in summary, got:\n%s", name, out)
+		}
+	}
+}
+
+func TestContainerExecAll_SkipsStopped(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(execAllConfig)
+	env.setContainerExists("api", true)
+	env.setContainerExists("db", false)
+	env.setContainerNotExists("web")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runContainerExecAll(nil, []string{"true"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("exec", "api", "--", "true") {
+		t.Error("expected exec in running container")
+	}
+	if env.mock.HasCallPrefix("exec", "db") || env.mock.HasCallPrefix("exec", "web") {
+		t.Error("should not exec in stopped or missing containers")
+	}
+	if !strings.Contains(out, "db (stopped) skipped") || !strings.Contains(out, "web (does not exist in LXC) skipped") {
+		t.Errorf("expected skipped containers in summary, got:\n%s", out)
+	}
+}
+
+func TestContainerExecAll_AggregatesFailures(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(execAllConfig)
+	env.setContainerExists("api", true)
+	env.setContainerExists("db", true)
+	env.setContainerExists("web", true)
+	env.mock.SetError("exec api -- apt-get update", "network unreachable")
+	env.mock.SetError("exec web -- apt-get update", "lock held")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runContainerExecAll(nil, []string{"apt-get", "update"})
+	})
+	if err == nil {
+		t.Fatal("expected error when containers fail")
+	}
+	if !strings.Contains(err.Error(), "failed for 2 container(s)") ||
+		!strings.Contains(err.Error(), "api") || !strings.Contains(err.Error(), "web") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Failures don't stop the remaining containers
+	if !env.mock.HasCall("exec", "db", "--", "apt-get", "update") {
+		t.Error("expected db to run after api failed")
+	}
+	if !strings.Contains(out, "✓ db") {
+		t.Errorf("expected db success in summary, got:\n%s", out)
+	}
+}
+
+func TestContainerExecAll_NoneRunning(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("api", "ubuntu:24.04")
+	env.setContainerExists("api", false)
+
+	err := runContainerExecAll(nil, []string{"true"})
+	if err == nil || !strings.Contains(err.Error(), "no running containers") {
+		t.Errorf("expected no running containers error, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var containerInfoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "Show LXD's instance state for a container",
+	Long: `Show the instance state reported by the LXD API: status, network
+addresses and instance config.
+
+Use --json to print LXD's full response (as returned by
+'lxc query /1.0/instances/<name>'), e.g. for other tools.
+
+Examples:
+  lxc-dev-manager container info dev1
+  lxc-dev-manager container info dev1 --json | jq .state.network`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContainerInfo,
+}
+
+var containerInfoJSON bool
+
+func init() {
+	containerCmd.AddCommand(containerInfoCmd)
+	containerInfoCmd.Flags().BoolVar(&containerInfoJSON, "json", false, "Print LXD's raw JSON response")
+}
+
+func runContainerInfo(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	_, lxcName, err := requireContainer(name)
+	if err != nil {
+		return err
+	}
+
+	raw, err := lxc.QueryInstance(lxcName)
+	if err != nil {
+		return err
+	}
+
+	if containerInfoJSON {
+		fmt.Println(strings.TrimRight(string(raw), "\n"))
+		return nil
+	}
+
+	info, err := lxc.ParseInstanceInfo(raw)
+	if err != nil {
+		return err
+	}
+	printContainerInfo(name, lxcName, info)
+	return nil
+}
+
+func printContainerInfo(name, lxcName string, info lxc.InstanceInfo) {
+	fmt.Printf("Container: %s (LXC: %s)\n", name, lxcName)
+	fmt.Printf("  Status: %s\n", orDash(info.Status))
+	fmt.Printf("  Type: %s\n", orDash(info.Type))
+	created := info.CreatedAt
+	if t, err := time.Parse(time.RFC3339, created); err == nil {
+		created = t.Format("2006-01-02 15:04")
+	}
+	fmt.Printf("  Created: %s\n", orDash(created))
+
+	fmt.Println("\nAddresses:")
+	addresses := instanceAddresses(info)
+	if len(addresses) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, addr := range addresses {
+		fmt.Printf("  %s\n", addr)
+	}
+
+	fmt.Println("\nConfig:")
+	keys := instanceConfigKeys(info)
+	if len(keys) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, key := range keys {
+		fmt.Printf("  %s: %s\n", key, info.Config[key])
+	}
+}
+
+// instanceAddresses lists the global addresses of each interface except
+// loopback, as "eth0: 10.0.0.2 (inet)", sorted by interface
+func instanceAddresses(info lxc.InstanceInfo) []string {
+	if info.State == nil {
+		return nil
+	}
+
+	ifaces := make([]string, 0, len(info.State.Network))
+	for iface := range info.State.Network {
+		if iface != "lo" {
+			ifaces = append(ifaces, iface)
+		}
+	}
+	sort.Strings(ifaces)
+
+	var addresses []string
+	for _, iface := range ifaces {
+		for _, addr := range info.State.Network[iface].Addresses {
+			if addr.Scope != "global" {
+				continue
+			}
+			addresses = append(addresses, fmt.Sprintf("%s: %s (%s)", iface, addr.Address, addr.Family))
+		}
+	}
+	return addresses
+}
+
+// instanceConfigKeys returns the sorted config keys worth showing. LXD's
+// volatile.* keys are internal bookkeeping and left to --json.
+func instanceConfigKeys(info lxc.InstanceInfo) []string {
+	keys := make([]string, 0, len(info.Config))
+	for key := range info.Config {
+		if !strings.HasPrefix(key, "volatile.") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleInstanceJSON = `{
+  "name": "test-dev1",
+  "type": "container",
+  "status": "Running",
+  "created_at": "2024-01-15T10:30:00Z",
+  "config": {"limits.cpu": "2", "security.nesting": "true", "volatile.eth0.hwaddr": "00:16:3e:00:00:01"},
+  "state": {
+    "status": "Running",
+    "network": {
+      "eth0": {"addresses": [
+        {"family": "inet", "address": "10.87.167.42", "scope": "global"},
+        {"family": "inet6", "address": "fe80::1", "scope": "link"}
+      ]},
+      "lo": {"addresses": [{"family": "inet", "address": "127.0.0.1", "scope": "local"}]}
+    }
+  }
+}`
+
+func setupContainerInfo(t *testing.T, response string) *testEnv {
+	t.Helper()
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("query /1.0/instances/test-dev1?recursion=1", response)
+	t.Cleanup(func() { containerInfoJSON = false })
+	return env
+}
+
+func TestContainerInfo_Success(t *testing.T) {
+	env := setupContainerInfo(t, sampleInstanceJSON)
+
+	out := env.captureStdout(func() {
+		if err := runContainerInfo(nil, []string{"dev1"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	for _, want := range []string{
+		"Container: dev1 (LXC: test-dev1)",
+		"Status: Running",
+		"Type: container",
+		"Created: 2024-01-15 10:30",
+		"eth0: 10.87.167.42 (inet)",
+		"limits.cpu: 2",
+		"security.nesting: true",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"127.0.0.1", "fe80::1", "volatile."} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("did not expect %q in output, got:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestContainerInfo_JSON(t *testing.T) {
+	env := setupContainerInfo(t, sampleInstanceJSON)
+	containerInfoJSON = true
+
+	out := env.captureStdout(func() {
+		if err := runContainerInfo(nil, []string{"dev1"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if out != sampleInstanceJSON+"\n" {
+		t.Errorf("expected LXD's raw response, got:\n%s", out)
+	}
+}
+
+func TestContainerInfo_Stopped(t *testing.T) {
+	env := setupContainerInfo(t, `{"name": "test-dev1", "status": "Stopped", "config": {}, "state": {"status": "Stopped", "network": null}}`)
+
+	out := env.captureStdout(func() {
+		if err := runContainerInfo(nil, []string{"dev1"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Status: Stopped") || !strings.Contains(out, "Addresses:\n  (none)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestContainerInfo_InvalidJSON(t *testing.T) {
+	setupContainerInfo(t, "not json")
+
+	err := runContainerInfo(nil, []string{"dev1"})
+	if err == nil || !strings.Contains(err.Error(), "failed to parse instance info") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestContainerInfo_NotInConfig(t *testing.T) {
+	setupContainerInfo(t, sampleInstanceJSON)
+
+	err := runContainerInfo(nil, []string{"other"})
+	if err == nil || !strings.Contains(err.Error(), "not found in project config") {
+		t.Errorf("expected config error, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var containerInspectCmd = &cobra.Command{
+	Use:   "inspect <name>",
+	Short: "Show detailed information about a container",
+	Long: `Show full LXC info for a container together with its project config
+(image, ports, user, and snapshots).
+
+Use --format json for machine-readable output.
+
+Examples:
+  lxc-dev-manager container inspect dev1
+  lxc-dev-manager container inspect dev1 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContainerInspect,
+}
+
+var inspectFormat string
+
+func init() {
+	containerCmd.AddCommand(containerInspectCmd)
+	containerInspectCmd.Flags().StringVar(&inspectFormat, "format", formatTable, "Output format (table, json)")
+}
+
+// inspectResult combines LXC state with config metadata for a container
+type inspectResult struct {
+	lxc.ContainerInfo
+	LXCName   string               `json:"lxc_name"`
+	Image     string               `json:"image"`
+	Ports     []config.PortMapping `json:"ports"`
+	User      string               `json:"user"`
+	Snapshots []config.Snapshot    `json:"snapshots"`
+	Details   map[string]string    `json:"details"`
+	rawInfo   string
+}
+
+func runContainerInspect(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if err := validateFormat(inspectFormat); err != nil {
+		return err
+	}
+
+	cfg, lxcName, err := requireContainer(name)
+	if err != nil {
+		return err
+	}
+
+	raw, err := lxc.Info(lxcName)
+	if err != nil {
+		return err
+	}
+
+	details, err := lxc.ParseInfo(lxcName, raw)
+	if err != nil {
+		return err
+	}
+
+	ip, _ := lxc.GetIP(lxcName)
+
+	ports := cfg.GetPortMappings(name)
+	if ports == nil {
+		ports = []config.PortMapping{}
+	}
+
+	result := inspectResult{
+		ContainerInfo: lxc.ContainerInfo{
+			Name:   name,
+			Status: details.Fields["Status"],
+			IP:     ip,
+		},
+		LXCName:   lxcName,
+		Image:     cfg.Containers[name].Image,
+		Ports:     ports,
+		User:      cfg.GetUser(name).Name,
+		Snapshots: sortedSnapshots(cfg, name),
+		Details:   details.Fields,
+		rawInfo:   raw,
+	}
+
+	return formatOutput(inspectFormat, result, func() {
+		printInspect(result)
+	})
+}
+
+// sortedSnapshots returns config snapshot metadata for a container, sorted by name
+func sortedSnapshots(cfg *config.Config, name string) []config.Snapshot {
+	snapshots := []config.Snapshot{}
+	for snapName, snap := range cfg.GetSnapshots(name) {
+		snap.Name = snapName
+		snapshots = append(snapshots, snap)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots
+}
+
+func printInspect(r inspectResult) {
+	ip := r.IP
+	if ip == "" {
+		ip = "-"
+	}
+
+	fmt.Printf("Container: %s\n", r.Name)
+	fmt.Printf("  LXC name: %s\n", r.LXCName)
+	fmt.Printf("  Image: %s\n", r.Image)
+	fmt.Printf("  Status: %s\n", r.Status)
+	fmt.Printf("  IP: %s\n", ip)
+	fmt.Printf("  Ports: %s\n", formatPorts(r.Ports))
+	fmt.Printf("  User: %s\n", r.User)
+
+	fmt.Println("\nSnapshots:")
+	if len(r.Snapshots) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, snap := range r.Snapshots {
+		line := "  - " + snap.Name
+		if t, err := time.Parse(time.RFC3339, snap.CreatedAt); err == nil {
+			line += " (" + t.Format("2006-01-02 15:04") + ")"
+		}
+		if snap.Description != "" {
+			line += ": " + snap.Description
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println("\nLXC info:")
+	for _, line := range strings.Split(strings.TrimRight(r.rawInfo, "\n"), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+}
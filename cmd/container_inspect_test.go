@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/lxc"
+)
+
+const sampleLXCInfo = `Name: test-dev1
+Status: RUNNING
+Type: container
+Architecture: x86_64
+Created: 2024/01/15 10:30 UTC
+
+Resources:
+  Processes: 42
+`
+
+func TestContainerInspect_Success(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ports: [8080]
+`)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("info test-dev1", sampleLXCInfo)
+
+	out := env.captureStdout(func() {
+		if err := runContainerInspect(nil, []string{"dev1"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	for _, want := range []string{"LXC name: test-dev1", "Image: ubuntu:24.04", "Ports: 8080", "Architecture: x86_64"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestContainerInspect_JSONFormat(t *testing.T) {
+	env := setupTestEnv(t)
+	inspectFormat = "json"
+	t.Cleanup(func() { inspectFormat = formatTable })
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      initial-state:
+        description: Initial state
+        created_at: "2024-01-15T10:30:00Z"
+`)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("info test-dev1", sampleLXCInfo)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runContainerInspect(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if result["name"] != "dev1" || result["lxc_name"] != "test-dev1" {
+		t.Errorf("unexpected names: %v / %v", result["name"], result["lxc_name"])
+	}
+	if result["status"] != "RUNNING" || result["ip"] != "10.10.10.100" {
+		t.Errorf("unexpected status/ip: %v / %v", result["status"], result["ip"])
+	}
+	if result["user"] != "dev" {
+		t.Errorf("expected user dev, got %v", result["user"])
+	}
+	snapshots, ok := result["snapshots"].([]any)
+	if !ok || len(snapshots) != 1 {
+		t.Errorf("expected 1 snapshot, got %v", result["snapshots"])
+	}
+}
+
+func TestContainerInspect_ParseError(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("info test-dev1", "garbage")
+
+	err := runContainerInspect(nil, []string{"dev1"})
+	var parseErr *lxc.InfoParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected InfoParseError, got %v", err)
+	}
+}
+
+func TestContainerInspect_NotFound(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	err := runContainerInspect(nil, []string{"dev1"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var containerLogsCmd = &cobra.Command{
+	Use:   "logs <name>",
+	Short: "Show the systemd journal of a container",
+	Long: `Show systemd journal output from a container.
+
+By default, prints the last 100 lines. Use -f to follow new entries
+(press Ctrl+C to stop), and -s to only show one service.
+
+The top-level 'logs' command is a shortcut for this one.
+
+Examples:
+  lxc-dev-manager container logs dev1
+  lxc-dev-manager container logs dev1 -f
+  lxc-dev-manager container logs dev1 -s nginx --lines 500`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContainerLogs,
+}
+
+var (
+	logsFollow  bool
+	logsLines   int
+	logsService string
+)
+
+func init() {
+	containerCmd.AddCommand(containerLogsCmd)
+	addLogsFlags(containerLogsCmd)
+}
+
+// addLogsFlags registers the flags shared by 'logs' and 'container logs'
+func addLogsFlags(c *cobra.Command) {
+	c.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Stream new journal entries")
+	c.Flags().IntVarP(&logsLines, "lines", "n", 100, "Number of history lines to show")
+	c.Flags().StringVarP(&logsService, "service", "s", "", "Only show output of this systemd unit")
+}
+
+func runContainerLogs(cmd *cobra.Command, args []string) error {
+	return streamLogs(args[0], logsLines, logsFollow, logsService)
+}
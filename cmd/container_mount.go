@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var containerMountCmd = &cobra.Command{
+	Use:   "mount <container> <source> <target>",
+	Short: "Bind-mount a host directory into a container",
+	Long: `Mount a host directory (or file) into a container as an LXC disk device.
+
+Changes on either side are visible immediately, so you can edit code on the
+host and run it in the container without copying it with 'mv'. The mount is
+recorded in containers.yaml.
+
+The source is resolved to an absolute host path. The target is a path in
+the container; ~ expands to the container user's home directory.
+
+Examples:
+  lxc-dev-manager container mount dev1 ./src /home/dev/app
+  lxc-dev-manager container mount dev1 ~/datasets '~/data'`,
+	Args: cobra.ExactArgs(3),
+	RunE: runContainerMount,
+}
+
+var containerUnmountCmd = &cobra.Command{
+	Use:   "unmount <container> <target>",
+	Short: "Remove a bind-mount from a container",
+	Long: `Remove a bind-mount added with 'container mount' or 'container create --mount'.
+
+The host directory is left untouched; it is only detached from the
+container and removed from containers.yaml.
+
+Example:
+  lxc-dev-manager container unmount dev1 /home/dev/app`,
+	Args: cobra.ExactArgs(2),
+	RunE: runContainerUnmount,
+}
+
+func init() {
+	containerCmd.AddCommand(containerMountCmd)
+	containerCmd.AddCommand(containerUnmountCmd)
+}
+
+// resolveMount builds a mount from a host source and container target,
+// making the source absolute and expanding ~ in the target
+func resolveMount(cfg *config.Config, containerName, source, target string) (config.Mount, error) {
+	abs, err := filepath.Abs(hostPath(source))
+	if err != nil {
+		return config.Mount{}, fmt.Errorf("invalid mount source %q: %w", source, err)
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return config.Mount{}, fmt.Errorf("mount source '%s' does not exist", source)
+	}
+
+	m := config.Mount{Source: abs, Target: expandRemoteHome(cfg, containerName, target)}
+	if err := m.Validate(); err != nil {
+		return config.Mount{}, err
+	}
+	return m, nil
+}
+
+// applyMounts bind-mounts each mount into a container
+func applyMounts(lxcName string, mounts []config.Mount) error {
+	for _, m := range mounts {
+		if err := lxc.Mount(lxcName, m.Source, m.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runContainerMount(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	cfg, lxcName, lock, err := requireContainerWithLock(containerName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	m, err := resolveMount(cfg, containerName, args[1], args[2])
+	if err != nil {
+		return err
+	}
+	if err := cfg.AddMount(containerName, m); err != nil {
+		return err
+	}
+
+	if err := lxc.Mount(lxcName, m.Source, m.Target); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	printInfo("Mounted %s at %s in '%s'\n", m.Source, m.Target, containerName)
+	return nil
+}
+
+func runContainerUnmount(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	cfg, lxcName, lock, err := requireContainerWithLock(containerName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	target := expandRemoteHome(cfg, containerName, args[1])
+	m, err := cfg.RemoveMount(containerName, target)
+	if err != nil {
+		return err
+	}
+
+	if err := lxc.Unmount(lxcName, lxc.MountDeviceName(m.Target)); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	printInfo("Unmounted %s from '%s'\n", m.Target, containerName)
+	return nil
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/config"
+)
+
+func TestContainerMount_Success(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	os.Mkdir(filepath.Join(env.dir, "src"), 0755)
+
+	output := env.captureStdout(func() {
+		if err := runContainerMount(nil, []string{"dev1", "./src", "/home/dev/app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	source := filepath.Join(env.dir, "src")
+	if !env.mock.HasCall("config", "device", "add", "dev1", "mount-home-dev-app", "disk", "source="+source, "path=/home/dev/app") {
+		t.Errorf("expected device add, got calls: %v", env.mock.Calls)
+	}
+	if !strings.Contains(output, "Mounted "+source+" at /home/dev/app in 'dev1'") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	cfg, _ := config.Load()
+	mounts := cfg.Containers["dev1"].Mounts
+	if len(mounts) != 1 || mounts[0] != (config.Mount{Source: source, Target: "/home/dev/app"}) {
+		t.Errorf("expected mount recorded in config, got %+v", mounts)
+	}
+}
+
+func TestContainerMount_ExpandsHome(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	env.captureStdout(func() {
+		if err := runContainerMount(nil, []string{"dev1", env.dir, "~/data"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCallPrefix("config", "device", "add", "dev1", "mount-home-dev-data", "disk", "source="+env.dir, "path=/home/dev/data") {
+		t.Errorf("expected ~ to expand to the user's home, got calls: %v", env.mock.Calls)
+	}
+}
+
+func TestContainerMount_SourceNotExists(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	err := runContainerMount(nil, []string{"dev1", "./missing", "/app"})
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected missing source error, got %v", err)
+	}
+	if env.mock.HasCallPrefix("config", "device") {
+		t.Error("should not add a device for a missing source")
+	}
+}
+
+func TestContainerMount_RelativeTarget(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	err := runContainerMount(nil, []string{"dev1", env.dir, "app"})
+	if err == nil || !strings.Contains(err.Error(), "absolute path") {
+		t.Fatalf("expected relative target error, got %v", err)
+	}
+}
+
+func TestContainerMount_DuplicateTarget(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+    mounts:
+      - source: /srv/app
+        target: /app
+`)
+	env.setContainerExists("dev1", true)
+
+	err := runContainerMount(nil, []string{"dev1", env.dir, "/app"})
+	if err == nil || !strings.Contains(err.Error(), "duplicate mount target") {
+		t.Fatalf("expected duplicate target error, got %v", err)
+	}
+	if env.mock.HasCallPrefix("config", "device") {
+		t.Error("should not add a device for an already mounted target")
+	}
+}
+
+func TestContainerMount_DeviceError(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetError("config device add dev1", "permission denied")
+
+	err := runContainerMount(nil, []string{"dev1", env.dir, "/app"})
+	if err == nil || !strings.Contains(err.Error(), "failed to add device") {
+		t.Fatalf("expected device error, got %v", err)
+	}
+	if strings.Contains(env.readConfig(), "mounts") {
+		t.Error("mount should not be recorded when the device could not be added")
+	}
+}
+
+func TestContainerMount_ContainerNotInConfig(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	err := runContainerMount(nil, []string{"dev1", env.dir, "/app"})
+	if err == nil || !strings.Contains(err.Error(), "not found in project config") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestContainerCreate_WithMounts(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+	env.setLaunchSuccess()
+	env.setContainerNotExists("dev1")
+	os.Mkdir(filepath.Join(env.dir, "src"), 0755)
+
+	createMounts = []string{"./src:/home/dev/app"}
+	t.Cleanup(func() { createMounts = nil })
+
+	env.captureStdout(func() {
+		if err := runContainerCreate(nil, []string{"dev1", "ubuntu:24.04"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	source := filepath.Join(env.dir, "src")
+	if !env.mock.HasCall("config", "device", "add", "dev1", "mount-home-dev-app", "disk", "source="+source, "path=/home/dev/app") {
+		t.Errorf("expected device add, got calls: %v", env.mock.Calls)
+	}
+
+	cfg, _ := config.Load()
+	mounts := cfg.Containers["dev1"].Mounts
+	if len(mounts) != 1 || mounts[0].Source != source {
+		t.Errorf("expected mount recorded in config, got %+v", mounts)
+	}
+}
+
+func TestContainerCreate_InvalidMount(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+	env.setContainerNotExists("dev1")
+
+	createMounts = []string{"./missing:/app"}
+	t.Cleanup(func() { createMounts = nil })
+
+	err := runContainerCreate(nil, []string{"dev1", "ubuntu:24.04"})
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected missing source error, got %v", err)
+	}
+	if env.mock.HasCallPrefix("launch") {
+		t.Error("should not launch with an invalid mount")
+	}
+}
+
+func TestContainerUnmount_Success(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+    mounts:
+      - source: /home/me/app
+        target: /home/dev/app
+`)
+	env.setContainerExists("dev1", true)
+
+	output := env.captureStdout(func() {
+		if err := runContainerUnmount(nil, []string{"dev1", "/home/dev/app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("config", "device", "remove", "dev1", "mount-home-dev-app") {
+		t.Errorf("expected device remove, got calls: %v", env.mock.Calls)
+	}
+	if !strings.Contains(output, "Unmounted /home/dev/app from 'dev1'") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	cfg, _ := config.Load()
+	if len(cfg.Containers["dev1"].Mounts) != 0 {
+		t.Errorf("expected mount removed from config, got %+v", cfg.Containers["dev1"].Mounts)
+	}
+}
+
+func TestContainerUnmount_NotMounted(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	err := runContainerUnmount(nil, []string{"dev1", "/home/dev/app"})
+	if err == nil || !strings.Contains(err.Error(), "nothing is mounted at /home/dev/app") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if env.mock.HasCallPrefix("config", "device", "remove") {
+		t.Error("should not remove a device that is not in config")
+	}
+}
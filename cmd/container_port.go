@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/validation"
+
+	"github.com/spf13/cobra"
+)
+
+var containerPortCmd = &cobra.Command{
+	Use:   "port",
+	Short: "Manage forwarded ports",
+}
+
+var containerPortAddCmd = &cobra.Command{
+	Use:   "add <container> <port>",
+	Short: "Forward another port",
+	Long: `Add a port to a container's forwarded ports.
+
+The port is either a single port (8080) forwarded to the same port in the
+container, or LOCAL:REMOTE (8080:80). A container without its own ports
+inherits the project defaults; they are copied to the container first so
+they stay forwarded.
+
+The change applies the next time 'proxy' is started.
+
+Examples:
+  lxc-dev-manager container port add dev1 9000
+  lxc-dev-manager container port add dev1 8080:80`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPortAdd,
+}
+
+var containerPortRemoveCmd = &cobra.Command{
+	Use:   "remove <container> <port>",
+	Short: "Stop forwarding a port",
+	Long: `Remove a port from a container's forwarded ports.
+
+The port is matched on its local side, so both 8080 and 8080:80 remove a
+8080:80 mapping.
+
+Example:
+  lxc-dev-manager container port remove dev1 9000`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPortRemove,
+}
+
+var containerPortListCmd = &cobra.Command{
+	Use:   "list <container>",
+	Short: "List forwarded ports",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPortList,
+}
+
+func init() {
+	containerCmd.AddCommand(containerPortCmd)
+	containerPortCmd.AddCommand(containerPortAddCmd)
+	containerPortCmd.AddCommand(containerPortRemoveCmd)
+	containerPortCmd.AddCommand(containerPortListCmd)
+}
+
+// parsePortArg parses and validates a PORT or LOCAL:REMOTE argument
+func parsePortArg(arg string) (config.PortMapping, error) {
+	m, err := config.ParsePortMapping(arg)
+	if err != nil {
+		return config.PortMapping{}, err
+	}
+	if err := validation.ValidatePort(m.Local); err != nil {
+		return config.PortMapping{}, err
+	}
+	if err := validation.ValidatePort(m.Remote); err != nil {
+		return config.PortMapping{}, err
+	}
+	return m, nil
+}
+
+// defaultPort returns the project default mapping for a local port, if any
+func defaultPort(cfg *config.Config, local int) (config.PortMapping, bool) {
+	for _, m := range cfg.Defaults.Ports {
+		if m.Local == local {
+			return m, true
+		}
+	}
+	return config.PortMapping{}, false
+}
+
+func runPortAdd(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	m, err := parsePortArg(args[1])
+	if err != nil {
+		return err
+	}
+
+	cfg, lock, err := requireProjectWithLock()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if !cfg.HasContainer(containerName) {
+		return fmt.Errorf("container '%s' not found in project config", containerName)
+	}
+
+	if d, ok := defaultPort(cfg, m.Local); ok {
+		fmt.Printf("Warning: port %d is already in the project defaults (%s)\n", m.Local, d)
+	}
+
+	inherited := len(cfg.Containers[containerName].Ports) == 0 && len(cfg.Defaults.Ports) > 0
+	if err := cfg.AddPort(containerName, m); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if inherited {
+		printInfo("Copied project default ports (%s) to '%s'\n", formatPorts(cfg.Defaults.Ports), containerName)
+	}
+	printInfo("Port %s added to '%s'\n", m, containerName)
+	return nil
+}
+
+func runPortRemove(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	m, err := parsePortArg(args[1])
+	if err != nil {
+		return err
+	}
+
+	cfg, lock, err := requireProjectWithLock()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if !cfg.HasContainer(containerName) {
+		return fmt.Errorf("container '%s' not found in project config", containerName)
+	}
+
+	if err := cfg.RemovePort(containerName, m.Local); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if _, ok := defaultPort(cfg, m.Local); ok {
+		fmt.Printf("Warning: port %d is in the project defaults; it was only removed from '%s'\n", m.Local, containerName)
+	}
+	printInfo("Port %d removed from '%s'\n", m.Local, containerName)
+	return nil
+}
+
+func runPortList(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+	if !cfg.HasContainer(containerName) {
+		return fmt.Errorf("container '%s' not found in project config", containerName)
+	}
+
+	ports := cfg.GetPortMappings(containerName)
+	if len(ports) == 0 {
+		fmt.Printf("No ports forwarded for '%s'\n", containerName)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LOCAL\tREMOTE\tORIGIN")
+	for _, m := range ports {
+		origin := "container"
+		if d, ok := defaultPort(cfg, m.Local); ok && d == m {
+			origin = "defaults"
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\n", m.Local, m.Remote, origin)
+	}
+	return w.Flush()
+}
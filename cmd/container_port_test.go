@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/config"
+)
+
+func TestPortAdd_CopiesDefaults(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`defaults:
+  ports: [5173, 8000]
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+
+	if err := runPortAdd(nil, []string{"dev1", "9000"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	got := formatPorts(cfg.GetPortMappings("dev1"))
+	if got != "5173,8000,9000" {
+		t.Errorf("expected defaults plus new port, got %s", got)
+	}
+}
+
+func TestPortAdd_Remapping(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	if err := runPortAdd(nil, []string{"dev1", "8080:80"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(env.readConfig(), "- 8080:80") {
+		t.Errorf("expected remapping in config, got:\n%s", env.readConfig())
+	}
+}
+
+func TestPortAdd_WarnsWhenInDefaults(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`defaults:
+  ports: [8000]
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ports: [3000]
+`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runPortAdd(nil, []string{"dev1", "8000"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Warning: port 8000 is already in the project defaults") {
+		t.Errorf("expected defaults warning, got: %s", out)
+	}
+}
+
+func TestPortAdd_Duplicate(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu:24.04
+    ports: [3000]
+`)
+
+	err := runPortAdd(nil, []string{"dev1", "3000:80"})
+	if err == nil {
+		t.Fatal("expected error for duplicate local port")
+	}
+	if !strings.Contains(err.Error(), "already forwarded") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPortAdd_InvalidPort(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	for _, arg := range []string{"abc", "70000", "8080:0"} {
+		if err := runPortAdd(nil, []string{"dev1", arg}); err == nil {
+			t.Errorf("expected error for %q", arg)
+		}
+	}
+}
+
+func TestPortAdd_ContainerNotFound(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	err := runPortAdd(nil, []string{"missing", "9000"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestPortRemove(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu:24.04
+    ports: [3000, "8080:80"]
+`)
+
+	if err := runPortRemove(nil, []string{"dev1", "8080"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, _ := config.Load()
+	if got := formatPorts(cfg.GetPortMappings("dev1")); got != "3000" {
+		t.Errorf("expected only 3000 left, got %s", got)
+	}
+}
+
+func TestPortRemove_InheritedDefault(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`defaults:
+  ports: [5173, 8000]
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runPortRemove(nil, []string{"dev1", "8000"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Warning: port 8000 is in the project defaults") {
+		t.Errorf("expected defaults warning, got: %s", out)
+	}
+
+	cfg, _ := config.Load()
+	if got := formatPorts(cfg.GetPortMappings("dev1")); got != "5173" {
+		t.Errorf("expected only 5173 left, got %s", got)
+	}
+	if got := formatPorts(cfg.Defaults.Ports); got != "5173,8000" {
+		t.Errorf("defaults should be unchanged, got %s", got)
+	}
+}
+
+func TestPortRemove_NotForwarded(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runPortRemove(nil, []string{"dev1", "9999"})
+	if err == nil || !strings.Contains(err.Error(), "not forwarded") {
+		t.Errorf("expected not forwarded error, got %v", err)
+	}
+}
+
+func TestPortList_Origins(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`defaults:
+  ports: [8000]
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ports: [8000, "8080:80"]
+  dev2:
+    image: ubuntu:24.04
+`)
+
+	out := env.captureStdout(func() {
+		if err := runPortList(nil, []string{"dev1"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got:\n%s", out)
+	}
+	if f := strings.Fields(lines[1]); strings.Join(f, " ") != "8000 8000 defaults" {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+	if f := strings.Fields(lines[2]); strings.Join(f, " ") != "8080 80 container" {
+		t.Errorf("unexpected row: %q", lines[2])
+	}
+
+	out = env.captureStdout(func() {
+		if err := runPortList(nil, []string{"dev2"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "8000") || !strings.Contains(out, "defaults") {
+		t.Errorf("expected inherited default, got:\n%s", out)
+	}
+}
@@ -1,18 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 
 	"github.com/spf13/cobra"
 )
 
 var snapshotDescription string
+var snapshotListFormat string
+var snapshotDeleteDryRun bool
+var snapshotPruneKeep int
+var snapshotPruneDryRun bool
 
 var containerSnapshotCmd = &cobra.Command{
 	Use:   "snapshot",
@@ -36,24 +43,79 @@ Examples:
 var containerSnapshotListCmd = &cobra.Command{
 	Use:   "list <container>",
 	Short: "List snapshots for a container",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runSnapshotList,
+	Long: `List snapshots for a container.
+
+Examples:
+  lxc-dev-manager container snapshot list dev1
+  lxc-dev-manager container snapshot list dev1 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotList,
+}
+
+var containerSnapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <container> [snapshot]",
+	Short: "Restore a container to a snapshot",
+	Long: `Restore a container to a snapshot state.
+
+If no snapshot is specified, restores 'initial-state'.
+A running container is stopped, restored, and started again.
+Uses ZFS snapshots - the operation is instant.
+
+Changes made since the snapshot are lost, so restore asks for confirmation
+unless --yes is given.
+
+Examples:
+  lxc-dev-manager container snapshot restore dev1                    # restore initial-state
+  lxc-dev-manager container snapshot restore dev1 before-refactor    # restore named snapshot
+  lxc-dev-manager container snapshot restore dev1 -y                 # don't ask`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runContainerReset,
 }
 
 var containerSnapshotDeleteCmd = &cobra.Command{
 	Use:   "delete <container> <name>",
 	Short: "Delete a snapshot",
-	Args:  cobra.ExactArgs(2),
-	RunE:  runSnapshotDelete,
+	Long: `Delete a named snapshot of a container.
+
+Examples:
+  lxc-dev-manager container snapshot delete dev1 checkpoint
+  lxc-dev-manager container snapshot delete dev1 checkpoint --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSnapshotDelete,
+}
+
+var containerSnapshotPruneCmd = &cobra.Command{
+	Use:   "prune <container> --keep N",
+	Short: "Delete all but the newest snapshots",
+	Long: `Delete old snapshots of a container, keeping the newest N.
+
+Snapshots are ordered by their created_at time in containers.yaml.
+Snapshots that exist in LXC but have no recorded creation time are
+treated as newest, so they are pruned last. 'initial-state' is never
+deleted and does not count towards N.
+
+Examples:
+  lxc-dev-manager container snapshot prune dev1 --keep 3
+  lxc-dev-manager container snapshot prune dev1 --keep 0 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotPrune,
 }
 
 func init() {
 	containerCmd.AddCommand(containerSnapshotCmd)
 	containerSnapshotCmd.AddCommand(containerSnapshotCreateCmd)
 	containerSnapshotCmd.AddCommand(containerSnapshotListCmd)
+	containerSnapshotCmd.AddCommand(containerSnapshotRestoreCmd)
 	containerSnapshotCmd.AddCommand(containerSnapshotDeleteCmd)
+	containerSnapshotCmd.AddCommand(containerSnapshotPruneCmd)
 
 	containerSnapshotCreateCmd.Flags().StringVarP(&snapshotDescription, "description", "d", "", "Snapshot description")
+	containerSnapshotListCmd.Flags().StringVar(&snapshotListFormat, "format", formatTable, "Output format (table, json)")
+	containerSnapshotRestoreCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "Restore without asking for confirmation")
+	containerSnapshotDeleteCmd.Flags().BoolVar(&snapshotDeleteDryRun, "dry-run", false, "Show what would be deleted without deleting")
+	containerSnapshotPruneCmd.Flags().IntVar(&snapshotPruneKeep, "keep", -1, "Number of snapshots to keep, not counting initial-state")
+	containerSnapshotPruneCmd.Flags().BoolVar(&snapshotPruneDryRun, "dry-run", false, "Show what would be deleted without deleting")
+	containerSnapshotPruneCmd.MarkFlagRequired("keep")
 }
 
 func runSnapshotCreate(cmd *cobra.Command, args []string) error {
@@ -72,8 +134,11 @@ func runSnapshotCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("snapshot '%s' already exists", snapshotName)
 	}
 
-	fmt.Printf("Creating snapshot '%s'...\n", snapshotName)
+	printInfo("Creating snapshot '%s'...\n", snapshotName)
 	if err := lxc.Snapshot(lxcName, snapshotName); err != nil {
+		if errors.Is(err, lxc.ErrAlreadyExists) {
+			return fmt.Errorf("snapshot '%s' already exists", snapshotName)
+		}
 		return err
 	}
 
@@ -83,61 +148,102 @@ func runSnapshotCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("Snapshot '%s' created successfully!\n", snapshotName)
+	printInfo("Snapshot '%s' created successfully!\n", snapshotName)
 	return nil
 }
 
 func runSnapshotList(cmd *cobra.Command, args []string) error {
 	containerName := args[0]
 
-	cfg, lxcName, err := requireContainer(containerName)
-	if err != nil {
+	if err := validateFormat(snapshotListFormat); err != nil {
 		return err
 	}
 
-	// Get snapshots from LXC
-	lxcSnapshots, err := lxc.ListSnapshots(lxcName)
+	cfg, lxcName, err := requireContainer(containerName)
 	if err != nil {
 		return err
 	}
 
-	if len(lxcSnapshots) == 0 {
-		fmt.Println("No snapshots found.")
-		return nil
+	// Get snapshots from LXC, sorted by name
+	lxcSnapshots, err := lxc.ListSnapshotsWithMetadata(lxcName)
+	if err != nil {
+		return err
 	}
 
 	// Get metadata from config
 	configSnapshots := cfg.GetSnapshots(containerName)
 
-	// Sort snapshots by name
-	sort.Strings(lxcSnapshots)
+	snapshots := make([]config.Snapshot, 0, len(lxcSnapshots))
+	for _, detail := range lxcSnapshots {
+		snap := configSnapshots[detail.Name]
+		snap.Name = detail.Name
+		// Snapshots taken outside lxc-dev-manager have no config record
+		if snap.CreatedAt == "" && detail.Info != nil {
+			snap.CreatedAt = detail.Info.CreatedAt
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	if snapshotListFormat == formatJSON {
+		return formatOutput(formatJSON, snapshotEntries(snapshots, lxcSnapshots), nil)
+	}
+
+	return formatOutput(snapshotListFormat, snapshots, func() {
+		printSnapshotTable(snapshots)
+	})
+}
+
+// snapshotEntry is a snapshot in 'snapshot list --format json': config
+// metadata plus details from the LXD API, which are null if LXD could not
+// be queried for that snapshot
+type snapshotEntry struct {
+	config.Snapshot
+	Stateful *bool  `json:"stateful"`
+	Size     *int64 `json:"size"`
+}
+
+// snapshotEntries adds LXD's details to each snapshot. A snapshot LXD could
+// not be queried for keeps the extra fields null. details must be in the
+// same order as snapshots.
+func snapshotEntries(snapshots []config.Snapshot, details []lxc.SnapshotDetail) []snapshotEntry {
+	entries := make([]snapshotEntry, 0, len(snapshots))
+	for i, snap := range snapshots {
+		entry := snapshotEntry{Snapshot: snap}
+		if info := details[i].Info; info != nil {
+			entry.Stateful = &info.Stateful
+			entry.Size = &info.Size
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// printSnapshotTable prints snapshots as a human-readable table
+func printSnapshotTable(snapshots []config.Snapshot) {
+	if len(snapshots) == 0 {
+		fmt.Println("No snapshots found.")
+		return
+	}
 
-	// Print table
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(w, "NAME\tCREATED\tDESCRIPTION")
 
-	for _, name := range lxcSnapshots {
+	for _, snap := range snapshots {
 		created := "-"
 		description := "-"
-		if configSnapshots != nil {
-			if meta, ok := configSnapshots[name]; ok {
-				if meta.CreatedAt != "" {
-					// Parse and format nicely
-					t, err := time.Parse(time.RFC3339, meta.CreatedAt)
-					if err == nil {
-						created = t.Format("2006-01-02 15:04")
-					}
-				}
-				if meta.Description != "" {
-					description = meta.Description
-				}
+		if snap.CreatedAt != "" {
+			// Parse and format nicely
+			t, err := time.Parse(time.RFC3339, snap.CreatedAt)
+			if err == nil {
+				created = t.Format("2006-01-02 15:04")
 			}
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\n", name, created, description)
+		if snap.Description != "" {
+			description = snap.Description
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", snap.Name, created, description)
 	}
 	w.Flush()
-
-	return nil
 }
 
 func runSnapshotDelete(cmd *cobra.Command, args []string) error {
@@ -160,7 +266,15 @@ func runSnapshotDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("snapshot '%s' does not exist", snapshotName)
 	}
 
-	fmt.Printf("Deleting snapshot '%s'...\n", snapshotName)
+	if snapshotDeleteDryRun {
+		dryRunf("Would run: lxc delete %s/%s", lxc.InstanceRef(lxcName), snapshotName)
+		if _, ok := cfg.GetSnapshots(containerName)[snapshotName]; ok {
+			dryRunf("Would remove snapshot '%s' of '%s' from %s", snapshotName, containerName, config.ConfigFile)
+		}
+		return nil
+	}
+
+	printInfo("Deleting snapshot '%s'...\n", snapshotName)
 	if err := lxc.DeleteSnapshot(lxcName, snapshotName); err != nil {
 		return err
 	}
@@ -171,6 +285,104 @@ func runSnapshotDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("Snapshot '%s' deleted.\n", snapshotName)
+	printInfo("Snapshot '%s' deleted.\n", snapshotName)
+	return nil
+}
+
+// snapshotsToPrune returns the snapshots to delete to keep only the newest
+// keep, oldest first. Snapshots without a valid created_at in meta count as
+// newer than any dated one. initial-state is never selected.
+func snapshotsToPrune(names []string, meta map[string]config.Snapshot, keep int) []string {
+	type candidate struct {
+		name    string
+		created time.Time
+		known   bool
+	}
+
+	var candidates []candidate
+	for _, name := range names {
+		if name == "initial-state" {
+			continue
+		}
+		c := candidate{name: name}
+		if t, err := time.Parse(time.RFC3339, meta[name].CreatedAt); err == nil {
+			c.created, c.known = t, true
+		}
+		candidates = append(candidates, c)
+	}
+
+	if len(candidates) <= keep {
+		return nil
+	}
+
+	// Oldest first: dated snapshots by time, then undated ones
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.known != b.known {
+			return a.known
+		}
+		if a.known && !a.created.Equal(b.created) {
+			return a.created.Before(b.created)
+		}
+		return a.name < b.name
+	})
+
+	prune := make([]string, 0, len(candidates)-keep)
+	for _, c := range candidates[:len(candidates)-keep] {
+		prune = append(prune, c.name)
+	}
+	return prune
+}
+
+func runSnapshotPrune(cmd *cobra.Command, args []string) error {
+	containerName := args[0]
+
+	if snapshotPruneKeep < 0 {
+		return fmt.Errorf("--keep must be 0 or more")
+	}
+
+	cfg, lxcName, lock, err := requireContainerWithLock(containerName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	names, err := lxc.ListSnapshots(lxcName)
+	if err != nil {
+		return err
+	}
+
+	prune := snapshotsToPrune(names, cfg.GetSnapshots(containerName), snapshotPruneKeep)
+	if len(prune) == 0 {
+		printInfo("Nothing to prune for '%s'\n", containerName)
+		return nil
+	}
+
+	if snapshotPruneDryRun {
+		for _, name := range prune {
+			dryRunf("Would delete snapshot '%s' of '%s'", name, containerName)
+		}
+		return nil
+	}
+
+	printInfo("Pruning %d snapshot(s) of '%s'...\n", len(prune), containerName)
+	var failed []string
+	for _, name := range prune {
+		if err := lxc.DeleteSnapshot(lxcName, name); err != nil {
+			fmt.Printf("✗ %s: %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		cfg.RemoveSnapshot(containerName, name)
+		printInfo("✓ %s deleted\n", name)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete %d snapshot(s): %s", len(failed), strings.Join(failed, ", "))
+	}
 	return nil
 }
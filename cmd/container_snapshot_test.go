@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
+
+	"lxc-dev-manager/internal/config"
 )
 
 func TestSnapshotCreate_Success(t *testing.T) {
@@ -92,6 +96,26 @@ containers:
 	}
 }
 
+func TestSnapshotCreate_AlreadyExistsInLXC(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetError("info test-dev1/checkpoint", "not found")
+	env.mock.SetResponse("snapshot test-dev1 checkpoint", []byte("Error: Snapshot \"checkpoint\" already exists"), errors.New("exit status 1"))
+
+	err := runSnapshotCreate(nil, []string{"dev1", "checkpoint"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if err.Error() != "snapshot 'checkpoint' already exists" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestSnapshotCreate_NoProject(t *testing.T) {
 	_ = setupTestEnv(t)
 	// No config file
@@ -125,6 +149,45 @@ containers:
 	}
 }
 
+func TestSnapshotList_UsesLXCCreatedAt(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      initial-state:
+        description: Initial state
+        created_at: "2024-01-15T10:30:00Z"
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots",
+		`["/1.0/instances/test-dev1/snapshots/initial-state","/1.0/instances/test-dev1/snapshots/manual"]`)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots/initial-state",
+		`{"name":"initial-state","created_at":"2024-03-01T08:00:00Z"}`)
+	// Taken with 'lxc snapshot' directly, so not recorded in config
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots/manual",
+		`{"name":"manual","created_at":"2024-02-20T09:15:30.123456Z"}`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runSnapshotList(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out, "2024-01-15 10:30") {
+		t.Errorf("expected config created_at to take precedence, got:\n%s", out)
+	}
+	if strings.Contains(out, "2024-03-01") {
+		t.Errorf("LXD's created_at should only fill in missing config metadata, got:\n%s", out)
+	}
+	if !strings.Contains(out, "2024-02-20 09:15") {
+		t.Errorf("expected LXD created_at for untracked snapshot, got:\n%s", out)
+	}
+}
+
 func TestSnapshotList_Empty(t *testing.T) {
 	env := setupTestEnv(t)
 	env.writeConfig(`project: test
@@ -227,3 +290,361 @@ containers: {}
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestSnapshotList_JSONFormat(t *testing.T) {
+	env := setupTestEnv(t)
+	snapshotListFormat = "json"
+	t.Cleanup(func() { snapshotListFormat = formatTable })
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      initial-state:
+        description: Initial state
+        created_at: "2024-01-15T10:30:00Z"
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots",
+		`["/1.0/instances/test-dev1/snapshots/initial-state","/1.0/instances/test-dev1/snapshots/untracked"]`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runSnapshotList(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var snapshots []config.Snapshot
+	if err := json.Unmarshal([]byte(out), &snapshots); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snapshots))
+	}
+	if snapshots[0].Name != "initial-state" || snapshots[0].Description != "Initial state" {
+		t.Errorf("unexpected snapshot: %+v", snapshots[0])
+	}
+	if snapshots[1].Name != "untracked" || snapshots[1].CreatedAt != "" {
+		t.Errorf("unexpected snapshot: %+v", snapshots[1])
+	}
+}
+
+func TestSnapshotList_JSONIncludesLXCDetails(t *testing.T) {
+	env := setupTestEnv(t)
+	snapshotListFormat = "json"
+	t.Cleanup(func() { snapshotListFormat = formatTable })
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      initial-state:
+        description: Initial state
+        created_at: "2024-01-15T10:30:00Z"
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots",
+		`["/1.0/instances/test-dev1/snapshots/initial-state","/1.0/instances/test-dev1/snapshots/broken"]`)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots/initial-state",
+		`{"name":"initial-state","created_at":"2024-01-15T10:30:00Z","stateful":true,"size":1048576}`)
+	env.mock.SetError("query /1.0/instances/test-dev1/snapshots/broken", "Error: not found")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runSnapshotList(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entries []map[string]any
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(entries))
+	}
+
+	// Sorted by name
+	tracked := entries[1]
+	if tracked["name"] != "initial-state" || tracked["description"] != "Initial state" {
+		t.Errorf("expected config metadata, got %v", tracked)
+	}
+	if tracked["stateful"] != true || tracked["size"] != float64(1048576) {
+		t.Errorf("expected LXC details, got %v", tracked)
+	}
+
+	// A failed lookup still lists the snapshot, with null details
+	broken := entries[0]
+	if broken["name"] != "broken" {
+		t.Errorf("unexpected snapshot: %v", broken)
+	}
+	for _, key := range []string{"stateful", "size"} {
+		if v, ok := broken[key]; !ok || v != nil {
+			t.Errorf("expected %s to be null, got %v (present: %v)", key, v, ok)
+		}
+	}
+}
+
+func TestSnapshotDelete_DryRun(t *testing.T) {
+	env := setupTestEnv(t)
+	snapshotDeleteDryRun = true
+	t.Cleanup(func() { snapshotDeleteDryRun = false })
+
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      checkpoint:
+        description: test
+`)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("info test-dev1/checkpoint", "Name: checkpoint")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runSnapshotDelete(nil, []string{"dev1", "checkpoint"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if env.mock.HasCallPrefix("delete") {
+		t.Error("dry run should not delete")
+	}
+	if !strings.Contains(out, "[DRY-RUN] Would run: lxc delete test-dev1/checkpoint") {
+		t.Errorf("expected delete preview, got:\n%s", out)
+	}
+	if !strings.Contains(env.readConfig(), "checkpoint") {
+		t.Error("dry run should not change config")
+	}
+}
+
+func TestSnapshotRestore_NamedSnapshot(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("info test-dev1/checkpoint", "Name: checkpoint")
+	env.mock.SetOutput("restore test-dev1 checkpoint", "")
+
+	env.setPromptInput("y\n")
+
+	err := containerSnapshotRestoreCmd.RunE(containerSnapshotRestoreCmd, []string{"dev1", "checkpoint"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("restore", "test-dev1", "checkpoint") {
+		t.Error("expected restore command")
+	}
+}
+
+func TestSnapshotRestore_DefaultsToInitialState(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("info test-dev1/initial-state", "Name: initial-state")
+	env.mock.SetOutput("restore test-dev1 initial-state", "")
+
+	env.setPromptInput("y\n")
+
+	err := containerSnapshotRestoreCmd.RunE(containerSnapshotRestoreCmd, []string{"dev1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("restore", "test-dev1", "initial-state") {
+		t.Error("expected restore to initial-state")
+	}
+}
+
+func TestContainerReset_Deprecated(t *testing.T) {
+	if !strings.Contains(containerResetCmd.Deprecated, "container snapshot restore") {
+		t.Errorf("expected reset to point at snapshot restore, got %q", containerResetCmd.Deprecated)
+	}
+}
+
+func TestSnapshotsToPrune(t *testing.T) {
+	meta := map[string]config.Snapshot{
+		"initial-state": {CreatedAt: "2024-01-01T00:00:00Z"},
+		"old":           {CreatedAt: "2024-01-02T00:00:00Z"},
+		"mid":           {CreatedAt: "2024-01-03T00:00:00Z"},
+		"new":           {CreatedAt: "2024-01-04T00:00:00Z"},
+		"bad-time":      {CreatedAt: "yesterday"},
+	}
+	names := []string{"new", "untracked", "initial-state", "old", "bad-time", "mid"}
+
+	tests := []struct {
+		keep int
+		want []string
+	}{
+		{keep: 10, want: nil},
+		{keep: 5, want: nil},
+		{keep: 4, want: []string{"old"}},
+		{keep: 2, want: []string{"old", "mid", "new"}},
+		// Undated snapshots go last, in name order
+		{keep: 1, want: []string{"old", "mid", "new", "bad-time"}},
+		{keep: 0, want: []string{"old", "mid", "new", "bad-time", "untracked"}},
+	}
+	for _, tt := range tests {
+		got := snapshotsToPrune(names, meta, tt.keep)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("keep=%d: got %v, want %v", tt.keep, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotsToPrune_NeverInitialState(t *testing.T) {
+	names := []string{"initial-state", "a"}
+	got := snapshotsToPrune(names, nil, 0)
+	if strings.Join(got, ",") != "a" {
+		t.Errorf("got %v, want [a]", got)
+	}
+
+	if got := snapshotsToPrune([]string{"initial-state"}, nil, 0); len(got) != 0 {
+		t.Errorf("initial-state must never be pruned, got %v", got)
+	}
+}
+
+func withSnapshotPrune(t *testing.T, keep int, dryRun bool) {
+	t.Helper()
+	snapshotPruneKeep = keep
+	snapshotPruneDryRun = dryRun
+	t.Cleanup(func() {
+		snapshotPruneKeep = -1
+		snapshotPruneDryRun = false
+	})
+}
+
+const pruneConfig = `project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    snapshots:
+      initial-state:
+        created_at: "2024-01-01T00:00:00Z"
+      snap1:
+        created_at: "2024-01-02T00:00:00Z"
+      snap2:
+        created_at: "2024-01-03T00:00:00Z"
+      snap3:
+        created_at: "2024-01-04T00:00:00Z"
+`
+
+const pruneSnapshotList = `["/1.0/instances/test-dev1/snapshots/initial-state",` +
+	`"/1.0/instances/test-dev1/snapshots/snap1",` +
+	`"/1.0/instances/test-dev1/snapshots/snap2",` +
+	`"/1.0/instances/test-dev1/snapshots/snap3"]`
+
+func TestSnapshotPrune_DeletesOldest(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(pruneConfig)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots", pruneSnapshotList)
+	withSnapshotPrune(t, 1, false)
+
+	if err := runSnapshotPrune(nil, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"snap1", "snap2"} {
+		if !env.mock.HasCall("delete", "test-dev1/"+name) {
+			t.Errorf("expected %s to be deleted", name)
+		}
+	}
+	for _, name := range []string{"snap3", "initial-state"} {
+		if env.mock.HasCall("delete", "test-dev1/"+name) {
+			t.Errorf("%s should be kept", name)
+		}
+	}
+
+	cfg := env.readConfig()
+	if strings.Contains(cfg, "snap1") || strings.Contains(cfg, "snap2") {
+		t.Errorf("pruned snapshots should be removed from config:\n%s", cfg)
+	}
+	if !strings.Contains(cfg, "snap3") || !strings.Contains(cfg, "initial-state") {
+		t.Errorf("kept snapshots should stay in config:\n%s", cfg)
+	}
+}
+
+func TestSnapshotPrune_KeepZeroPreservesInitialState(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(pruneConfig)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots", pruneSnapshotList)
+	withSnapshotPrune(t, 0, false)
+
+	if err := runSnapshotPrune(nil, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if env.mock.HasCall("delete", "test-dev1/initial-state") {
+		t.Error("initial-state must never be deleted")
+	}
+	if !env.mock.HasCall("delete", "test-dev1/snap3") {
+		t.Error("expected snap3 to be deleted")
+	}
+}
+
+func TestSnapshotPrune_DryRun(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(pruneConfig)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots", pruneSnapshotList)
+	withSnapshotPrune(t, 2, true)
+
+	out := env.captureStdout(func() {
+		if err := runSnapshotPrune(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if env.mock.HasCallPrefix("delete") {
+		t.Error("dry run should not delete")
+	}
+	if !strings.Contains(out, "Would delete snapshot 'snap1'") {
+		t.Errorf("expected dry-run output, got:\n%s", out)
+	}
+}
+
+func TestSnapshotPrune_PartialFailure(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(pruneConfig)
+	env.setContainerExists("test-dev1", true)
+	env.mock.SetOutput("query /1.0/instances/test-dev1/snapshots", pruneSnapshotList)
+	env.mock.SetError("delete test-dev1/snap1", "busy")
+	withSnapshotPrune(t, 1, false)
+
+	err := runSnapshotPrune(nil, []string{"dev1"})
+	if err == nil || !strings.Contains(err.Error(), "snap1") {
+		t.Fatalf("expected error naming snap1, got %v", err)
+	}
+
+	cfg := env.readConfig()
+	if !strings.Contains(cfg, "snap1") {
+		t.Error("snap1 failed to delete and should stay in config")
+	}
+	if strings.Contains(cfg, "snap2") {
+		t.Error("snap2 was deleted and should be removed from config")
+	}
+}
+
+func TestSnapshotPrune_NegativeKeep(t *testing.T) {
+	setupTestEnv(t)
+	withSnapshotPrune(t, -1, false)
+
+	if err := runSnapshotPrune(nil, []string{"dev1"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
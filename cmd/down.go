@@ -1,32 +1,122 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
+	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 
 	"github.com/spf13/cobra"
 )
 
 var downCmd = &cobra.Command{
-	Use:   "down <name>",
+	Use:   "down [name]",
 	Short: "Stop a container",
 	Long: `Stop a running container.
 
+Use --all to stop every container in the project in parallel, or pass a
+pattern ("dev*", "*") to stop only the matching containers. Quote patterns
+so the shell does not expand them.
+
+Use --stateful to save the container's memory to disk before stopping, so
+the next 'up' resumes it where it left off instead of booting it. This needs
+CRIU on the LXD host and migration.stateful=true on the container.
+
 Example:
-  lxc-dev-manager down dev1`,
-	Args: cobra.ExactArgs(1),
+  lxc-dev-manager down dev1
+  lxc-dev-manager down dev1 --graceful-timeout 30
+  lxc-dev-manager down dev1 --stateful
+  lxc-dev-manager down --all
+  lxc-dev-manager down "dev*"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runDown,
 }
 
+var (
+	downAll             bool
+	downConcurrency     int
+	downGracefulTimeout int
+	downStateful        bool
+)
+
 func init() {
 	rootCmd.AddCommand(downCmd)
+	downCmd.Flags().BoolVarP(&downAll, "all", "a", false, "Stop all containers in the project")
+	downCmd.Flags().IntVar(&downConcurrency, "concurrency", defaultConcurrency, "Maximum number of containers to stop at once (with --all or a pattern)")
+	downCmd.Flags().IntVar(&downGracefulTimeout, "graceful-timeout", 0, "Seconds to wait for a clean shutdown (0 uses LXC's default)")
+	downCmd.Flags().BoolVar(&downStateful, "stateful", false, "Save the memory state so 'up' resumes the container (needs CRIU)")
 }
 
 func runDown(cmd *cobra.Command, args []string) error {
-	name := args[0]
+	if downStateful && downGracefulTimeout > 0 {
+		return fmt.Errorf("--stateful cannot be combined with --graceful-timeout")
+	}
+	if downAll {
+		if len(args) > 0 {
+			return fmt.Errorf("cannot specify a container name with --all")
+		}
+		return runDownAll()
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("requires a container name (or --all)")
+	}
+	if isContainerPattern(args[0]) {
+		return runDownMatching(args[0])
+	}
+
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+	return stopContainer(cfg, args[0])
+}
+
+// runDownMatching stops every container whose name matches pattern, in parallel
+func runDownMatching(pattern string) error {
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	names := matchContainers(cfg, pattern)
+	if len(names) == 0 {
+		return fmt.Errorf("no containers match pattern %q", pattern)
+	}
+
+	printInfo("Stopping %d container(s): %s\n", len(names), joinNames(names))
+	return runForEach(names, downConcurrency, stopEach(cfg))
+}
+
+// runDownAll stops every container in the project in parallel
+func runDownAll() error {
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	names := containerNames(cfg)
+	if len(names) == 0 {
+		printInfo("No containers defined in config\n")
+		return nil
+	}
+
+	printInfo("Stopping %d container(s): %s\n", len(names), joinNames(names))
+	return runForEach(names, downConcurrency, stopEach(cfg))
+}
 
-	_, lxcName, err := requireContainer(name)
+// stopEach returns a runForEach worker that stops containers from cfg,
+// so that parallel stops share one config snapshot
+func stopEach(cfg *config.Config) func(name string) error {
+	return func(name string) error {
+		return stopContainer(cfg, name)
+	}
+}
+
+// stopContainer stops a single container
+func stopContainer(cfg *config.Config, name string) error {
+	lxcName, err := containerLXCName(cfg, name)
 	if err != nil {
 		return err
 	}
@@ -38,16 +128,28 @@ func runDown(cmd *cobra.Command, args []string) error {
 	}
 
 	if status == "STOPPED" {
-		fmt.Printf("Container '%s' is already stopped\n", name)
+		printInfo("Container '%s' is already stopped\n", name)
+		return nil
+	}
+
+	if downStateful {
+		printInfo("Saving state of container '%s'...\n", name)
+		if err := lxc.StopStateful(lxcName); err != nil {
+			if errors.Is(err, lxc.ErrStatefulUnsupported) {
+				return fmt.Errorf("cannot save the state of '%s': install CRIU on the LXD host and run 'lxc config set %s migration.stateful=true', or stop without --stateful (%w)", name, lxcName, err)
+			}
+			return err
+		}
+		printInfo("Container '%s' stopped with its state saved; 'up' will resume it\n", name)
 		return nil
 	}
 
 	// Stop container
-	fmt.Printf("Stopping container '%s'...\n", name)
-	if err := lxc.Stop(lxcName); err != nil {
+	printInfo("Stopping container '%s'...\n", name)
+	if err := lxc.Stop(lxcName, time.Duration(downGracefulTimeout)*time.Second); err != nil {
 		return err
 	}
 
-	fmt.Printf("Container '%s' stopped\n", name)
+	printInfo("Container '%s' stopped\n", name)
 	return nil
 }
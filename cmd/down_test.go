@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -21,6 +22,83 @@ func TestDown_Success(t *testing.T) {
 	}
 }
 
+func TestDown_GracefulTimeout(t *testing.T) {
+	env := setupTestEnv(t)
+	downGracefulTimeout = 30
+	t.Cleanup(func() { downGracefulTimeout = 0 })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("stop dev1", "")
+
+	if err := runDown(nil, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("stop", "dev1", "--timeout", "30") {
+		t.Errorf("expected stop with --timeout 30, got calls: %v", env.mock.Calls)
+	}
+}
+
+func setDownStateful(t *testing.T) {
+	t.Helper()
+	downStateful = true
+	t.Cleanup(func() { downStateful = false })
+}
+
+func TestDown_Stateful(t *testing.T) {
+	env := setupTestEnv(t)
+	setDownStateful(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("stop dev1 --stateful", "")
+
+	out := env.captureStdout(func() {
+		if err := runDown(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("stop", "dev1", "--stateful") {
+		t.Errorf("expected stop --stateful, got calls: %v", env.mock.Calls)
+	}
+	if !strings.Contains(out, "state saved") {
+		t.Errorf("expected saved state message, got:\n%s", out)
+	}
+}
+
+func TestDown_StatefulWithoutCRIU(t *testing.T) {
+	env := setupTestEnv(t)
+	setDownStateful(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetResponse("stop dev1 --stateful", []byte("Error: Unable to perform container live migration. CRIU isn't installed on the source server"), errors.New("exit status 1"))
+
+	var err error
+	env.captureStdout(func() {
+		err = runDown(nil, []string{"dev1"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "install CRIU") {
+		t.Errorf("expected CRIU hint, got %v", err)
+	}
+	if env.mock.HasCall("stop", "dev1") {
+		t.Error("should not fall back to a plain stop")
+	}
+}
+
+func TestDown_StatefulWithGracefulTimeout(t *testing.T) {
+	env := setupTestEnv(t)
+	setDownStateful(t)
+	downGracefulTimeout = 30
+	t.Cleanup(func() { downGracefulTimeout = 0 })
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runDown(nil, []string{"dev1"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
 func TestDown_AlreadyStopped(t *testing.T) {
 	env := setupTestEnv(t)
 	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
@@ -77,3 +155,146 @@ func TestDown_GetStatusFails(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+// withDownAll enables --all for the duration of a test
+func withDownAll(t *testing.T) {
+	t.Helper()
+	downAll = true
+	downConcurrency = defaultConcurrency
+	t.Cleanup(func() { downAll = false })
+}
+
+func TestDown_All(t *testing.T) {
+	env := setupTestEnv(t)
+	withDownAll(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+  dev3:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+	env.setContainerExists("dev2", true)
+	env.setContainerExists("dev3", false)
+	env.mock.SetOutput("stop", "")
+
+	err := runDown(nil, []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("stop", "dev1") || !env.mock.HasCall("stop", "dev2") {
+		t.Error("expected stop commands for running containers")
+	}
+	if env.mock.HasCall("stop", "dev3") {
+		t.Error("should not stop already stopped dev3")
+	}
+}
+
+func TestDown_AllPartialFailure(t *testing.T) {
+	env := setupTestEnv(t)
+	withDownAll(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+	env.setContainerExists("dev2", true)
+	env.mock.SetOutput("stop dev1", "")
+	env.mock.SetError("stop dev2", "failed to stop")
+
+	err := runDown(nil, []string{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "dev2") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCall("stop", "dev1") {
+		t.Error("expected dev1 to be stopped despite dev2 failure")
+	}
+}
+
+func TestDown_AllUsesOneConfigSnapshot(t *testing.T) {
+	env := setupTestEnv(t)
+	withDownAll(t)
+	downConcurrency = 1
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+	env.setContainerExists("dev2", true)
+	env.mock.SetOutput("stop", "")
+	env.mock.SetCallback("stop dev1", func([]string) {
+		env.writeMinimalConfig()
+	})
+
+	if err := runDown(nil, []string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCall("stop", "dev2") {
+		t.Errorf("expected dev2 to stop from the initial config, got calls: %v", env.mock.Calls)
+	}
+}
+
+func TestDown_AllEmpty(t *testing.T) {
+	env := setupTestEnv(t)
+	withDownAll(t)
+	env.writeMinimalConfig()
+
+	err := runDown(nil, []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDown_Pattern(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+  db:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+	env.setContainerExists("dev2", true)
+	env.setContainerExists("db", true)
+
+	err := runDown(nil, []string{"dev*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("stop", "dev1") || !env.mock.HasCall("stop", "dev2") {
+		t.Error("expected matching containers to be stopped")
+	}
+	if env.mock.HasCall("stop", "db") {
+		t.Error("should not stop non-matching container")
+	}
+}
+
+func TestDown_PatternNoMatch(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runDown(nil, []string{"web*"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "no containers match pattern") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze <name>",
+	Short: "Pause a running container",
+	Long: `Freeze all processes in a running container.
+
+The container keeps its memory and state but uses no CPU until it is
+resumed with 'unfreeze'.
+
+Example:
+  lxc-dev-manager freeze dev1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFreeze,
+}
+
+var unfreezeCmd = &cobra.Command{
+	Use:   "unfreeze <name>",
+	Short: "Resume a frozen container",
+	Long: `Resume a container paused with 'freeze'.
+
+Example:
+  lxc-dev-manager unfreeze dev1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUnfreeze,
+}
+
+func init() {
+	rootCmd.AddCommand(freezeCmd)
+	rootCmd.AddCommand(unfreezeCmd)
+}
+
+func runFreeze(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	_, lxcName, err := requireContainer(name)
+	if err != nil {
+		return err
+	}
+
+	status, err := lxc.GetStatus(lxcName)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case "FROZEN":
+		printInfo("Container '%s' is already frozen\n", name)
+		return nil
+	case "RUNNING":
+	default:
+		return fmt.Errorf("container '%s' is not running (status: %s)", name, status)
+	}
+
+	printInfo("Freezing container '%s'...\n", name)
+	if err := lxc.Freeze(lxcName); err != nil {
+		return err
+	}
+
+	printInfo("Container '%s' frozen\n", name)
+	return nil
+}
+
+func runUnfreeze(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	_, lxcName, err := requireContainer(name)
+	if err != nil {
+		return err
+	}
+
+	status, err := lxc.GetStatus(lxcName)
+	if err != nil {
+		return err
+	}
+
+	if status != "FROZEN" {
+		return fmt.Errorf("container '%s' is not frozen (status: %s)", name, status)
+	}
+
+	printInfo("Unfreezing container '%s'...\n", name)
+	if err := lxc.Unfreeze(lxcName); err != nil {
+		return err
+	}
+
+	printInfo("Container '%s' resumed\n", name)
+	return nil
+}
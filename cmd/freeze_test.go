@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFreeze_Running(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	if err := runFreeze(nil, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCall("pause", "dev1") {
+		t.Errorf("expected pause call, got: %v", env.mock.Calls)
+	}
+}
+
+func TestFreeze_AlreadyFrozen(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("list dev1 -cs -f csv", "FROZEN")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runFreeze(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "already frozen") {
+		t.Errorf("expected already frozen message, got: %s", out)
+	}
+	if env.mock.HasCallPrefix("pause") {
+		t.Error("should not pause an already frozen container")
+	}
+}
+
+func TestFreeze_Stopped(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+
+	err := runFreeze(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error for stopped container")
+	}
+	if !strings.Contains(err.Error(), "not running") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if env.mock.HasCallPrefix("pause") {
+		t.Error("should not pause a stopped container")
+	}
+}
+
+func TestFreeze_PauseFails(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetError("pause dev1", "cgroup freezer unavailable")
+
+	err := runFreeze(nil, []string{"dev1"})
+	if err == nil || !strings.Contains(err.Error(), "failed to freeze") {
+		t.Errorf("expected freeze error, got %v", err)
+	}
+}
+
+func TestFreeze_NotInConfig(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	if err := runFreeze(nil, []string{"dev1"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUnfreeze_Frozen(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("list dev1 -cs -f csv", "FROZEN")
+
+	if err := runUnfreeze(nil, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCall("start", "dev1") {
+		t.Errorf("expected start call, got: %v", env.mock.Calls)
+	}
+}
+
+func TestUnfreeze_NotFrozen(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	err := runUnfreeze(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error for running container")
+	}
+	if !strings.Contains(err.Error(), "is not frozen (status: RUNNING)") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if env.mock.HasCallPrefix("start") {
+		t.Error("should not start a container that is not frozen")
+	}
+}
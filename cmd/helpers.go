@@ -1,12 +1,111 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
 
 	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 )
 
+// defaultConcurrency is the default number of parallel container operations
+const defaultConcurrency = 4
+
+// Output formats accepted by --format
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv" // only supported by 'list'
+)
+
+// formatOutput renders data in the requested format. For "table", printTable
+// is called to render the human-readable view; for "json", data is written to
+// stdout as pretty-printed JSON.
+func formatOutput(format string, data any, printTable func()) error {
+	if err := validateFormat(format); err != nil {
+		return err
+	}
+
+	if format == formatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	}
+
+	printTable()
+	return nil
+}
+
+// validateFormat checks a --format value so commands can fail before doing any work
+func validateFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, "":
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (must be %s or %s)", format, formatTable, formatJSON)
+	}
+}
+
+// interruptContext returns a context that is cancelled on Ctrl+C or SIGTERM,
+// so long-running lxc commands are killed instead of blocking the CLI.
+// The caller must call the returned stop function when done.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// dryRunPrefix marks output describing actions a --dry-run skipped
+const dryRunPrefix = "[DRY-RUN]"
+
+// dryRunf prints a single skipped action
+func dryRunf(format string, args ...any) {
+	fmt.Printf(dryRunPrefix+" "+format+"\n", args...)
+}
+
+// printInfo prints a progress or success message, unless --quiet is set.
+// Warnings and requested output (lists, status) use fmt directly.
+func printInfo(format string, args ...any) {
+	if quiet {
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+// infoOutput is where lxc progress output is written: stdout, or nowhere
+// with --quiet
+func infoOutput() io.Writer {
+	if quiet {
+		return io.Discard
+	}
+	return os.Stdout
+}
+
+// promptInput is where confirmPrompt reads answers from. It is shared so
+// that several prompts in one run consume piped answers in order; tests
+// replace it with canned responses.
+var promptInput = bufio.NewReader(os.Stdin)
+
+// confirmPrompt asks user for yes/no confirmation
+func confirmPrompt(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+
+	response, err := promptInput.ReadString('\n')
+	if err != nil && response == "" {
+		return false
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
 // requireProject loads config and ensures a project exists.
 // Returns the config or an error if no project is found.
 func requireProject() (*config.Config, error) {
@@ -17,6 +116,7 @@ func requireProject() (*config.Config, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("no project found. Run 'lxc-dev-manager project create' first to initialize a project")
 	}
+	lxc.SetRemote(cfg.Defaults.Remote)
 	return cfg, nil
 }
 
@@ -28,16 +128,26 @@ func requireContainer(name string) (*config.Config, string, error) {
 		return nil, "", err
 	}
 
+	lxcName, err := containerLXCName(cfg, name)
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg, lxcName, nil
+}
+
+// containerLXCName ensures a container exists in cfg and in LXC, and returns
+// its LXC name. Operations over several containers load the config once and
+// check each container with this rather than calling requireContainer.
+func containerLXCName(cfg *config.Config, name string) (string, error) {
 	if !cfg.HasContainer(name) {
-		return nil, "", fmt.Errorf("container '%s' not found in project config", name)
+		return "", fmt.Errorf("container '%s' not found in project config", name)
 	}
 
 	lxcName := cfg.GetLXCName(name)
 	if !lxc.Exists(lxcName) {
-		return nil, "", fmt.Errorf("container '%s' does not exist in LXC (expected: %s)", name, lxcName)
+		return "", fmt.Errorf("container '%s' does not exist in LXC (expected: %s)", name, lxcName)
 	}
-
-	return cfg, lxcName, nil
+	return lxcName, nil
 }
 
 // requireRunningContainer ensures a container exists and is running.
@@ -70,6 +180,7 @@ func requireProjectWithLock() (*config.Config, *config.ConfigLock, error) {
 		lock.Release()
 		return nil, nil, fmt.Errorf("no project found. Run 'lxc-dev-manager project create' first to initialize a project")
 	}
+	lxc.SetRemote(cfg.Defaults.Remote)
 	return cfg, lock, nil
 }
 
@@ -94,3 +205,95 @@ func requireContainerWithLock(name string) (*config.Config, string, *config.Conf
 
 	return cfg, lxcName, lock, nil
 }
+
+// containerNames returns the short names of all containers in config, sorted
+func containerNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Containers))
+	for name := range cfg.Containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// joinNames formats container names for display
+func joinNames(names []string) string {
+	return strings.Join(names, ", ")
+}
+
+// isContainerPattern reports whether a container argument is a glob pattern
+func isContainerPattern(arg string) bool {
+	return strings.Contains(arg, "*")
+}
+
+// matchContainers returns container names matching the glob pattern.
+// Supports: "*" (all containers), "prefix*" (containers starting with prefix)
+func matchContainers(cfg *config.Config, pattern string) []string {
+	var matches []string
+
+	if pattern == "*" {
+		// All containers
+		for name := range cfg.Containers {
+			matches = append(matches, name)
+		}
+	} else if strings.HasSuffix(pattern, "*") {
+		// Prefix match (e.g., "dev*")
+		prefix := strings.TrimSuffix(pattern, "*")
+		for name := range cfg.Containers {
+			if strings.HasPrefix(name, prefix) {
+				matches = append(matches, name)
+			}
+		}
+	} else {
+		// Exact match - return single container if it exists
+		if _, ok := cfg.Containers[pattern]; ok {
+			matches = append(matches, pattern)
+		}
+	}
+
+	sort.Strings(matches) // Consistent ordering
+	return matches
+}
+
+// runForEach runs fn for every container name with at most concurrency
+// operations in flight, then prints a per-container summary.
+// Returns a combined error if any operation failed.
+func runForEach(names []string, concurrency int, fn func(name string) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs := make([]error, len(names))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(name)
+		}(i, name)
+	}
+	wg.Wait()
+
+	// Print summary in stable order
+	printInfo("\n")
+	var failures []string
+	for i, name := range names {
+		if errs[i] != nil {
+			fmt.Printf("✗ %s failed: %v\n", name, errs[i])
+			failures = append(failures, fmt.Sprintf("%s: %v", name, errs[i]))
+			continue
+		}
+		printInfo("✓ %s done\n", name)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed for %d container(s):\n  %s", len(failures), strings.Join(failures, "\n  "))
+	}
+	return nil
+}
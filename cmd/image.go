@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"lxc-dev-manager/internal/lxc"
+	"lxc-dev-manager/internal/validation"
 
 	"github.com/spf13/cobra"
 )
@@ -13,7 +16,7 @@ import (
 var imageCmd = &cobra.Command{
 	Use:   "image",
 	Short: "Manage images",
-	Long:  `Manage container images (list, delete, rename).`,
+	Long:  `Manage container images (pull, list, delete, rename, export, import).`,
 }
 
 // Alias: 'images' -> 'image list'
@@ -33,7 +36,9 @@ var imageListCmd = &cobra.Command{
 
 Example:
   lxc-dev-manager image list
-  lxc-dev-manager image list --all`,
+  lxc-dev-manager image list --all
+  lxc-dev-manager image list --filter 'ubuntu*'
+  lxc-dev-manager image list --format json`,
 	Args: cobra.NoArgs,
 	RunE: runImageList,
 }
@@ -65,6 +70,8 @@ Example:
 }
 
 var imageListAll bool
+var imageListFormat string
+var imageListFilter string
 var imageDeleteForce bool
 
 func init() {
@@ -76,6 +83,9 @@ func init() {
 	imageCmd.AddCommand(imageListCmd)
 	imageCmd.AddCommand(imageDeleteCmd)
 	imageCmd.AddCommand(imageRenameCmd)
+	imageCmd.AddCommand(imagePullCmd)
+	imageCmd.AddCommand(imageExportCmd)
+	imageCmd.AddCommand(imageImportCmd)
 
 	// Add images alias at root level
 	rootCmd.AddCommand(imagesCmd)
@@ -83,15 +93,72 @@ func init() {
 	// Flags
 	imageListCmd.Flags().BoolVarP(&imageListAll, "all", "a", false, "Show all images including cached")
 	imagesCmd.Flags().BoolVarP(&imageListAll, "all", "a", false, "Show all images including cached")
+	imageListCmd.Flags().StringVar(&imageListFormat, "format", formatTable, "Output format (table, json)")
+	imagesCmd.Flags().StringVar(&imageListFormat, "format", formatTable, "Output format (table, json)")
+	imageListCmd.Flags().StringVar(&imageListFilter, "filter", "", "Only show images whose alias matches a glob (ubuntu*) or contains a string (dev-)")
+	imagesCmd.Flags().StringVar(&imageListFilter, "filter", "", "Only show images whose alias matches a glob (ubuntu*) or contains a string (dev-)")
 	imageDeleteCmd.Flags().BoolVarP(&imageDeleteForce, "force", "f", false, "Skip confirmation prompt")
+	imageCreateCmd.Flags().BoolVar(&imageCreateNoRestart, "no-restart", false, "Leave the container stopped afterwards")
 }
 
 func runImageList(cmd *cobra.Command, args []string) error {
+	if err := validateFormat(imageListFormat); err != nil {
+		return err
+	}
+
 	images, err := lxc.ListImages(imageListAll)
 	if err != nil {
 		return err
 	}
+	if imageListFilter != "" {
+		if images, err = filterImages(images, imageListFilter); err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			if !imageListAll {
+				return fmt.Errorf("no custom images match %q (use --all to include cached images)", imageListFilter)
+			}
+			return fmt.Errorf("no images match %q", imageListFilter)
+		}
+	}
+	if images == nil {
+		images = []lxc.ImageInfo{}
+	}
 
+	return formatOutput(imageListFormat, images, func() {
+		printImageTable(images)
+	})
+}
+
+// filterImages keeps the images whose alias matches pattern: a glob when it
+// contains '*', otherwise a substring
+func filterImages(images []lxc.ImageInfo, pattern string) ([]lxc.ImageInfo, error) {
+	glob := strings.Contains(pattern, "*")
+	if glob {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", pattern, err)
+		}
+	}
+
+	var matched []lxc.ImageInfo
+	for _, img := range images {
+		if img.Alias == "" {
+			continue
+		}
+		if glob {
+			if ok, _ := filepath.Match(pattern, img.Alias); ok {
+				matched = append(matched, img)
+			}
+		} else if strings.Contains(img.Alias, pattern) {
+			matched = append(matched, img)
+		}
+	}
+
+	return matched, nil
+}
+
+// printImageTable prints images as a human-readable table
+func printImageTable(images []lxc.ImageInfo) {
 	if len(images) == 0 {
 		if imageListAll {
 			fmt.Println("No images found")
@@ -99,7 +166,7 @@ func runImageList(cmd *cobra.Command, args []string) error {
 			fmt.Println("No custom images found")
 			fmt.Println("Use --all to show cached images")
 		}
-		return nil
+		return
 	}
 
 	// Print header
@@ -124,28 +191,29 @@ func runImageList(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("%-25s %-14s %-10s %s\n", alias, fp, img.Size, desc)
 	}
-
-	return nil
 }
 
 func runImageDelete(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
 	// Check if exists
-	if !lxc.ImageExists(name) {
-		return fmt.Errorf("image '%s' not found", name)
+	if _, err := lxc.GetImageFingerprint(name); err != nil {
+		if errors.Is(err, lxc.ErrNotFound) {
+			return fmt.Errorf("image '%s' not found", name)
+		}
+		return err
 	}
 
 	// Get image info for display
 	images, _ := lxc.ListImages(true)
 	for _, img := range images {
 		if img.Alias == name {
-			fmt.Printf("\nImage: %s\n", name)
-			fmt.Printf("  Size: %s\n", img.Size)
+			printInfo("\nImage: %s\n", name)
+			printInfo("  Size: %s\n", img.Size)
 			if img.Description != "" {
-				fmt.Printf("  Description: %s\n", img.Description)
+				printInfo("  Description: %s\n", img.Description)
 			}
-			fmt.Println()
+			printInfo("\n")
 			break
 		}
 	}
@@ -158,12 +226,12 @@ func runImageDelete(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("Deleting image '%s'...\n", name)
+	printInfo("Deleting image '%s'...\n", name)
 	if err := lxc.DeleteImage(name); err != nil {
 		return err
 	}
 
-	fmt.Printf("Image '%s' deleted\n", name)
+	printInfo("Image '%s' deleted\n", name)
 	return nil
 }
 
@@ -171,21 +239,30 @@ func runImageRename(cmd *cobra.Command, args []string) error {
 	oldName := args[0]
 	newName := args[1]
 
+	if err := validation.ValidateImageAlias(newName); err != nil {
+		return err
+	}
+
 	// Check if old exists
-	if !lxc.ImageExists(oldName) {
-		return fmt.Errorf("image '%s' not found", oldName)
+	if _, err := lxc.GetImageFingerprint(oldName); err != nil {
+		if errors.Is(err, lxc.ErrNotFound) {
+			return fmt.Errorf("image '%s' not found", oldName)
+		}
+		return err
 	}
 
 	// Check if new already exists
-	if lxc.ImageExists(newName) {
+	if _, err := lxc.GetImageFingerprint(newName); err == nil {
 		return fmt.Errorf("image '%s' already exists", newName)
+	} else if !errors.Is(err, lxc.ErrNotFound) {
+		return err
 	}
 
-	fmt.Printf("Renaming image '%s' → '%s'...\n", oldName, newName)
+	printInfo("Renaming image '%s' → '%s'...\n", oldName, newName)
 	if err := lxc.RenameImage(oldName, newName); err != nil {
 		return err
 	}
 
-	fmt.Printf("Image renamed: %s → %s\n", oldName, newName)
+	printInfo("Image renamed: %s → %s\n", oldName, newName)
 	return nil
 }
@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"lxc-dev-manager/internal/lxc"
+	"lxc-dev-manager/internal/validation"
 
 	"github.com/spf13/cobra"
 )
@@ -15,10 +18,12 @@ var imageCreateCmd = &cobra.Command{
 	Short: "Create an image from a container",
 	Long: `Create a reusable image from an existing container.
 
-The container will be stopped before creating the image, then restarted.
+The container will be stopped before creating the image, then restarted
+if it was running. Use --no-restart to leave it stopped.
 
 Example:
   lxc-dev-manager image create dev1 my-base-image
+  lxc-dev-manager image create dev1 my-base-image --no-restart
 
 Then create new containers from it:
   lxc-dev-manager container create dev2 my-base-image`,
@@ -28,6 +33,10 @@ Then create new containers from it:
 
 // imageCreateCmd is registered in image.go init()
 
+var (
+	imageCreateNoRestart bool
+)
+
 const (
 	colorReset  = "\033[0m"
 	colorGreen  = "\033[32m"
@@ -36,15 +45,15 @@ const (
 )
 
 func stepStart(step, total int, msg string) {
-	fmt.Printf("%s[%d/%d]%s %s\n", colorCyan, step, total, colorReset, msg)
+	printInfo("%s[%d/%d]%s %s\n", colorCyan, step, total, colorReset, msg)
 }
 
 func stepDone(msg string) {
-	fmt.Printf("      %s✓%s %s\n", colorGreen, colorReset, msg)
+	printInfo("      %s✓%s %s\n", colorGreen, colorReset, msg)
 }
 
 func stepInfo(msg string) {
-	fmt.Printf("      %s\n", msg)
+	printInfo("      %s\n", msg)
 }
 
 func runImageCreate(cmd *cobra.Command, args []string) error {
@@ -52,6 +61,10 @@ func runImageCreate(cmd *cobra.Command, args []string) error {
 	imageName := args[1]
 	snapshotName := fmt.Sprintf("snapshot-%d", time.Now().Unix())
 
+	if err := validation.ValidateImageAlias(imageName); err != nil {
+		return err
+	}
+
 	totalSteps := 4
 
 	_, lxcName, err := requireContainer(name)
@@ -70,7 +83,7 @@ func runImageCreate(cmd *cobra.Command, args []string) error {
 	// Step 1: Stop container
 	stepStart(1, totalSteps, fmt.Sprintf("Stopping container '%s'...", name))
 	if wasRunning {
-		if err := lxc.Stop(lxcName); err != nil {
+		if err := lxc.Stop(lxcName, 0); err != nil {
 			return err
 		}
 		stepDone("Stopped")
@@ -87,11 +100,13 @@ func runImageCreate(cmd *cobra.Command, args []string) error {
 
 	// Step 3: Publish snapshot as image (this is the slow part)
 	stepStart(3, totalSteps, fmt.Sprintf("Publishing image '%s'...", imageName))
-	fmt.Println() // Extra line for LXC output
+	printInfo("\n") // Extra line for LXC output
 
-	// Create a prefixed writer to indent LXC output
-	err = lxc.PublishSnapshotWithProgress(lxcName, snapshotName, imageName,
-		&prefixWriter{prefix: "      ", w: os.Stdout},
+	// Create a prefixed writer to indent LXC output (Ctrl+C kills the publish)
+	ctx, stop := interruptContext()
+	defer stop()
+	err = lxc.PublishSnapshotWithProgress(ctx, lxcName, snapshotName, imageName,
+		&prefixWriter{prefix: "      ", w: infoOutput()},
 		&prefixWriter{prefix: "      ", w: os.Stderr})
 
 	// Clean up snapshot regardless of publish result
@@ -100,12 +115,14 @@ func runImageCreate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println()
+	printInfo("\n")
 	stepDone("Image published")
 
 	// Step 4: Restart if was running
 	stepStart(4, totalSteps, fmt.Sprintf("Restarting container '%s'...", name))
-	if wasRunning {
+	if wasRunning && imageCreateNoRestart {
+		stepDone("Kept stopped (--no-restart)")
+	} else if wasRunning {
 		if err := lxc.Start(lxcName); err != nil {
 			return fmt.Errorf("failed to restart container: %w", err)
 		}
@@ -120,41 +137,48 @@ func runImageCreate(cmd *cobra.Command, args []string) error {
 		stepDone("Kept stopped (was not running before)")
 	}
 
-	fmt.Printf("\n%sImage '%s' created successfully!%s\n", colorGreen, imageName, colorReset)
-	fmt.Printf("\nCreate new containers from it with:\n")
-	fmt.Printf("  lxc-dev-manager container create <name> %s\n", imageName)
+	printInfo("\n%sImage '%s' created successfully!%s\n", colorGreen, imageName, colorReset)
+	printInfo("\nCreate new containers from it with:\n")
+	printInfo("  lxc-dev-manager container create <name> %s\n", imageName)
+	if wasRunning && imageCreateNoRestart {
+		printInfo("\nContainer '%s' is stopped. Start it again with:\n", name)
+		printInfo("  lxc-dev-manager up %s\n", name)
+	}
 
 	return nil
 }
 
-// prefixWriter adds a prefix to each line of output
+// prefixWriter adds a prefix to each line of output. A carriage return
+// also starts a new line, so progress updates that redraw the current line
+// stay indented.
 type prefixWriter struct {
-	prefix     string
-	w          *os.File
-	needPrefix bool
+	prefix  string
+	w       io.Writer
+	midLine bool // The last write did not end a line
 }
 
-func (pw *prefixWriter) Write(p []byte) (n int, err error) {
-	if pw.needPrefix || len(p) == 0 {
-		pw.w.WriteString(pw.prefix)
-		pw.needPrefix = false
-	}
-
-	for i, b := range p {
-		if b == '\n' && i < len(p)-1 {
-			pw.w.Write(p[:i+1])
-			pw.w.WriteString(pw.prefix)
-			p = p[i+1:]
-			i = -1
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if !pw.midLine {
+			if _, err := io.WriteString(pw.w, pw.prefix); err != nil {
+				return n - len(p), err
+			}
+			pw.midLine = true
 		}
-	}
 
-	if len(p) > 0 {
-		pw.w.Write(p)
-		if p[len(p)-1] == '\n' {
-			pw.needPrefix = true
+		end := len(p)
+		if i := bytes.IndexAny(p, "\r\n"); i >= 0 {
+			end = i + 1
+			if p[i] == '\r' && end < len(p) && p[end] == '\n' {
+				end++
+			}
+			pw.midLine = false
+		}
+		if _, err := pw.w.Write(p[:end]); err != nil {
+			return n - len(p), err
 		}
+		p = p[end:]
 	}
-
-	return len(p), nil
+	return n, nil
 }
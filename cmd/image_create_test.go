@@ -1,13 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 )
 
-// Note: Full snapshot tests require e2e testing because PublishSnapshotWithProgress
-// uses exec.Command directly for streaming output. These tests cover the
-// pre-publish validation logic.
+// Note: the mock executor accepts any publish, so these tests cover the
+// surrounding steps; the image itself is only checked by e2e tests.
 
 func TestImageCreate_NotExists(t *testing.T) {
 	env := setupTestEnv(t)
@@ -122,8 +122,7 @@ func TestImageCreate_DeletesSnapshotOnSuccess(t *testing.T) {
 	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
 	env.setContainerExists("dev1", false)
 	env.mock.SetOutput("snapshot dev1", "")
-	// The delete is called for cleanup, but publish uses exec.Command
-	// so we can only verify the snapshot was created
+	// The delete is called for cleanup; verify the snapshot was created
 
 	runImageCreate(nil, []string{"dev1", "my-image"})
 
@@ -194,3 +193,93 @@ func TestImageCreate_CallsStopBeforeSnapshot(t *testing.T) {
 		t.Error("expected stop to be called before snapshot")
 	}
 }
+
+func TestPrefixWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"single line", []string{"hello\n"}, "> hello\n"},
+		{"several lines", []string{"a\nb\n"}, "> a\n> b\n"},
+		{"line split across writes", []string{"hel", "lo\nwor", "ld\n"}, "> hello\n> world\n"},
+		{"no trailing newline", []string{"a\nb"}, "> a\n> b"},
+		{"progress updates", []string{"Pushing: 10%\rPushing: 50%\rPushing: 100%\n"}, "> Pushing: 10%\r> Pushing: 50%\r> Pushing: 100%\n"},
+		{"crlf", []string{"a\r\nb\r\n"}, "> a\r\n> b\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			pw := &prefixWriter{prefix: "> ", w: &buf}
+			for _, w := range tt.writes {
+				n, err := pw.Write([]byte(w))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if n != len(w) {
+					t.Errorf("Write returned %d, want %d", n, len(w))
+				}
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestImageCreate_RestartsRunningContainer(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runImageCreate(nil, []string{"dev1", "my-image"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("start", "dev1") {
+		t.Error("expected running container to be restarted")
+	}
+	if strings.Contains(out, "is stopped") {
+		t.Errorf("unexpected stopped notice, got:\n%s", out)
+	}
+}
+
+func TestImageCreate_NoRestart(t *testing.T) {
+	env := setupTestEnv(t)
+	imageCreateNoRestart = true
+	t.Cleanup(func() { imageCreateNoRestart = false })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runImageCreate(nil, []string{"dev1", "my-image"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("stop", "dev1") {
+		t.Error("expected stop command for running container")
+	}
+	if env.mock.HasCallPrefix("start") {
+		t.Error("container should not be restarted with --no-restart")
+	}
+	if !strings.Contains(out, "Container 'dev1' is stopped") || !strings.Contains(out, "lxc-dev-manager up dev1") {
+		t.Errorf("expected stopped notice with start hint, got:\n%s", out)
+	}
+}
+
+func TestImageCreate_InvalidAlias(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runImageCreate(nil, []string{"dev1", "my_image"})
+	if err == nil || !strings.Contains(err.Error(), "invalid characters") {
+		t.Errorf("expected invalid alias error, got %v", err)
+	}
+	if env.mock.CallCount() != 0 {
+		t.Errorf("no lxc commands should run for an invalid alias, got %v", env.mock.Calls)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"lxc-dev-manager/internal/lxc"
+	"lxc-dev-manager/internal/validation"
+
+	"github.com/spf13/cobra"
+)
+
+var imageExportCmd = &cobra.Command{
+	Use:   "export <image> <path>",
+	Short: "Export an image to a file",
+	Long: `Write a local image to a file so it can be copied to another machine.
+
+lxc adds the file extension for the image format (usually .tar.gz), so
+pass the path without one. Load the file elsewhere with 'image import'.
+
+Example:
+  lxc-dev-manager image export my-base-image ./my-base-image`,
+	Args: cobra.ExactArgs(2),
+	RunE: runImageExport,
+}
+
+// imageExportCmd is registered in image.go init()
+
+func runImageExport(cmd *cobra.Command, args []string) error {
+	alias := args[0]
+	path := hostPath(args[1])
+
+	if err := validation.ValidateImageName(alias); err != nil {
+		return err
+	}
+
+	if _, err := lxc.GetImageFingerprint(alias); err != nil {
+		if errors.Is(err, lxc.ErrNotFound) {
+			return fmt.Errorf("image '%s' not found", alias)
+		}
+		return err
+	}
+
+	printInfo("Exporting image '%s' to '%s'...\n", alias, path)
+
+	ctx, stop := interruptContext()
+	defer stop()
+	if err := lxc.ExportImageWithProgress(ctx, alias, path,
+		&prefixWriter{prefix: "  ", w: infoOutput()},
+		&prefixWriter{prefix: "  ", w: os.Stderr}); err != nil {
+		return err
+	}
+
+	printInfo("\nImage '%s' exported\n", alias)
+	printInfo("Import it elsewhere with: lxc-dev-manager image import <file> %s\n", alias)
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImageExport_Success(t *testing.T) {
+	env := setupTestEnv(t)
+	env.mock.SetOutput("image list my-base --format=csv -c f", "abc123def456")
+
+	env.captureStdout(func() {
+		if err := runImageExport(nil, []string{"my-base", "/tmp/my-base"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("image", "export", "my-base", "/tmp/my-base") {
+		t.Errorf("expected image export call, got %v", env.mock.Calls)
+	}
+}
+
+func TestImageExport_NotFound(t *testing.T) {
+	env := setupTestEnv(t)
+	env.mock.SetOutput("image list my-base --format=csv -c f", "")
+
+	err := runImageExport(nil, []string{"my-base", "/tmp/my-base"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if env.mock.HasCallPrefix("image", "export") {
+		t.Error("should not export a missing image")
+	}
+}
+
+func TestImageExport_ExistingAliasForms(t *testing.T) {
+	// Aliases LXD accepts but new aliases may not use, and a fingerprint
+	for _, image := range []string{"ubuntu/24.04", "my_base", "debian-12.1", "abc123def456"} {
+		t.Run(image, func(t *testing.T) {
+			env := setupTestEnv(t)
+			env.mock.SetOutput("image list "+image+" --format=csv -c f", "abc123def456")
+
+			env.captureStdout(func() {
+				if err := runImageExport(nil, []string{image, "/tmp/out"}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+			if !env.mock.HasCall("image", "export", image, "/tmp/out") {
+				t.Errorf("expected image export call, got %v", env.mock.Calls)
+			}
+		})
+	}
+}
+
+func TestImageExport_InvalidAlias(t *testing.T) {
+	env := setupTestEnv(t)
+
+	err := runImageExport(nil, []string{"my base", "/tmp/my-base"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if env.mock.HasCallPrefix("image", "export") {
+		t.Error("should not export with an invalid alias")
+	}
+}
+
+func TestImageExport_Error(t *testing.T) {
+	env := setupTestEnv(t)
+	env.mock.SetOutput("image list my-base --format=csv -c f", "abc123def456")
+	env.mock.SetError("image export my-base /tmp/my-base", "disk full")
+
+	env.captureStdout(func() {
+		err := runImageExport(nil, []string{"my-base", "/tmp/my-base"})
+		if err == nil || !strings.Contains(err.Error(), "failed to export image") {
+			t.Errorf("expected export error, got %v", err)
+		}
+	})
+}
+
+func TestImageExport_RelativeToWorkDir(t *testing.T) {
+	env := setupTestEnv(t)
+	workDir = "/home/user/project/src"
+	env.mock.SetOutput("image list my-base --format=csv -c f", "abc123def456")
+
+	env.captureStdout(func() {
+		if err := runImageExport(nil, []string{"my-base", "./my-base"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("image", "export", "my-base", "/home/user/project/src/my-base") {
+		t.Errorf("expected the path to resolve against the invocation directory, got %v", env.mock.Calls)
+	}
+}
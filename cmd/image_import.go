@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lxc-dev-manager/internal/lxc"
+	"lxc-dev-manager/internal/validation"
+
+	"github.com/spf13/cobra"
+)
+
+var imageImportCmd = &cobra.Command{
+	Use:   "import <path> [image]",
+	Short: "Import an image from a file",
+	Long: `Load an image file written by 'image export' into the local image store.
+
+The image alias defaults to the file name without its extension.
+
+Examples:
+  lxc-dev-manager image import ./my-base-image.tar.gz
+  lxc-dev-manager image import ./my-base-image.tar.gz team-base`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runImageImport,
+}
+
+// imageImportCmd is registered in image.go init()
+
+// imageFileExtensions are stripped from the file name to get the default alias
+var imageFileExtensions = []string{".tar.gz", ".tar.xz", ".tar.bz2", ".tar.zst", ".tar", ".squashfs"}
+
+// importAlias derives an image alias from an image file path
+func importAlias(path string) string {
+	name := filepath.Base(path)
+	for _, ext := range imageFileExtensions {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+	return name
+}
+
+func runImageImport(cmd *cobra.Command, args []string) error {
+	path := hostPath(args[0])
+
+	alias := importAlias(path)
+	if len(args) > 1 {
+		alias = args[1]
+	}
+	if err := validation.ValidateImageAlias(alias); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("cannot read image file: %w", err)
+	}
+
+	if _, err := lxc.GetImageFingerprint(alias); err == nil {
+		return fmt.Errorf("image '%s' already exists", alias)
+	} else if !errors.Is(err, lxc.ErrNotFound) {
+		return err
+	}
+
+	printInfo("Importing '%s' as image '%s'...\n", path, alias)
+
+	ctx, stop := interruptContext()
+	defer stop()
+	if err := lxc.ImportImageWithProgress(ctx, path, alias,
+		&prefixWriter{prefix: "  ", w: infoOutput()},
+		&prefixWriter{prefix: "  ", w: os.Stderr}); err != nil {
+		return err
+	}
+
+	printInfo("\nImage '%s' imported\n\n", alias)
+	if img, found := findImage(alias); found {
+		printImageTable([]lxc.ImageInfo{img})
+	}
+	return nil
+}
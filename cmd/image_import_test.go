@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeImageFile creates an empty image file in the test directory
+func writeImageFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImageImport_DefaultAlias(t *testing.T) {
+	env := setupTestEnv(t)
+	path := writeImageFile(t, env.dir, "my-base.tar.gz")
+
+	env.captureStdout(func() {
+		if err := runImageImport(nil, []string{path}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("image", "import", path, "--alias", "my-base") {
+		t.Errorf("expected image import call, got %v", env.mock.Calls)
+	}
+}
+
+func TestImageImport_WithAlias(t *testing.T) {
+	env := setupTestEnv(t)
+	path := writeImageFile(t, env.dir, "my-base.tar.gz")
+
+	env.captureStdout(func() {
+		if err := runImageImport(nil, []string{path, "team-base"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("image", "import", path, "--alias", "team-base") {
+		t.Errorf("expected image import call, got %v", env.mock.Calls)
+	}
+}
+
+func TestImageImport_AliasExists(t *testing.T) {
+	env := setupTestEnv(t)
+	path := writeImageFile(t, env.dir, "my-base.tar.gz")
+	env.mock.SetOutput("image list my-base --format=csv -c f", "abc123def456")
+
+	err := runImageImport(nil, []string{path})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, got %v", err)
+	}
+	if env.mock.HasCallPrefix("image", "import") {
+		t.Error("should not import over an existing alias")
+	}
+}
+
+func TestImageImport_MissingFile(t *testing.T) {
+	env := setupTestEnv(t)
+
+	err := runImageImport(nil, []string{filepath.Join(env.dir, "nope.tar.gz")})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if env.mock.HasCallPrefix("image", "import") {
+		t.Error("should not import a missing file")
+	}
+}
+
+func TestImageImport_InvalidAlias(t *testing.T) {
+	env := setupTestEnv(t)
+	path := writeImageFile(t, env.dir, "my-base.tar.gz")
+
+	if err := runImageImport(nil, []string{path, "Bad_Alias"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestImportAlias(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"my-base.tar.gz", "my-base"},
+		{"/images/my-base.tar.xz", "my-base"},
+		{"dir/my-base.squashfs", "my-base"},
+		{"my-base", "my-base"},
+	}
+
+	for _, tt := range tests {
+		if got := importAlias(tt.path); got != tt.want {
+			t.Errorf("importAlias(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestImageImport_RelativeToWorkDir(t *testing.T) {
+	env := setupTestEnv(t)
+	sub := filepath.Join(env.dir, "src")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := writeImageFile(t, sub, "my-base.tar.gz")
+	workDir = sub
+
+	env.captureStdout(func() {
+		if err := runImageImport(nil, []string{"./my-base.tar.gz"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("image", "import", path, "--alias", "my-base") {
+		t.Errorf("expected the file to resolve against the invocation directory, got %v", env.mock.Calls)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var imagePullCmd = &cobra.Command{
+	Use:   "pull <remote>:<image>",
+	Short: "Download an image from a remote",
+	Long: `Download an image from a remote image server into the local image store.
+
+The remote's aliases are copied along with the image. Use --alias to add
+a local alias of your own.
+
+Examples:
+  lxc-dev-manager image pull ubuntu:24.04
+  lxc-dev-manager image pull images:debian/12 --alias debian-base`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImagePull,
+}
+
+// imagePullCmd is registered in image.go init()
+
+var imagePullAlias string
+
+// pullImage downloads the image; replaced in tests
+var pullImage = lxc.PullImageWithProgress
+
+func init() {
+	imagePullCmd.Flags().StringVar(&imagePullAlias, "alias", "", "Local alias for the pulled image")
+}
+
+func runImagePull(cmd *cobra.Command, args []string) error {
+	source := args[0]
+
+	remoteName, image, ok := strings.Cut(source, ":")
+	if !ok || remoteName == "" || image == "" {
+		return fmt.Errorf("invalid image %q: expected <remote>:<image>, e.g. ubuntu:24.04", source)
+	}
+
+	if imagePullAlias != "" && lxc.ImageExists(imagePullAlias) {
+		return fmt.Errorf("image '%s' already exists", imagePullAlias)
+	}
+
+	printInfo("Pulling image '%s'...\n", source)
+
+	ctx, stop := interruptContext()
+	defer stop()
+	if err := pullImage(ctx, source, imagePullAlias,
+		&prefixWriter{prefix: "  ", w: infoOutput()},
+		&prefixWriter{prefix: "  ", w: os.Stderr}); err != nil {
+		return err
+	}
+
+	printInfo("\nImage '%s' pulled\n\n", source)
+
+	// Aliases are copied from the remote, so the image name works as a local alias too
+	alias := imagePullAlias
+	if alias == "" {
+		alias = image
+	}
+	if img, found := findImage(alias); found {
+		printImageTable([]lxc.ImageInfo{img})
+	}
+
+	return nil
+}
+
+// findImage looks up a local image by alias
+func findImage(alias string) (lxc.ImageInfo, bool) {
+	fp, err := lxc.GetImageFingerprint(alias)
+	if err != nil {
+		return lxc.ImageInfo{}, false
+	}
+
+	images, err := lxc.ListImages(true)
+	if err != nil {
+		return lxc.ImageInfo{}, false
+	}
+	for _, img := range images {
+		if strings.HasPrefix(fp, img.Fingerprint) || strings.HasPrefix(img.Fingerprint, fp) {
+			return img, true
+		}
+	}
+	return lxc.ImageInfo{}, false
+}
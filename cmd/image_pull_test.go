@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakePull replaces pullImage and records the source and alias it was called with
+func fakePull(t *testing.T, err error) *[]string {
+	t.Helper()
+	var calls []string
+	old := pullImage
+	pullImage = func(ctx context.Context, source, alias string, stdout, stderr io.Writer) error {
+		calls = append(calls, source+"|"+alias)
+		return err
+	}
+	t.Cleanup(func() {
+		pullImage = old
+		imagePullAlias = ""
+	})
+	return &calls
+}
+
+func TestImagePull_PrintsPulledImage(t *testing.T) {
+	env := setupTestEnv(t)
+	calls := fakePull(t, nil)
+	env.mock.SetOutput("image list 24.04 --format=csv -c f", "abc123def456")
+	env.mock.SetOutput("image list --format=csv -c lfsd", `my-base,999999999999,500MiB,Custom
+24.04,abc123def456,250MiB,ubuntu 24.04 LTS amd64`)
+
+	out := env.captureStdout(func() {
+		if err := runImagePull(nil, []string{"ubuntu:24.04"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if len(*calls) != 1 || (*calls)[0] != "ubuntu:24.04|" {
+		t.Errorf("unexpected pull calls: %v", *calls)
+	}
+	if !strings.Contains(out, "abc123def456") || !strings.Contains(out, "250MiB") {
+		t.Errorf("expected pulled image row, got:\n%s", out)
+	}
+	if strings.Contains(out, "my-base") {
+		t.Errorf("only the pulled image should be shown, got:\n%s", out)
+	}
+}
+
+func TestImagePull_WithAlias(t *testing.T) {
+	env := setupTestEnv(t)
+	calls := fakePull(t, nil)
+	imagePullAlias = "noble"
+	// The alias only resolves once the image has been pulled
+	recordPull := pullImage
+	pullImage = func(ctx context.Context, source, alias string, stdout, stderr io.Writer) error {
+		env.mock.SetOutput("image list noble --format=csv -c f", "abc123def456")
+		return recordPull(ctx, source, alias, stdout, stderr)
+	}
+	env.mock.SetOutput("image list --format=csv -c lfsd", "noble,abc123def456,250MiB,ubuntu")
+
+	out := env.captureStdout(func() {
+		if err := runImagePull(nil, []string{"ubuntu:24.04"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if len(*calls) != 1 || (*calls)[0] != "ubuntu:24.04|noble" {
+		t.Errorf("unexpected pull calls: %v", *calls)
+	}
+	if !strings.Contains(out, "noble") {
+		t.Errorf("expected aliased image row, got:\n%s", out)
+	}
+}
+
+func TestImagePull_AliasExists(t *testing.T) {
+	env := setupTestEnv(t)
+	calls := fakePull(t, nil)
+	imagePullAlias = "my-base"
+	env.mock.SetOutput("image list my-base --format=csv -c f", "abc123")
+
+	err := runImagePull(nil, []string{"ubuntu:24.04"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, got: %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Error("should not pull when alias is taken")
+	}
+}
+
+func TestImagePull_InvalidSource(t *testing.T) {
+	setupTestEnv(t)
+	fakePull(t, nil)
+
+	for _, source := range []string{"ubuntu", ":24.04", "ubuntu:"} {
+		if err := runImagePull(nil, []string{source}); err == nil {
+			t.Errorf("expected error for %q", source)
+		}
+	}
+}
+
+func TestImagePull_Fails(t *testing.T) {
+	env := setupTestEnv(t)
+	fakePull(t, fmt.Errorf("failed to pull image: exit status 1"))
+
+	var err error
+	env.captureStdout(func() {
+		err = runImagePull(nil, []string{"ubuntu:24.04"})
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+
+	"lxc-dev-manager/internal/lxc"
 )
 
 func TestImageList_Empty(t *testing.T) {
@@ -79,6 +82,39 @@ func TestImageDelete_Success(t *testing.T) {
 	}
 }
 
+func TestImageDelete_ConfirmYes(t *testing.T) {
+	env := setupTestEnv(t)
+	env.mock.SetOutput("image list my-base --format=csv -c f", "abc123def456")
+	env.mock.SetOutput("image delete my-base", "")
+	env.setPromptInput("yes\n")
+
+	env.captureStdout(func() {
+		if err := runImageDelete(nil, []string{"my-base"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("image", "delete", "my-base") {
+		t.Error("expected image delete command after confirming")
+	}
+}
+
+func TestImageDelete_ConfirmNo(t *testing.T) {
+	env := setupTestEnv(t)
+	env.mock.SetOutput("image list my-base --format=csv -c f", "abc123def456")
+	env.setPromptInput("n\n")
+
+	env.captureStdout(func() {
+		if err := runImageDelete(nil, []string{"my-base"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if env.mock.HasCallPrefix("image", "delete") {
+		t.Error("image should not be deleted when declined")
+	}
+}
+
 func TestImageDelete_NotFound(t *testing.T) {
 	env := setupTestEnv(t)
 	withImageDeleteForce(t)
@@ -94,6 +130,24 @@ func TestImageDelete_NotFound(t *testing.T) {
 	}
 }
 
+func TestImageDelete_LookupError(t *testing.T) {
+	env := setupTestEnv(t)
+	withImageDeleteForce(t)
+
+	env.mock.SetError("image list my-base --format=csv -c f", "permission denied")
+
+	err := runImageDelete(nil, []string{"my-base"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), "not found") {
+		t.Errorf("lookup failure should not be reported as not found: %v", err)
+	}
+	if env.mock.HasCallPrefix("image", "delete") {
+		t.Error("should not delete when lookup fails")
+	}
+}
+
 func TestImageDelete_Error(t *testing.T) {
 	env := setupTestEnv(t)
 	withImageDeleteForce(t)
@@ -158,6 +212,18 @@ func TestImageRename_NewAlreadyExists(t *testing.T) {
 	}
 }
 
+func TestImageRename_InvalidNewName(t *testing.T) {
+	env := setupTestEnv(t)
+
+	err := runImageRename(nil, []string{"old-name", "new name"})
+	if err == nil || !strings.Contains(err.Error(), "cannot contain spaces") {
+		t.Errorf("expected invalid alias error, got %v", err)
+	}
+	if env.mock.CallCount() != 0 {
+		t.Errorf("no lxc commands should run for an invalid alias, got %v", env.mock.Calls)
+	}
+}
+
 func TestImageRename_AliasCreateFails(t *testing.T) {
 	env := setupTestEnv(t)
 	env.mock.SetOutput("image list old-name --format=csv -c f", "abc123")
@@ -169,3 +235,114 @@ func TestImageRename_AliasCreateFails(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestImageList_JSONFormat(t *testing.T) {
+	env := setupTestEnv(t)
+	imageListFormat = "json"
+	t.Cleanup(func() { imageListFormat = formatTable })
+	env.mock.SetOutput("image list --format=csv -c lfsd", `my-base,abc123def456,500MiB,Ubuntu 24.04`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runImageList(nil, []string{})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var images []lxc.ImageInfo
+	if err := json.Unmarshal([]byte(out), &images); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(images) != 1 || images[0].Alias != "my-base" || images[0].Fingerprint != "abc123def456" {
+		t.Errorf("unexpected images: %+v", images)
+	}
+}
+
+func setImageListFilter(t *testing.T, pattern string) {
+	t.Helper()
+	imageListFilter = pattern
+	t.Cleanup(func() { imageListFilter = "" })
+}
+
+const filterImageCSV = `ubuntu-base,abc123,500MiB,Ubuntu
+ubuntu-dev,bcd234,700MiB,Ubuntu dev
+dev-node,cde345,900MiB,Node
+my-dev-tools,def456,300MiB,Tools`
+
+func TestImageList_FilterGlob(t *testing.T) {
+	env := setupTestEnv(t)
+	setImageListFilter(t, "ubuntu*")
+	env.mock.SetOutput("image list --format=csv -c lfsd", filterImageCSV)
+
+	out := env.captureStdout(func() {
+		if err := runImageList(nil, []string{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for _, want := range []string{"ubuntu-base", "ubuntu-dev"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in output, got:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"dev-node", "my-dev-tools"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("did not expect %s in output, got:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestImageList_FilterSubstring(t *testing.T) {
+	env := setupTestEnv(t)
+	setImageListFilter(t, "dev-")
+	env.mock.SetOutput("image list --format=csv -c lfsd", filterImageCSV)
+
+	out := env.captureStdout(func() {
+		if err := runImageList(nil, []string{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for _, want := range []string{"dev-node", "my-dev-tools"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ubuntu") {
+		t.Errorf("did not expect ubuntu images in output, got:\n%s", out)
+	}
+}
+
+func TestImageList_FilterNoMatch(t *testing.T) {
+	env := setupTestEnv(t)
+	setImageListFilter(t, "alpine*")
+	env.mock.SetOutput("image list --format=csv -c lfsd", filterImageCSV)
+
+	err := runImageList(nil, []string{})
+	if err == nil || !strings.Contains(err.Error(), `no custom images match "alpine*"`) {
+		t.Errorf("expected no-match error, got %v", err)
+	}
+}
+
+func TestImageList_FilterInvalidGlob(t *testing.T) {
+	env := setupTestEnv(t)
+	setImageListFilter(t, "[ubuntu*")
+	env.mock.SetOutput("image list --format=csv -c lfsd", filterImageCSV)
+
+	err := runImageList(nil, []string{})
+	if err == nil || !strings.Contains(err.Error(), "invalid filter") {
+		t.Errorf("expected invalid filter error, got %v", err)
+	}
+}
+
+func TestImageList_InvalidFormat(t *testing.T) {
+	setupTestEnv(t)
+	imageListFormat = "xml"
+	t.Cleanup(func() { imageListFormat = formatTable })
+
+	err := runImageList(nil, []string{})
+	if err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+}
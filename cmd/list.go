@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"fmt"
+	"os"
 	"strings"
 
+	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 
 	"github.com/spf13/cobra"
@@ -14,29 +17,74 @@ var listCmd = &cobra.Command{
 	Short: "List all containers",
 	Long: `List all containers defined in the config with their status.
 
+Use --json (or --format json) for machine-readable output, or
+--format csv for spreadsheets and shell pipelines.
+
 Example:
-  lxc-dev-manager list`,
+  lxc-dev-manager list
+  lxc-dev-manager list --json
+  lxc-dev-manager list --format csv`,
 	Args: cobra.NoArgs,
 	RunE: runList,
 }
 
+var (
+	listFormat string
+	listJSON   bool
+)
+
 func init() {
 	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().StringVar(&listFormat, "format", formatTable, "Output format (table, json, csv)")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output JSON (same as --format json)")
+}
+
+// listEntry is one container row of 'list' output
+type listEntry struct {
+	Name   string   `json:"name"`
+	Image  string   `json:"image"`
+	Status string   `json:"status"`
+	IP     string   `json:"ip"`
+	Ports  []string `json:"ports"`
+}
+
+// resolveListFormat combines --json and --format into a single output format
+func resolveListFormat() (string, error) {
+	format := listFormat
+	if listJSON {
+		if format != formatTable && format != formatJSON {
+			return "", fmt.Errorf("--json cannot be combined with --format %s", format)
+		}
+		format = formatJSON
+	}
+
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return format, nil
+	case "":
+		return formatTable, nil
+	default:
+		return "", fmt.Errorf("unknown format %q (must be %s, %s or %s)", format, formatTable, formatJSON, formatCSV)
+	}
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	cfg, err := requireProject()
+	format, err := resolveListFormat()
 	if err != nil {
 		return err
 	}
 
-	// Show project header
-	fmt.Printf("Project: %s\n\n", cfg.Project)
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
 
 	if len(cfg.Containers) == 0 {
-		fmt.Println("No containers defined in config")
-		fmt.Println("Create one with: lxc-dev-manager container create <name> <image>")
-		return nil
+		return printListEntries(format, []listEntry{}, func() {
+			fmt.Printf("Project: %s\n\n", cfg.Project)
+			fmt.Println("No containers defined in config")
+			fmt.Println("Create one with: lxc-dev-manager container create <name> <image>")
+		})
 	}
 
 	// Get all LXC container info
@@ -51,43 +99,80 @@ func runList(cmd *cobra.Command, args []string) error {
 		lxcInfo[c.Name] = c
 	}
 
-	// Print header
-	fmt.Printf("%-15s %-20s %-10s %-15s %s\n", "NAME", "IMAGE", "STATUS", "IP", "PORTS")
-	fmt.Println(strings.Repeat("-", 75))
+	// Collect status for each container from config, keyed by SHORT name
+	names := containerNames(cfg)
+	entries := make([]listEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, buildListEntry(cfg, name, lxcInfo))
+	}
 
-	// Print each container from config
-	for name, container := range cfg.Containers {
-		// Get full LXC name with prefix
-		lxcName := cfg.GetLXCName(name)
+	return printListEntries(format, entries, func() {
+		// Show project header
+		fmt.Printf("Project: %s\n\n", cfg.Project)
 
-		status := "NOT FOUND"
-		ip := "-"
+		// Print header
+		fmt.Printf("%-15s %-20s %-10s %-15s %s\n", "NAME", "IMAGE", "STATUS", "IP", "PORTS")
+		fmt.Println(strings.Repeat("-", 75))
 
-		if info, ok := lxcInfo[lxcName]; ok {
-			status = info.Status
-			if info.IP != "" {
-				ip = info.IP
+		for _, entry := range entries {
+			ip := entry.IP
+			if ip == "" {
+				ip = "-"
 			}
+
+			portStr := "-"
+			if len(entry.Ports) > 0 {
+				portStr = strings.Join(entry.Ports, ",")
+			}
+
+			// Display SHORT name, not LXC name
+			fmt.Printf("%-15s %-20s %-10s %-15s %s\n", entry.Name, entry.Image, entry.Status, ip, portStr)
 		}
+	})
+}
 
-		ports := cfg.GetPorts(name)
-		portStr := formatPorts(ports)
+// buildListEntry assembles the row for a configured container.
+// Containers missing from LXC are reported as NOT FOUND with an empty IP.
+func buildListEntry(cfg *config.Config, name string, lxcInfo map[string]lxc.ContainerInfo) listEntry {
+	entry := listEntry{
+		Name:   name,
+		Image:  cfg.Containers[name].Image,
+		Status: "NOT FOUND",
+		Ports:  []string{},
+	}
+	if info, ok := lxcInfo[cfg.GetLXCName(name)]; ok {
+		entry.Status = info.Status
+		entry.IP = info.IP
+	}
+	for _, p := range cfg.GetPortMappings(name) {
+		entry.Ports = append(entry.Ports, p.String())
+	}
+	return entry
+}
 
-		// Display SHORT name, not LXC name
-		fmt.Printf("%-15s %-20s %-10s %-15s %s\n", name, container.Image, status, ip, portStr)
+// printListEntries renders entries as CSV, or hands off to formatOutput for table and JSON
+func printListEntries(format string, entries []listEntry, printTable func()) error {
+	if format != formatCSV {
+		return formatOutput(format, entries, printTable)
 	}
 
-	return nil
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"name", "image", "status", "ip", "ports"})
+	for _, e := range entries {
+		w.Write([]string{e.Name, e.Image, e.Status, e.IP, strings.Join(e.Ports, ",")})
+	}
+	w.Flush()
+	return w.Error()
 }
 
-func formatPorts(ports []int) string {
+func formatPorts(ports []config.PortMapping) string {
 	if len(ports) == 0 {
 		return "-"
 	}
 
 	strs := make([]string, len(ports))
 	for i, p := range ports {
-		strs[i] = fmt.Sprintf("%d", p)
+		strs[i] = p.String()
 	}
 	return strings.Join(strs, ",")
 }
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/lxc"
 )
 
 func TestList_Empty(t *testing.T) {
@@ -120,3 +123,196 @@ func TestList_LXCError(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestList_JSONFormat(t *testing.T) {
+	env := setupTestEnv(t)
+	listFormat = "json"
+	t.Cleanup(func() { listFormat = formatTable })
+
+	env.writeConfig(`project: web
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: my-image
+`)
+	env.setListAllContainers(`web-dev1,RUNNING,10.10.10.45 (eth0)`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runList(nil, []string{})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entries []lxc.ContainerInfo
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Name != "dev1" || entries[0].Status != "RUNNING" || entries[0].IP != "10.10.10.45" {
+		t.Errorf("unexpected dev1 entry: %+v", entries[0])
+	}
+	if entries[1].Name != "dev2" || entries[1].Status != "NOT FOUND" {
+		t.Errorf("unexpected dev2 entry: %+v", entries[1])
+	}
+}
+
+func TestList_JSONFormatEmpty(t *testing.T) {
+	env := setupTestEnv(t)
+	listFormat = "json"
+	t.Cleanup(func() { listFormat = formatTable })
+	env.writeMinimalConfig()
+
+	out := env.captureStdout(func() {
+		if err := runList(nil, []string{}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if strings.TrimSpace(out) != "[]" {
+		t.Errorf("expected empty JSON array, got %q", out)
+	}
+}
+
+func TestList_JSONFlagMixedStatus(t *testing.T) {
+	env := setupTestEnv(t)
+	listJSON = true
+	t.Cleanup(func() { listJSON = false })
+
+	env.writeConfig(`defaults:
+  ports: [8000]
+containers:
+  api:
+    image: ubuntu:24.04
+    ports: [3000, "8080:80"]
+  db:
+    image: postgres-base
+  web:
+    image: node-base
+`)
+	env.setListAllContainers(`api,RUNNING,10.10.10.1 (eth0)
+db,STOPPED,`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runList(nil, []string{})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entries []listEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+
+	want := []listEntry{
+		{Name: "api", Image: "ubuntu:24.04", Status: "RUNNING", IP: "10.10.10.1", Ports: []string{"3000", "8080:80"}},
+		{Name: "db", Image: "postgres-base", Status: "STOPPED", IP: "", Ports: []string{"8000"}},
+		{Name: "web", Image: "node-base", Status: "NOT FOUND", IP: "", Ports: []string{"8000"}},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Name != w.Name || e.Image != w.Image || e.Status != w.Status || e.IP != w.IP ||
+			strings.Join(e.Ports, " ") != strings.Join(w.Ports, " ") {
+			t.Errorf("entry %d = %+v, want %+v", i, e, w)
+		}
+	}
+
+	// Missing containers keep the ip key so consumers can rely on it
+	if !strings.Contains(out, `"ip": ""`) {
+		t.Errorf("expected empty ip field in output, got:\n%s", out)
+	}
+}
+
+func TestList_CSVFormat(t *testing.T) {
+	env := setupTestEnv(t)
+	listFormat = "csv"
+	t.Cleanup(func() { listFormat = formatTable })
+
+	env.writeConfig(`containers:
+  api:
+    image: ubuntu:24.04
+    ports: [3000, "8080:80"]
+  web:
+    image: node-base
+`)
+	env.setListAllContainers(`api,RUNNING,10.10.10.1 (eth0)`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runList(nil, []string{})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `name,image,status,ip,ports
+api,ubuntu:24.04,RUNNING,10.10.10.1,"3000,8080:80"
+web,node-base,NOT FOUND,,
+`
+	if out != want {
+		t.Errorf("unexpected CSV output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestList_JSONFlagConflictsWithFormat(t *testing.T) {
+	env := setupTestEnv(t)
+	listJSON = true
+	listFormat = "csv"
+	t.Cleanup(func() {
+		listJSON = false
+		listFormat = formatTable
+	})
+	env.writeMinimalConfig()
+
+	err := runList(nil, []string{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "--json cannot be combined") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestList_InvalidFormat(t *testing.T) {
+	env := setupTestEnv(t)
+	listFormat = "yaml"
+	t.Cleanup(func() { listFormat = formatTable })
+	env.writeMinimalConfig()
+
+	err := runList(nil, []string{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestList_ShowsPortMappings(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`containers:
+  web:
+    image: ubuntu
+    ports: [3000, "8080:80"]
+`)
+	env.setListAllContainers(`web,RUNNING,10.10.10.1 (eth0)`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runList(nil, []string{})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "3000,8080:80") {
+		t.Errorf("expected remapped port in output:\n%s", out)
+	}
+}
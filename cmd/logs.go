@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <name>",
+	Short: "Show the systemd journal of a container",
+	Long: `Show systemd journal output from a container.
+
+Shortcut for 'container logs', with the same flags.
+
+Examples:
+  lxc-dev-manager logs dev1
+  lxc-dev-manager logs dev1 -f -s nginx`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContainerLogs,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	addLogsFlags(logsCmd)
+}
+
+// buildLogsArgs constructs the journalctl command to run in the container
+func buildLogsArgs(lines int, follow bool, service string) []string {
+	args := []string{"journalctl", "-n", strconv.Itoa(lines), "--no-pager"}
+	if service != "" {
+		args = append(args, "-u", service)
+	}
+	if follow {
+		args = append(args, "-f")
+	}
+	return args
+}
+
+// streamLogs streams journalctl output from a running container to stdout
+func streamLogs(name string, lines int, follow bool, service string) error {
+	if lines < 1 {
+		return fmt.Errorf("--lines must be at least 1")
+	}
+
+	_, lxcName, err := requireRunningContainer(name)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+C stops following without reporting an error
+	ctx, stop := interruptContext()
+	defer stop()
+
+	err = lxc.ExecStream(ctx, lxcName, os.Stdout, os.Stderr, buildLogsArgs(lines, follow, service)...)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
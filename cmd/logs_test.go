@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogs_NotRunning(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+
+	err := runContainerLogs(logsCmd, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "not running") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLogs_SameFlagsAsContainerLogs(t *testing.T) {
+	for _, name := range []string{"follow", "lines", "service"} {
+		top, sub := logsCmd.Flags().Lookup(name), containerLogsCmd.Flags().Lookup(name)
+		if top == nil || sub == nil {
+			t.Fatalf("--%s should be on both logs commands", name)
+		}
+		if top.Shorthand != sub.Shorthand || top.DefValue != sub.DefValue {
+			t.Errorf("--%s differs: -%s %s vs -%s %s", name, top.Shorthand, top.DefValue, sub.Shorthand, sub.DefValue)
+		}
+	}
+}
+
+func TestLogs_Service(t *testing.T) {
+	env := setupTestEnv(t)
+	logsService = "nginx"
+	logsLines = 100
+	t.Cleanup(func() { logsService = "" })
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	if err := runContainerLogs(logsCmd, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCall("exec", "dev1", "--", "journalctl", "-n", "100", "--no-pager", "-u", "nginx") {
+		t.Errorf("expected journalctl for nginx, got calls: %v", env.mock.Calls)
+	}
+}
+
+func TestBuildLogsArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    int
+		follow   bool
+		service  string
+		expected []string
+	}{
+		{"no follow", 100, false, "", []string{"journalctl", "-n", "100", "--no-pager"}},
+		{"follow", 100, true, "", []string{"journalctl", "-n", "100", "--no-pager", "-f"}},
+		{"custom lines", 20, false, "", []string{"journalctl", "-n", "20", "--no-pager"}},
+		{"service", 100, true, "nginx", []string{"journalctl", "-n", "100", "--no-pager", "-u", "nginx", "-f"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := buildLogsArgs(tt.lines, tt.follow, tt.service)
+			if strings.Join(args, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected %v, got %v", tt.expected, args)
+			}
+		})
+	}
+}
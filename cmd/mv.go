@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
-	"sort"
+	"regexp"
 	"strings"
 
 	"lxc-dev-manager/internal/config"
@@ -46,35 +48,6 @@ func parsePath(p string) pathSpec {
 	}
 }
 
-// matchContainers returns container names matching the glob pattern.
-// Supports: "*" (all containers), "prefix*" (containers starting with prefix)
-func matchContainers(cfg *config.Config, pattern string) []string {
-	var matches []string
-
-	if pattern == "*" {
-		// All containers
-		for name := range cfg.Containers {
-			matches = append(matches, name)
-		}
-	} else if strings.HasSuffix(pattern, "*") {
-		// Prefix match (e.g., "dev*")
-		prefix := strings.TrimSuffix(pattern, "*")
-		for name := range cfg.Containers {
-			if strings.HasPrefix(name, prefix) {
-				matches = append(matches, name)
-			}
-		}
-	} else {
-		// Exact match - return single container if it exists
-		if _, ok := cfg.Containers[pattern]; ok {
-			matches = append(matches, pattern)
-		}
-	}
-
-	sort.Strings(matches) // Consistent ordering
-	return matches
-}
-
 // validateContainer checks that a container exists in config and LXC
 func validateContainer(cfg *config.Config, name string) error {
 	if !cfg.HasContainer(name) {
@@ -87,27 +60,62 @@ func validateContainer(cfg *config.Config, name string) error {
 	return nil
 }
 
-// copyToContainer copies a file or directory from host to a single container
-func copyToContainer(cfg *config.Config, containerName, source, remotePath string, sourceInfo os.FileInfo, autoCreate bool) error {
-	lxcName := cfg.GetLXCName(containerName)
+// tildeUserRegex matches the login names that ~user is expanded for
+var tildeUserRegex = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
 
-	// Expand ~ to user's home directory
-	if strings.HasPrefix(remotePath, "~/") {
-		user := cfg.GetUser(containerName)
-		remotePath = "/home/" + user.Name + remotePath[1:]
-	} else if remotePath == "~" {
-		user := cfg.GetUser(containerName)
-		remotePath = "/home/" + user.Name
+// expandRemoteHome expands a leading ~ like a shell would: ~ and ~/path use
+// the container user's home directory, ~name and ~name/path that of user
+// name. Anything else (e.g. ~.cache) is returned unchanged.
+func expandRemoteHome(cfg *config.Config, containerName, remotePath string) string {
+	if !strings.HasPrefix(remotePath, "~") {
+		return remotePath
 	}
 
-	// Determine if recursive (directory)
-	recursive := sourceInfo.IsDir()
+	user, rest, hasSlash := strings.Cut(remotePath[1:], "/")
+	if user == "" {
+		user = cfg.GetUser(containerName).Name
+	} else if !tildeUserRegex.MatchString(user) {
+		return remotePath
+	}
+
+	home := remoteHomeDir(user)
+	if !hasSlash {
+		return home
+	}
+	return home + "/" + rest
+}
+
+// remoteHomeDir returns the home directory of a user in a container
+func remoteHomeDir(user string) string {
+	if user == "root" {
+		return "/root"
+	}
+	return "/home/" + user
+}
+
+// copyToContainer copies a file or directory (recursive) from host to a single container
+func copyToContainer(cfg *config.Config, containerName, source, remotePath string, recursive, autoCreate bool) error {
+	lxcName := cfg.GetLXCName(containerName)
+
+	// Expand ~ to user's home directory
+	remotePath = expandRemoteHome(cfg, containerName, remotePath)
 
 	// Get the destination directory to check/create
 	destDir := path.Dir(remotePath)
 
-	// Check if destination directory exists
+	// Push the file
+	pushPath := remotePath
+	if recursive {
+		pushPath = path.Dir(remotePath)
+	}
+
 	user := cfg.GetUser(containerName)
+	if mvDryRun {
+		printPushPlan(lxcName, source, destDir, pushPath, remotePath, user.Name, recursive)
+		return nil
+	}
+
+	// Check if destination directory exists
 	if !lxc.DirExists(lxcName, destDir) {
 		if !autoCreate && !confirmPrompt(fmt.Sprintf("Directory '%s' does not exist in %s. Create it?", destDir, containerName)) {
 			return fmt.Errorf("destination directory does not exist")
@@ -118,13 +126,20 @@ func copyToContainer(cfg *config.Config, containerName, source, remotePath strin
 		lxc.Exec(lxcName, "chown", user.Name+":"+user.Name, destDir)
 	}
 
-	// Push the file
-	pushPath := remotePath
 	if recursive {
-		pushPath = path.Dir(remotePath)
-	}
-
-	if err := lxc.FilePush(lxcName, source, pushPath, recursive); err != nil {
+		// Directories can be large, so show lxc's progress (Ctrl+C aborts the copy)
+		if files, size, err := dirSize(source); err == nil {
+			printInfo("  %d file(s), %s\n", files, formatBytes(size))
+		}
+		ctx, stop := interruptContext()
+		err := lxc.FilePushWithProgress(ctx, lxcName, source, pushPath, true,
+			&prefixWriter{prefix: "  ", w: infoOutput()},
+			&prefixWriter{prefix: "  ", w: os.Stderr})
+		stop()
+		if err != nil {
+			return err
+		}
+	} else if err := lxc.FilePush(lxcName, source, pushPath, false); err != nil {
 		return err
 	}
 
@@ -142,18 +157,130 @@ func copyToContainer(cfg *config.Config, containerName, source, remotePath strin
 	return nil
 }
 
+// dirSize counts the regular files under dir and their total size in bytes
+func dirSize(dir string) (files int, size int64, err error) {
+	err = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	return files, size, err
+}
+
+// stageDirectory copies the source directory to dest, skipping every path
+// that matches one of the exclude patterns. Patterns are matched against the
+// path relative to source; a pattern without a slash also matches any file
+// or directory by name, so "node_modules" is skipped at every depth.
+func stageDirectory(source, dest string, excludes []string) error {
+	return filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+		if rel != "." && isExcluded(rel, d.Name(), excludes) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		target := filepath.Join(dest, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyFile(p, target, info.Mode().Perm())
+		default:
+			// Sockets, devices and pipes cannot be pushed anyway
+			return nil
+		}
+	})
+}
+
+// isExcluded reports whether the relative path rel (whose last element is
+// name) matches one of the exclude patterns
+func isExcluded(rel, name string, excludes []string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range excludes {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyFile copies a regular file from src to dst with the given permissions
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// printPushPlan prints the lxc commands copyToContainer would run
+func printPushPlan(lxcName, source, destDir, pushPath, remotePath, user string, recursive bool) {
+	ref := lxc.InstanceRef(lxcName)
+	owner := user + ":" + user
+
+	if !lxc.DirExists(lxcName, destDir) {
+		dryRunf("Would run: lxc exec %s -- mkdir -p %s", ref, destDir)
+		dryRunf("Would run: lxc exec %s -- chown %s %s", ref, owner, destDir)
+	}
+	if recursive {
+		dryRunf("Would run: lxc file push -r %s %s/%s", source, ref, pushPath)
+		dryRunf("Would run: lxc exec %s -- chown -R %s %s", ref, owner, remotePath)
+	} else {
+		dryRunf("Would run: lxc file push %s %s/%s", source, ref, pushPath)
+		dryRunf("Would run: lxc exec %s -- chown %s %s", ref, owner, remotePath)
+	}
+}
+
 // copyFromContainer copies a file or directory from container to host
 func copyFromContainer(cfg *config.Config, containerName, remotePath, localPath string) error {
 	lxcName := cfg.GetLXCName(containerName)
 
 	// Expand ~ to user's home directory
-	if strings.HasPrefix(remotePath, "~/") {
-		user := cfg.GetUser(containerName)
-		remotePath = "/home/" + user.Name + remotePath[1:]
-	} else if remotePath == "~" {
-		user := cfg.GetUser(containerName)
-		remotePath = "/home/" + user.Name
-	}
+	remotePath = expandRemoteHome(cfg, containerName, remotePath)
 
 	// Check if source exists in container
 	if !lxc.FileExists(lxcName, remotePath) {
@@ -163,6 +290,15 @@ func copyFromContainer(cfg *config.Config, containerName, remotePath, localPath
 	// Determine if recursive (directory)
 	recursive := lxc.IsDir(lxcName, remotePath)
 
+	if mvDryRun {
+		flag := ""
+		if recursive {
+			flag = "-r "
+		}
+		dryRunf("Would run: lxc file pull %s%s/%s %s", flag, lxc.InstanceRef(lxcName), remotePath, localPath)
+		return nil
+	}
+
 	// Ensure local destination directory exists
 	localDir := filepath.Dir(localPath)
 	if err := os.MkdirAll(localDir, 0755); err != nil {
@@ -189,28 +325,47 @@ Supports multiple directions:
 
 Use * to target all containers, or prefix* to match by name prefix.
 
+Host sources may contain wildcards (quote them so the shell does not expand
+them). When several files match, each one is pushed into the destination,
+which must then be a directory.
+
 Examples:
   lxc-dev-manager mv ./app dev1:/home/dev/app       # host → container
   lxc-dev-manager mv ./config.json *:/etc/app/      # host → all containers
+  lxc-dev-manager mv './logs/*.log' dev1:/tmp/logs/ # host glob → container
   lxc-dev-manager mv dev1:/etc/config ./backup/     # container → host
   lxc-dev-manager mv dev1:/app/config *:/app/       # container → all containers
   lxc-dev-manager mv dev1:/data dev2:/data          # container → container
-  lxc-dev-manager mv ./data dev1:/opt/data -y       # auto-create directory`,
+  lxc-dev-manager mv ./data dev1:/opt/data -y       # auto-create directory
+  lxc-dev-manager mv ./app.conf '*:/etc/app/' --dry-run  # preview
+  lxc-dev-manager mv ./app dev1:~/app --exclude node_modules --exclude .git`,
 	Args: cobra.ExactArgs(2),
 	RunE: runMv,
 }
 
-var mvYes bool
+var (
+	mvYes     bool
+	mvDryRun  bool
+	mvExclude []string
+)
 
 func init() {
 	rootCmd.AddCommand(mvCmd)
 	mvCmd.Flags().BoolVarP(&mvYes, "yes", "y", false, "Auto-create destination directory if it doesn't exist")
+	mvCmd.Flags().BoolVar(&mvDryRun, "dry-run", false, "Show the copies that would run without copying")
+	mvCmd.Flags().StringArrayVar(&mvExclude, "exclude", nil, "Skip paths matching this pattern when copying a host directory (repeatable)")
 }
 
 func runMv(cmd *cobra.Command, args []string) error {
 	src := parsePath(args[0])
 	dst := parsePath(args[1])
 
+	for _, pattern := range mvExclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+
 	// Check for common mistake: container/path instead of container:/path
 	if !src.isContainer && !dst.isContainer {
 		// If destination looks like it might be a container path (starts with alphanumeric, contains /)
@@ -221,6 +376,13 @@ func runMv(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("both source and destination are local paths; use 'cp' instead")
 	}
 
+	if !src.isContainer {
+		src.path = hostPath(src.path)
+	}
+	if !dst.isContainer {
+		dst.path = hostPath(dst.path)
+	}
+
 	switch {
 	case !src.isContainer && dst.isContainer:
 		// Host → Container(s)
@@ -241,6 +403,21 @@ func runMv(cmd *cobra.Command, args []string) error {
 
 // hostToContainer handles copying from host to one or more containers
 func hostToContainer(src, dst pathSpec) error {
+	// Expand wildcards in the source (e.g. when quoted to bypass the shell)
+	if strings.ContainsAny(src.path, "*?[") {
+		files, err := filepath.Glob(src.path)
+		if err != nil {
+			return fmt.Errorf("invalid source pattern %q: %w", src.path, err)
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no files match %q", src.path)
+		}
+		if len(files) > 1 {
+			return hostFilesToContainer(files, dst)
+		}
+		src.path = files[0]
+	}
+
 	// Validate source exists on host
 	info, err := os.Stat(src.path)
 	if err != nil {
@@ -254,11 +431,30 @@ func hostToContainer(src, dst pathSpec) error {
 		return fmt.Errorf("destination path cannot be empty")
 	}
 
-	cfg, err := config.Load()
+	cfg, err := requireProject()
 	if err != nil {
 		return err
 	}
 
+	// lxc file push cannot skip files, so push a filtered copy instead
+	pushPath := src.path
+	if info.IsDir() && len(mvExclude) > 0 {
+		if mvDryRun {
+			dryRunf("Would exclude: %s", strings.Join(mvExclude, ", "))
+		} else {
+			tempDir, err := os.MkdirTemp("", "lxc-mv-")
+			if err != nil {
+				return fmt.Errorf("failed to create temp directory: %w", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			pushPath = filepath.Join(tempDir, filepath.Base(src.path))
+			if err := stageDirectory(src.path, pushPath, mvExclude); err != nil {
+				return fmt.Errorf("failed to stage '%s': %w", src.path, err)
+			}
+		}
+	}
+
 	// Check for glob pattern
 	if strings.Contains(dst.container, "*") {
 		matches := matchContainers(cfg, dst.container)
@@ -266,7 +462,7 @@ func hostToContainer(src, dst pathSpec) error {
 			return fmt.Errorf("no containers match pattern %q", dst.container)
 		}
 
-		fmt.Printf("Targeting %d container(s): %s\n", len(matches), strings.Join(matches, ", "))
+		printInfo("Targeting %d container(s): %s\n", len(matches), strings.Join(matches, ", "))
 
 		var errors []string
 		for _, name := range matches {
@@ -278,18 +474,18 @@ func hostToContainer(src, dst pathSpec) error {
 
 			printCopyMessage(src.path, name, dst.path, info.IsDir())
 
-			if err := copyToContainer(cfg, name, src.path, dst.path, info, mvYes); err != nil {
+			if err := copyToContainer(cfg, name, pushPath, dst.path, info.IsDir(), mvYes); err != nil {
 				errors = append(errors, fmt.Sprintf("%s: %v", name, err))
 				fmt.Printf("✗ %s failed: %v\n", name, err)
 				continue
 			}
-			fmt.Printf("✓ %s done\n", name)
+			printInfo("✓ %s done\n", name)
 		}
 
 		if len(errors) > 0 {
 			return fmt.Errorf("failed for %d container(s):\n  %s", len(errors), strings.Join(errors, "\n  "))
 		}
-		fmt.Println("All done.")
+		printMvDone("All done.")
 		return nil
 	}
 
@@ -300,11 +496,77 @@ func hostToContainer(src, dst pathSpec) error {
 
 	printCopyMessage(src.path, dst.container, dst.path, info.IsDir())
 
-	if err := copyToContainer(cfg, dst.container, src.path, dst.path, info, mvYes); err != nil {
+	if err := copyToContainer(cfg, dst.container, pushPath, dst.path, info.IsDir(), mvYes); err != nil {
+		return err
+	}
+
+	printMvDone("Done.")
+	return nil
+}
+
+// hostFilesToContainer pushes several host files into a destination directory,
+// one file at a time, on one container or every container matching a glob
+func hostFilesToContainer(files []string, dst pathSpec) error {
+	if dst.path == "" {
+		return fmt.Errorf("destination path cannot be empty")
+	}
+
+	cfg, err := requireProject()
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("Done.")
+	targets := []string{dst.container}
+	if strings.Contains(dst.container, "*") {
+		targets = matchContainers(cfg, dst.container)
+		if len(targets) == 0 {
+			return fmt.Errorf("no containers match pattern %q", dst.container)
+		}
+		printInfo("Targeting %d container(s): %s\n", len(targets), strings.Join(targets, ", "))
+	}
+
+	printInfo("Copying %d files matching pattern\n", len(files))
+
+	var errors []string
+	for _, name := range targets {
+		if err := validateContainer(cfg, name); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", name, err))
+			fmt.Printf("✗ %s failed: %v\n", name, err)
+			continue
+		}
+
+		// A trailing slash means "this directory", created if missing
+		destDir := expandRemoteHome(cfg, name, dst.path)
+		if !strings.HasSuffix(dst.path, "/") && !lxc.DirExists(cfg.GetLXCName(name), destDir) {
+			err := fmt.Errorf("destination '%s' must be a directory when copying multiple files (add a trailing '/' to create it)", dst.path)
+			errors = append(errors, fmt.Sprintf("%s: %v", name, err))
+			fmt.Printf("✗ %s failed: %v\n", name, err)
+			continue
+		}
+
+		for _, file := range files {
+			remotePath := path.Join(destDir, filepath.Base(file))
+
+			info, err := os.Stat(file)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %s: %v", name, file, err))
+				fmt.Printf("✗ %s -> %s:%s failed: %v\n", file, name, remotePath, err)
+				continue
+			}
+
+			if err := copyToContainer(cfg, name, file, remotePath, info.IsDir(), mvYes); err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %s: %v", name, file, err))
+				fmt.Printf("✗ %s -> %s:%s failed: %v\n", file, name, remotePath, err)
+				continue
+			}
+			printInfo("✓ %s -> %s:%s\n", file, name, remotePath)
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to copy %d file(s):\n  %s", len(errors), strings.Join(errors, "\n  "))
+	}
+	printMvDone("All done.")
 	return nil
 }
 
@@ -314,7 +576,7 @@ func containerToHost(src, dst pathSpec) error {
 		return fmt.Errorf("source path cannot be empty")
 	}
 
-	cfg, err := config.Load()
+	cfg, err := requireProject()
 	if err != nil {
 		return err
 	}
@@ -328,13 +590,15 @@ func containerToHost(src, dst pathSpec) error {
 		return err
 	}
 
-	fmt.Printf("Copying from %s:%s to %s...\n", src.container, src.path, dst.path)
+	if !mvDryRun {
+		printInfo("Copying from %s:%s to %s...\n", src.container, src.path, dst.path)
+	}
 
 	if err := copyFromContainer(cfg, src.container, src.path, dst.path); err != nil {
 		return err
 	}
 
-	fmt.Println("Done.")
+	printMvDone("Done.")
 	return nil
 }
 
@@ -347,7 +611,7 @@ func containerToContainer(src, dst pathSpec) error {
 		return fmt.Errorf("destination path cannot be empty")
 	}
 
-	cfg, err := config.Load()
+	cfg, err := requireProject()
 	if err != nil {
 		return err
 	}
@@ -361,24 +625,36 @@ func containerToContainer(src, dst pathSpec) error {
 		return err
 	}
 
-	// Create temp directory for intermediate storage
-	tempDir, err := os.MkdirTemp("", "lxc-mv-")
-	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
-	}
-	defer os.RemoveAll(tempDir)
+	// Pull from source container to a temp copy
+	var tempPath string
+	var isDir bool
+	if mvDryRun {
+		// Nothing is pulled, so ask LXC whether the source is a directory
+		tempPath = filepath.Join(os.TempDir(), "lxc-pull-*", filepath.Base(src.path))
+		if err := copyFromContainer(cfg, src.container, src.path, tempPath); err != nil {
+			return fmt.Errorf("failed to pull from source: %w", err)
+		}
+		isDir = lxc.IsDir(cfg.GetLXCName(src.container), expandRemoteHome(cfg, src.container, src.path))
+	} else {
+		printInfo("Pulling from %s:%s...\n", src.container, src.path)
+		lxcName := cfg.GetLXCName(src.container)
+		remotePath := expandRemoteHome(cfg, src.container, src.path)
+		if !lxc.FileExists(lxcName, remotePath) {
+			return fmt.Errorf("failed to pull from source: source '%s' does not exist in container %s", remotePath, src.container)
+		}
 
-	// Pull from source container to temp
-	tempPath := filepath.Join(tempDir, filepath.Base(src.path))
-	fmt.Printf("Pulling from %s:%s...\n", src.container, src.path)
-	if err := copyFromContainer(cfg, src.container, src.path, tempPath); err != nil {
-		return fmt.Errorf("failed to pull from source: %w", err)
-	}
+		pulled, cleanup, err := lxc.CopyToTemp(lxcName, remotePath)
+		if err != nil {
+			return fmt.Errorf("failed to pull from source: %w", err)
+		}
+		defer cleanup()
+		tempPath = pulled
 
-	// Get info about the pulled file/directory
-	info, err := os.Stat(tempPath)
-	if err != nil {
-		return fmt.Errorf("failed to stat temp file: %w", err)
+		info, err := os.Stat(tempPath)
+		if err != nil {
+			return fmt.Errorf("failed to stat temp file: %w", err)
+		}
+		isDir = info.IsDir()
 	}
 
 	// Push to destination container(s)
@@ -388,13 +664,13 @@ func containerToContainer(src, dst pathSpec) error {
 			return fmt.Errorf("no containers match pattern %q", dst.container)
 		}
 
-		fmt.Printf("Targeting %d container(s): %s\n", len(matches), strings.Join(matches, ", "))
+		printInfo("Targeting %d container(s): %s\n", len(matches), strings.Join(matches, ", "))
 
 		var errors []string
 		for _, name := range matches {
 			// Skip source container if it matches
 			if name == src.container {
-				fmt.Printf("⊘ %s skipped (source container)\n", name)
+				printInfo("⊘ %s skipped (source container)\n", name)
 				continue
 			}
 
@@ -404,20 +680,20 @@ func containerToContainer(src, dst pathSpec) error {
 				continue
 			}
 
-			printCopyMessage(src.path, name, dst.path, info.IsDir())
+			printCopyMessage(src.path, name, dst.path, isDir)
 
-			if err := copyToContainer(cfg, name, tempPath, dst.path, info, mvYes); err != nil {
+			if err := copyToContainer(cfg, name, tempPath, dst.path, isDir, mvYes); err != nil {
 				errors = append(errors, fmt.Sprintf("%s: %v", name, err))
 				fmt.Printf("✗ %s failed: %v\n", name, err)
 				continue
 			}
-			fmt.Printf("✓ %s done\n", name)
+			printInfo("✓ %s done\n", name)
 		}
 
 		if len(errors) > 0 {
 			return fmt.Errorf("failed for %d container(s):\n  %s", len(errors), strings.Join(errors, "\n  "))
 		}
-		fmt.Println("All done.")
+		printMvDone("All done.")
 		return nil
 	}
 
@@ -426,20 +702,30 @@ func containerToContainer(src, dst pathSpec) error {
 		return err
 	}
 
-	printCopyMessage(src.path, dst.container, dst.path, info.IsDir())
+	printCopyMessage(src.path, dst.container, dst.path, isDir)
 
-	if err := copyToContainer(cfg, dst.container, tempPath, dst.path, info, mvYes); err != nil {
+	if err := copyToContainer(cfg, dst.container, tempPath, dst.path, isDir, mvYes); err != nil {
 		return err
 	}
 
-	fmt.Println("Done.")
+	printMvDone("Done.")
 	return nil
 }
 
 func printCopyMessage(source, container, dest string, isDir bool) {
+	if mvDryRun {
+		return
+	}
 	if isDir {
-		fmt.Printf("Copying directory '%s' to %s:%s...\n", source, container, dest)
+		printInfo("Copying directory '%s' to %s:%s...\n", source, container, dest)
 	} else {
-		fmt.Printf("Copying file '%s' to %s:%s...\n", source, container, dest)
+		printInfo("Copying file '%s' to %s:%s...\n", source, container, dest)
+	}
+}
+
+// printMvDone prints the final message of a copy; a dry run has nothing to report
+func printMvDone(msg string) {
+	if !mvDryRun {
+		printInfo("%s\n", msg)
 	}
 }
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"lxc-dev-manager/internal/config"
 )
 
 func TestMv_InvalidDestinationFormat(t *testing.T) {
@@ -127,6 +130,86 @@ defaults:
 	}
 }
 
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want pathSpec
+	}{
+		{"dev1:~", pathSpec{isContainer: true, container: "dev1", path: "~"}},
+		{"dev1:~/app", pathSpec{isContainer: true, container: "dev1", path: "~/app"}},
+		{"dev1:~root/.bashrc", pathSpec{isContainer: true, container: "dev1", path: "~root/.bashrc"}},
+		{"dev*:/tmp", pathSpec{isContainer: true, container: "dev*", path: "/tmp"}},
+		{"./file", pathSpec{path: "./file"}},
+		{"/abs/path", pathSpec{path: "/abs/path"}},
+		{"C:/windows", pathSpec{path: "C:/windows"}},
+	}
+	for _, tt := range tests {
+		if got := parsePath(tt.arg); got != tt.want {
+			t.Errorf("parsePath(%q) = %+v, want %+v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestExpandRemoteHome(t *testing.T) {
+	cfg := &config.Config{
+		Defaults:   config.Defaults{User: config.User{Name: "myuser"}},
+		Containers: map[string]config.Container{"dev1": {Image: "ubuntu:24.04"}, "ops": {Image: "ubuntu:24.04", User: config.User{Name: "root"}}},
+	}
+
+	tests := []struct {
+		container string
+		path      string
+		want      string
+	}{
+		{"dev1", "~", "/home/myuser"},
+		{"dev1", "~/", "/home/myuser/"},
+		{"dev1", "~/.ssh/key", "/home/myuser/.ssh/key"},
+		{"dev1", "~root", "/root"},
+		{"dev1", "~root/.bashrc", "/root/.bashrc"},
+		{"dev1", "~alice", "/home/alice"},
+		{"dev1", "~alice/app", "/home/alice/app"},
+		{"dev1", "~_svc-user/x", "/home/_svc-user/x"},
+		{"ops", "~", "/root"},
+		{"ops", "~/.profile", "/root/.profile"},
+		{"dev1", "/etc/hosts", "/etc/hosts"},
+		{"dev1", "app/~", "app/~"},
+		{"dev1", "~.cache", "~.cache"},
+		{"dev1", "~Alice/app", "~Alice/app"},
+	}
+	for _, tt := range tests {
+		if got := expandRemoteHome(cfg, tt.container, tt.path); got != tt.want {
+			t.Errorf("expandRemoteHome(%q, %q) = %q, want %q", tt.container, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMv_TildeAloneIsUserHome(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+defaults:
+  user:
+    name: myuser
+`)
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -e /home/myuser", "")
+	env.mock.SetError("exec dev1 -- test -d /home/myuser", "not a directory")
+	env.mock.SetOutput("file pull dev1/home/myuser", "")
+
+	localDir := filepath.Join(env.dir, "home-copy")
+	env.captureStdout(func() {
+		if err := runMv(nil, []string{"dev1:~", localDir}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("exec", "dev1", "--", "test", "-e", "/home/myuser") {
+		t.Errorf("expected ~ to expand to /home/myuser, got calls: %v", env.mock.Calls)
+	}
+}
+
 func TestMv_SuccessfulFileCopy(t *testing.T) {
 	env := setupTestEnv(t)
 	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
@@ -301,3 +384,441 @@ containers:
 		t.Errorf("expected file push with bob's home path, got calls: %v", env.mock.Calls)
 	}
 }
+
+func TestMv_GlobSingleMatch(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d /home/dev", "")
+	env.mock.SetOutput("file push", "")
+
+	os.WriteFile(filepath.Join(env.dir, "app.log"), []byte("log"), 0644)
+	os.WriteFile(filepath.Join(env.dir, "notes.txt"), []byte("notes"), 0644)
+
+	err := runMv(nil, []string{filepath.Join(env.dir, "*.log"), "dev1:/home/dev/app.log"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "file push " + filepath.Join(env.dir, "app.log") + " dev1//home/dev/app.log"
+	if !env.mock.HasCallPrefix(want) {
+		t.Errorf("expected %q, got calls: %v", want, env.mock.Calls)
+	}
+	if env.mock.HasCallPrefix("file push " + filepath.Join(env.dir, "notes.txt")) {
+		t.Error("non-matching file should not be pushed")
+	}
+}
+
+func TestMv_GlobMultipleMatches(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d /home/dev/logs", "")
+	env.mock.SetOutput("file push", "")
+
+	for _, name := range []string{"a.log", "b.log", "c.txt"} {
+		os.WriteFile(filepath.Join(env.dir, name), []byte(name), 0644)
+	}
+
+	var err error
+	output := env.captureStdout(func() {
+		err = runMv(nil, []string{filepath.Join(env.dir, "*.log"), "dev1:/home/dev/logs"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"a.log", "b.log"} {
+		want := "file push " + filepath.Join(env.dir, name) + " dev1//home/dev/logs/" + name
+		if !env.mock.HasCallPrefix(want) {
+			t.Errorf("expected %q, got calls: %v", want, env.mock.Calls)
+		}
+		if !strings.Contains(output, "✓ "+filepath.Join(env.dir, name)) {
+			t.Errorf("expected progress for %s, got: %s", name, output)
+		}
+	}
+	if env.mock.HasCallPrefix("file push " + filepath.Join(env.dir, "c.txt")) {
+		t.Error("non-matching file should not be pushed")
+	}
+}
+
+func TestMv_GlobMultipleMatchesRequiresDirectory(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetError("exec dev1 -- test -d /home/dev/out.log", "exit status 1")
+
+	os.WriteFile(filepath.Join(env.dir, "a.log"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(env.dir, "b.log"), []byte("b"), 0644)
+
+	err := runMv(nil, []string{filepath.Join(env.dir, "*.log"), "dev1:/home/dev/out.log"})
+	if err == nil {
+		t.Fatal("expected error when destination is not a directory")
+	}
+	if !strings.Contains(err.Error(), "must be a directory") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if env.mock.HasCallPrefix("file push") {
+		t.Error("no file should be pushed")
+	}
+}
+
+func TestMv_GlobMultipleMatchesCollectsErrors(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d /tmp/logs", "")
+	env.mock.SetOutput("file push", "")
+	env.mock.SetError("file push "+filepath.Join(env.dir, "a.log")+" dev1//tmp/logs/a.log", "push failed")
+
+	os.WriteFile(filepath.Join(env.dir, "a.log"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(env.dir, "b.log"), []byte("b"), 0644)
+
+	err := runMv(nil, []string{filepath.Join(env.dir, "*.log"), "dev1:/tmp/logs/"})
+	if err == nil {
+		t.Fatal("expected error when a push fails")
+	}
+	if !strings.Contains(err.Error(), "failed to copy 1 file(s)") || !strings.Contains(err.Error(), "a.log") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCallPrefix("file push " + filepath.Join(env.dir, "b.log")) {
+		t.Error("remaining files should still be pushed after a failure")
+	}
+}
+
+func TestMv_GlobNoMatch(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runMv(nil, []string{filepath.Join(env.dir, "*.log"), "dev1:/home/dev/"})
+	if err == nil {
+		t.Fatal("expected error when no files match")
+	}
+	if !strings.Contains(err.Error(), "no files match") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMv_DirectoryShowsProgress(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d", "")
+	env.mock.SetOutput("file push -r", "Pushing /home/dev: 100% (2.0KB/s)\n")
+	env.mock.SetOutput("exec dev1 -- chown -R dev:dev", "")
+
+	testDir := filepath.Join(env.dir, "myproject")
+	os.MkdirAll(filepath.Join(testDir, "sub"), 0755)
+	os.WriteFile(filepath.Join(testDir, "a.txt"), make([]byte, 1024), 0644)
+	os.WriteFile(filepath.Join(testDir, "sub", "b.txt"), make([]byte, 1024), 0644)
+
+	out := env.captureStdout(func() {
+		if err := runMv(nil, []string{testDir, "dev1:/home/dev/myproject"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "2 file(s), 2.0 KiB") {
+		t.Errorf("expected directory size summary, got:\n%s", out)
+	}
+	if !strings.Contains(out, "  Pushing /home/dev: 100%") {
+		t.Errorf("expected indented lxc progress output, got:\n%s", out)
+	}
+}
+
+func TestMv_DirectoryPushError(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d", "")
+	env.mock.SetResponse("file push -r", []byte("Error: not found"), errors.New("exit status 1"))
+
+	testDir := filepath.Join(env.dir, "myproject")
+	os.MkdirAll(testDir, 0755)
+
+	var err error
+	env.captureStdout(func() {
+		err = runMv(nil, []string{testDir, "dev1:/home/dev/myproject"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "does the directory exist?") {
+		t.Errorf("expected destination hint, got %v", err)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "a", "b"), 0755)
+	os.WriteFile(filepath.Join(dir, "x"), make([]byte, 10), 0644)
+	os.WriteFile(filepath.Join(dir, "a", "b", "y"), make([]byte, 5), 0644)
+
+	files, size, err := dirSize(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if files != 2 || size != 15 {
+		t.Errorf("got %d files, %d bytes; want 2 files, 15 bytes", files, size)
+	}
+}
+
+// assertNoCopyCalls fails the test if a dry run pushed, pulled or chowned anything
+func assertNoCopyCalls(t *testing.T, env *testEnv) {
+	t.Helper()
+	for _, call := range env.mock.Calls {
+		callStr := strings.Join(call.Args, " ")
+		for _, op := range []string{"file push", "file pull", "chown", "mkdir"} {
+			if strings.Contains(callStr, op) {
+				t.Errorf("dry run should not call %q, got: %s", op, callStr)
+			}
+		}
+	}
+}
+
+func TestMv_DryRunFile(t *testing.T) {
+	env := setupTestEnv(t)
+	mvDryRun = true
+	t.Cleanup(func() { mvDryRun = false })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d", "")
+
+	testFile := filepath.Join(env.dir, "testfile.txt")
+	os.WriteFile(testFile, []byte("test content"), 0644)
+
+	out := env.captureStdout(func() {
+		if err := runMv(nil, []string{testFile, "dev1:~/testfile.txt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	assertNoCopyCalls(t, env)
+	for _, want := range []string{
+		"[DRY-RUN] Would run: lxc file push " + testFile + " dev1//home/dev/testfile.txt",
+		"[DRY-RUN] Would run: lxc exec dev1 -- chown dev:dev /home/dev/testfile.txt",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Done.") {
+		t.Errorf("dry run should not report completion, got:\n%s", out)
+	}
+}
+
+func TestMv_DryRunDirectory(t *testing.T) {
+	env := setupTestEnv(t)
+	mvDryRun = true
+	t.Cleanup(func() { mvDryRun = false })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetError("exec dev1 -- test -d /home/dev/src", "not a directory")
+
+	testDir := filepath.Join(env.dir, "myproject")
+	os.MkdirAll(testDir, 0755)
+
+	out := env.captureStdout(func() {
+		if err := runMv(nil, []string{testDir, "dev1:~/src/myproject"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	assertNoCopyCalls(t, env)
+	for _, want := range []string{
+		"Would run: lxc exec dev1 -- mkdir -p /home/dev/src",
+		"Would run: lxc file push -r " + testDir + " dev1//home/dev/src",
+		"Would run: lxc exec dev1 -- chown -R dev:dev /home/dev/src/myproject",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMv_DryRunGlob(t *testing.T) {
+	env := setupTestEnv(t)
+	mvDryRun = true
+	t.Cleanup(func() { mvDryRun = false })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d", "")
+
+	os.WriteFile(filepath.Join(env.dir, "a.log"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(env.dir, "b.log"), []byte("b"), 0644)
+
+	out := env.captureStdout(func() {
+		if err := runMv(nil, []string{filepath.Join(env.dir, "*.log"), "dev1:/tmp/"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	assertNoCopyCalls(t, env)
+	for _, name := range []string{"a.log", "b.log"} {
+		want := "Would run: lxc file push " + filepath.Join(env.dir, name) + " dev1//tmp/" + name
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMv_DryRunContainerToHost(t *testing.T) {
+	env := setupTestEnv(t)
+	mvDryRun = true
+	t.Cleanup(func() { mvDryRun = false })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -e /home/dev/.bashrc", "")
+	env.mock.SetError("exec dev1 -- test -d /home/dev/.bashrc", "not a directory")
+
+	destFile := filepath.Join(env.dir, "out", "bashrc")
+	out := env.captureStdout(func() {
+		if err := runMv(nil, []string{"dev1:~/.bashrc", destFile}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	assertNoCopyCalls(t, env)
+	if !strings.Contains(out, "Would run: lxc file pull dev1//home/dev/.bashrc "+destFile) {
+		t.Errorf("expected pull plan, got:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Dir(destFile)); !os.IsNotExist(err) {
+		t.Error("dry run should not create the local directory")
+	}
+}
+
+func TestMv_DryRunStillValidates(t *testing.T) {
+	env := setupTestEnv(t)
+	mvDryRun = true
+	t.Cleanup(func() { mvDryRun = false })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerNotExists("dev1")
+
+	err := runMv(nil, []string{"/nonexistent/file.txt", "dev1:/home/dev/"})
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected missing source error, got %v", err)
+	}
+
+	testFile := filepath.Join(env.dir, "testfile.txt")
+	os.WriteFile(testFile, []byte("test content"), 0644)
+	if err := runMv(nil, []string{testFile, "dev1:/home/dev/"}); err == nil {
+		t.Error("expected error for container missing in LXC")
+	}
+	assertNoCopyCalls(t, env)
+}
+
+func TestStageDirectory_SkipsExcluded(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "project")
+	for _, p := range []string{
+		"main.go",
+		"node_modules/left-pad/index.js",
+		"web/node_modules/react/index.js",
+		"web/app.js",
+		".git/HEAD",
+		"build/out.log",
+		"build/keep.txt",
+	} {
+		os.MkdirAll(filepath.Join(src, filepath.Dir(p)), 0755)
+		os.WriteFile(filepath.Join(src, p), []byte(p), 0644)
+	}
+	os.Symlink("main.go", filepath.Join(src, "link.go"))
+
+	dest := filepath.Join(t.TempDir(), "project")
+	if err := stageDirectory(src, dest, []string{"node_modules", ".git/", "build/*.log"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, p := range []string{"node_modules", "web/node_modules", ".git", "build/out.log"} {
+		if _, err := os.Lstat(filepath.Join(dest, p)); !os.IsNotExist(err) {
+			t.Errorf("%s should be excluded from the staged directory", p)
+		}
+	}
+	for _, p := range []string{"main.go", "web/app.js", "build/keep.txt"} {
+		data, err := os.ReadFile(filepath.Join(dest, p))
+		if err != nil {
+			t.Errorf("%s should be staged: %v", p, err)
+			continue
+		}
+		if string(data) != p {
+			t.Errorf("%s has content %q", p, data)
+		}
+	}
+	if link, err := os.Readlink(filepath.Join(dest, "link.go")); err != nil || link != "main.go" {
+		t.Errorf("expected symlink to be kept, got %q (%v)", link, err)
+	}
+}
+
+func TestIsExcluded(t *testing.T) {
+	tests := []struct {
+		rel      string
+		excludes []string
+		want     bool
+	}{
+		{"node_modules", []string{"node_modules"}, true},
+		{"a/b/node_modules", []string{"node_modules"}, true},
+		{"a/b.log", []string{"*.log"}, true},
+		{"a/b.log", []string{"a/*.log"}, true},
+		{"c/a/b.log", []string{"a/*.log"}, false},
+		{"src/main.go", []string{"*.log", ".git"}, false},
+	}
+	for _, tt := range tests {
+		if got := isExcluded(tt.rel, filepath.Base(tt.rel), tt.excludes); got != tt.want {
+			t.Errorf("isExcluded(%q, %v) = %v, want %v", tt.rel, tt.excludes, got, tt.want)
+		}
+	}
+}
+
+func TestMv_ExcludePushesStagedCopy(t *testing.T) {
+	env := setupTestEnv(t)
+	mvExclude = []string{"node_modules"}
+	t.Cleanup(func() { mvExclude = nil })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d", "")
+
+	testDir := filepath.Join(env.dir, "myproject")
+	os.MkdirAll(filepath.Join(testDir, "node_modules"), 0755)
+	os.WriteFile(filepath.Join(testDir, "node_modules", "big.js"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(testDir, "main.go"), []byte("package main"), 0644)
+
+	// Inspect the staged directory while it still exists
+	var pushed string
+	env.mock.SetCallback("file push", func(args []string) {
+		pushed = args[len(args)-2]
+		if _, err := os.Stat(filepath.Join(pushed, "main.go")); err != nil {
+			t.Errorf("main.go missing from staged directory: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(pushed, "node_modules")); !os.IsNotExist(err) {
+			t.Error("node_modules should not be staged")
+		}
+	})
+
+	env.captureStdout(func() {
+		if err := runMv(nil, []string{testDir, "dev1:/home/dev/myproject"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if pushed == "" || pushed == testDir || filepath.Base(pushed) != "myproject" {
+		t.Errorf("expected a staged copy named myproject to be pushed, got %q", pushed)
+	}
+	if _, err := os.Stat(pushed); !os.IsNotExist(err) {
+		t.Error("staged directory should be removed after the push")
+	}
+}
+
+func TestMv_ExcludeInvalidPattern(t *testing.T) {
+	env := setupTestEnv(t)
+	mvExclude = []string{"[unclosed"}
+	t.Cleanup(func() { mvExclude = nil })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runMv(nil, []string{env.dir, "dev1:/home/dev/"})
+	if err == nil || !strings.Contains(err.Error(), "invalid exclude pattern") {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+}
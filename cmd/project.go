@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
+	"sync"
 
 	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
@@ -31,15 +32,18 @@ specified with --name. All containers will be prefixed with
 the project name in LXC.
 
 Default ports for proxying can be specified with --ports as a
-comma-separated list. If not specified, no default ports are set.
+comma-separated list. Use LOCAL:REMOTE to forward a host port to a
+different container port (e.g. 8080:80). If not specified, no default
+ports are set.
 
 Examples:
   lxc-dev-manager project create
   lxc-dev-manager project create --name my-app
   lxc-dev-manager project create --ports 5173,8000,5432
   lxc-dev-manager create  # alias for project create`,
-	Args: cobra.NoArgs,
-	RunE: runProjectCreate,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noProjectSearch: "true"},
+	RunE:        runProjectCreate,
 }
 
 // Root-level create command as alias for project create
@@ -53,7 +57,9 @@ specified with --name. All containers will be prefixed with
 the project name in LXC.
 
 Default ports for proxying can be specified with --ports as a
-comma-separated list. If not specified, no default ports are set.
+comma-separated list. Use LOCAL:REMOTE to forward a host port to a
+different container port (e.g. 8080:80). If not specified, no default
+ports are set.
 
 This is an alias for 'lxc-dev-manager project create'.
 
@@ -61,8 +67,9 @@ Examples:
   lxc-dev-manager create
   lxc-dev-manager create --name my-app
   lxc-dev-manager create --ports 5173,8000,5432`,
-	Args: cobra.NoArgs,
-	RunE: runProjectCreate,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noProjectSearch: "true"},
+	RunE:        runProjectCreate,
 }
 
 var projectDeleteCmd = &cobra.Command{
@@ -71,17 +78,25 @@ var projectDeleteCmd = &cobra.Command{
 	Long: `Deletes all containers belonging to this project and removes
 the containers.yaml file. This action is destructive and irreversible.
 
+Containers are deleted in parallel, at most --concurrency at a time.
+Use --dry-run to print what would be deleted without deleting anything.
+
 Examples:
   lxc-dev-manager project delete
-  lxc-dev-manager project delete --force`,
+  lxc-dev-manager project delete --force
+  lxc-dev-manager project delete --dry-run
+  lxc-dev-manager project delete --force --concurrency 8`,
 	Args: cobra.NoArgs,
 	RunE: runProjectDelete,
 }
 
 var (
-	projectNameFlag    string
-	projectPortsFlag   string
-	projectDeleteForce bool
+	projectNameFlag     string
+	projectPortsFlag    string
+	projectDeleteForce  bool
+	projectDeleteDryRun bool
+
+	projectDeleteConcurrency int
 )
 
 func init() {
@@ -95,6 +110,8 @@ func init() {
 
 	// Add --force flag to project delete
 	projectDeleteCmd.Flags().BoolVarP(&projectDeleteForce, "force", "f", false, "Skip confirmation prompt")
+	projectDeleteCmd.Flags().BoolVar(&projectDeleteDryRun, "dry-run", false, "Show what would be deleted without deleting")
+	projectDeleteCmd.Flags().IntVar(&projectDeleteConcurrency, "concurrency", defaultConcurrency, "Maximum number of containers to delete at once")
 
 	// Add root-level create alias
 	rootCmd.AddCommand(createCmd)
@@ -126,8 +143,8 @@ func runProjectCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid project name %q: must contain only letters, numbers, hyphens, and underscores", projectName)
 	}
 
-	// Parse ports flag
-	var ports []int
+	// Parse ports flag (PORT or LOCAL:REMOTE)
+	var ports config.PortList
 	if projectPortsFlag != "" {
 		portStrs := strings.Split(projectPortsFlag, ",")
 		for _, ps := range portStrs {
@@ -135,14 +152,16 @@ func runProjectCreate(cmd *cobra.Command, args []string) error {
 			if ps == "" {
 				continue
 			}
-			port, err := strconv.Atoi(ps)
+			m, err := config.ParsePortMapping(ps)
 			if err != nil {
-				return fmt.Errorf("invalid port %q: %w", ps, err)
+				return err
 			}
-			if port < 1 || port > 65535 {
-				return fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
+			for _, port := range []int{m.Local, m.Remote} {
+				if port < 1 || port > 65535 {
+					return fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
+				}
 			}
-			ports = append(ports, port)
+			ports = append(ports, m)
 		}
 	}
 
@@ -159,10 +178,10 @@ func runProjectCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("Project '%s' created\n", projectName)
-	fmt.Printf("  Config: %s\n", config.ConfigFile)
-	fmt.Printf("\nNext steps:\n")
-	fmt.Printf("  lxc-dev-manager container create dev1 ubuntu:24.04\n")
+	printInfo("Project '%s' created\n", projectName)
+	printInfo("  Config: %s\n", config.ConfigFile)
+	printInfo("\nNext steps:\n")
+	printInfo("  lxc-dev-manager container create dev1 ubuntu:24.04\n")
 
 	return nil
 }
@@ -175,13 +194,14 @@ func runProjectDelete(cmd *cobra.Command, args []string) error {
 	if cfg == nil {
 		return fmt.Errorf("no project found in current directory")
 	}
+	lxc.SetRemote(cfg.Defaults.Remote)
 
 	// List containers to be deleted
-	fmt.Printf("Project: %s\n", cfg.Project)
-	fmt.Printf("Config:  %s\n\n", config.ConfigFile)
+	printInfo("Project: %s\n", cfg.Project)
+	printInfo("Config:  %s\n\n", config.ConfigFile)
 
 	if len(cfg.Containers) > 0 {
-		fmt.Println("Containers to be deleted:")
+		printInfo("Containers to be deleted:\n")
 		for name := range cfg.Containers {
 			lxcName := cfg.GetLXCName(name)
 			status := "NOT FOUND"
@@ -189,11 +209,22 @@ func runProjectDelete(cmd *cobra.Command, args []string) error {
 				s, _ := lxc.GetStatus(lxcName)
 				status = s
 			}
-			fmt.Printf("  - %s (%s) [%s]\n", name, lxcName, status)
+			printInfo("  - %s (%s) [%s]\n", name, lxcName, status)
 		}
-		fmt.Println()
+		printInfo("\n")
 	} else {
-		fmt.Println("No containers defined.")
+		printInfo("No containers defined.\n")
+	}
+
+	if projectDeleteDryRun {
+		for _, name := range containerNames(cfg) {
+			lxcName := cfg.GetLXCName(name)
+			if lxc.Exists(lxcName) {
+				dryRunf("Would run: lxc delete %s --force", lxc.InstanceRef(lxcName))
+			}
+		}
+		dryRunf("Would remove %s", config.ConfigFile)
+		return nil
 	}
 
 	// Confirm deletion
@@ -205,27 +236,14 @@ func runProjectDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	// Delete all containers
-	var deleteErrors []string
-	for name := range cfg.Containers {
-		lxcName := cfg.GetLXCName(name)
-		fmt.Printf("Deleting container '%s'... ", name)
-
-		if lxc.Exists(lxcName) {
-			if err := lxc.Delete(lxcName); err != nil {
-				fmt.Printf("FAILED: %v\n", err)
-				deleteErrors = append(deleteErrors, fmt.Sprintf("%s: %v", name, err))
-				continue
-			}
-		}
-		fmt.Println("done")
-	}
+	deleteErrors := deleteContainers(cfg, cfg.GetAllLXCNames(), projectDeleteConcurrency)
 
 	// Remove config file
-	fmt.Printf("Removing %s... ", config.ConfigFile)
+	printInfo("Removing %s... ", config.ConfigFile)
 	if err := os.Remove(config.ConfigFile); err != nil {
 		return fmt.Errorf("failed to remove config: %w", err)
 	}
-	fmt.Println("done")
+	printInfo("done\n")
 
 	if len(deleteErrors) > 0 {
 		fmt.Printf("\nWarning: Some containers failed to delete:\n")
@@ -234,6 +252,55 @@ func runProjectDelete(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("\nProject '%s' deleted\n", cfg.Project)
+	printInfo("\nProject '%s' deleted\n", cfg.Project)
 	return nil
 }
+
+// deleteContainers deletes the given LXC containers with at most concurrency
+// deletions in flight, printing each result as it completes. Containers that
+// no longer exist count as deleted. Returns one message per failure.
+func deleteContainers(cfg *config.Config, lxcNames []string, concurrency int) []string {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if len(lxcNames) > 0 {
+		printInfo("Deleting %d container(s)...\n", len(lxcNames))
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures []string
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, lxcName := range lxcNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(lxcName string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			var err error
+			if lxc.Exists(lxcName) {
+				err = lxc.Delete(lxcName)
+			}
+
+			name := cfg.GetShortName(lxcName)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Printf("✗ %s failed: %v\n", name, err)
+				failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+				return
+			}
+			printInfo("✓ %s deleted\n", name)
+		}(lxcName)
+	}
+	wg.Wait()
+
+	sort.Strings(failures)
+	return failures
+}
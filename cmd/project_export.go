@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"lxc-dev-manager/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var projectExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the project config as a shareable template",
+	Long: `Write a copy of containers.yaml that can be shared with a team.
+
+Snapshot records (including their created_at timestamps) and deployed SSH
+keys are removed, since they describe containers on this machine only. Use --strip-passwords
+to also remove user passwords. No container or image data is exported.
+
+The template is written to stdout unless --output is given.
+
+Example:
+  lxc-dev-manager project export > template.yaml
+  lxc-dev-manager project export -o template.yaml --strip-passwords`,
+	Args: cobra.NoArgs,
+	RunE: runProjectExport,
+}
+
+var projectImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create a project from an exported template",
+	Long: `Validate an exported template and write it as containers.yaml.
+
+Only the config is imported; containers are not created. An existing
+project is never overwritten unless --force is given.
+
+Example:
+  lxc-dev-manager project import template.yaml
+  lxc-dev-manager project import template.yaml --force`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{noProjectSearch: "true"},
+	RunE:        runProjectImport,
+}
+
+var (
+	projectExportOutput         string
+	projectExportStripPasswords bool
+	projectImportForce          bool
+)
+
+func init() {
+	projectCmd.AddCommand(projectExportCmd)
+	projectCmd.AddCommand(projectImportCmd)
+
+	projectExportCmd.Flags().StringVarP(&projectExportOutput, "output", "o", "", "Write the template to a file instead of stdout")
+	projectExportCmd.Flags().BoolVar(&projectExportStripPasswords, "strip-passwords", false, "Remove user passwords from the template")
+	projectImportCmd.Flags().BoolVarP(&projectImportForce, "force", "f", false, "Overwrite an existing project config")
+}
+
+func runProjectExport(cmd *cobra.Command, args []string) error {
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	template := cfg.Export(config.ExportOptions{StripPasswords: projectExportStripPasswords})
+
+	if projectExportOutput == "" {
+		data, err := template.Marshal()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	output := hostPath(projectExportOutput)
+	if isProjectConfig(output) {
+		return fmt.Errorf("refusing to overwrite %s with an export; choose another --output", config.ConfigFile)
+	}
+	if err := template.SaveAs(output); err != nil {
+		return fmt.Errorf("failed to write %s: %w", projectExportOutput, err)
+	}
+
+	printInfo("Exported project '%s' to %s\n", cfg.Project, projectExportOutput)
+	return nil
+}
+
+func runProjectImport(cmd *cobra.Command, args []string) error {
+	path := hostPath(args[0])
+
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", path, err)
+	}
+
+	lock, err := config.AcquireLock()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if _, err := os.Stat(config.ConfigFile); err == nil && !projectImportForce {
+		return fmt.Errorf("project already exists in %s\nUse --force to overwrite it", config.ConfigFile)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	printInfo("Imported project '%s' from %s (%d containers)\n", cfg.Project, path, len(cfg.Containers))
+	return nil
+}
+
+// isProjectConfig reports whether path refers to the project's containers.yaml
+func isProjectConfig(path string) bool {
+	a, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	b, err := os.Stat(config.ConfigFile)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(a, b)
+}
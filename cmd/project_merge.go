@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"lxc-dev-manager/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var projectMergeCmd = &cobra.Command{
+	Use:   "merge <file>",
+	Short: "Merge a shared base config into the project",
+	Long: `Add the containers of another config file (e.g. a team's base.yaml)
+to containers.yaml.
+
+Default ports missing from the project are added, and the default user is
+taken from the file if the project has none. Other project settings are
+kept. Containers are only added to the config; create them with
+'container create'.
+
+Containers already defined in the project are never replaced unless
+--force is given.
+
+Example:
+  lxc-dev-manager project merge base.yaml
+  lxc-dev-manager project merge base.yaml --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectMerge,
+}
+
+var projectMergeForce bool
+
+func init() {
+	projectCmd.AddCommand(projectMergeCmd)
+	projectMergeCmd.Flags().BoolVarP(&projectMergeForce, "force", "f", false, "Overwrite containers that are already defined")
+}
+
+func runProjectMerge(cmd *cobra.Command, args []string) error {
+	path := hostPath(args[0])
+	if isProjectConfig(path) {
+		return fmt.Errorf("cannot merge %s into itself", config.ConfigFile)
+	}
+
+	other, err := config.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+
+	cfg, lock, err := requireProjectWithLock()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if err := cfg.MergeWithOptions(other, config.MergeOptions{Overwrite: projectMergeForce}); err != nil {
+		return fmt.Errorf("%w\nUse --force to overwrite them", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("merged config is invalid: %w", err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	printInfo("Merged %s into project '%s' (%d container(s))\n", args[0], cfg.Project, len(other.Containers))
+	return nil
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/lxc"
+	"lxc-dev-manager/internal/validation"
+
+	"github.com/spf13/cobra"
+)
+
+var projectRenameCmd = &cobra.Command{
+	Use:   "rename <new-name>",
+	Short: "Rename the project and its containers",
+	Long: `Change the project name and move every container to the new prefix.
+
+Every container is checked against the name length limit first, then each
+one is renamed in LXC to the new prefix (snapshots move with it). Running
+containers are stopped for the rename and started again afterwards.
+
+If a rename fails or the command is interrupted with Ctrl+C, the containers
+renamed so far get their old names back and the project is left as it was.
+
+Example:
+  lxc-dev-manager project rename my-new-app`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectRename,
+}
+
+func init() {
+	projectCmd.AddCommand(projectRenameCmd)
+}
+
+// projectMove is one container moving to a new LXC name
+type projectMove struct {
+	name       string
+	oldLXC     string
+	newLXC     string
+	wasRunning bool
+}
+
+func runProjectRename(cmd *cobra.Command, args []string) error {
+	newProject := args[0]
+	if !config.IsValidProjectName(newProject) {
+		return fmt.Errorf("invalid project name %q: must contain only letters, numbers, hyphens, and underscores", newProject)
+	}
+
+	cfg, lock, err := requireProjectWithLock()
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	oldProject := cfg.Project
+	if oldProject == newProject {
+		return fmt.Errorf("project is already named '%s'", newProject)
+	}
+
+	// Check every new name before touching LXC, so a long prefix cannot
+	// leave the project half renamed
+	names := containerNames(cfg)
+	for _, name := range names {
+		if err := validation.ValidateFullContainerName(newProject, name); err != nil {
+			return err
+		}
+	}
+
+	renamed := &config.Config{Project: newProject}
+	var moves []projectMove
+	for _, name := range names {
+		move := projectMove{name: name, oldLXC: cfg.GetLXCName(name), newLXC: renamed.GetLXCName(name)}
+		if lxc.Exists(move.newLXC) {
+			return fmt.Errorf("container '%s' already exists in LXC", move.newLXC)
+		}
+		if !lxc.Exists(move.oldLXC) {
+			printInfo("Skipping '%s': not found in LXC\n", name)
+			continue
+		}
+		status, err := lxc.GetStatus(move.oldLXC)
+		if err != nil {
+			return err
+		}
+		move.wasRunning = status == "RUNNING"
+		moves = append(moves, move)
+	}
+
+	printInfo("Renaming project '%s' to '%s' (%d container(s))\n", oldProject, newProject, len(moves))
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	if err := renameProjectContainers(ctx, moves); err != nil {
+		return err
+	}
+
+	cfg.Project = newProject
+	if err := cfg.Save(); err != nil {
+		var stopped []projectMove
+		for _, m := range moves {
+			if m.wasRunning {
+				stopped = append(stopped, m)
+			}
+		}
+		return rollbackProjectRename(moves, stopped, fmt.Errorf("failed to save config: %w", err))
+	}
+
+	for _, m := range moves {
+		if !m.wasRunning {
+			continue
+		}
+		printInfo("Starting container '%s'...\n", m.name)
+		if err := lxc.Start(m.newLXC); err != nil {
+			fmt.Printf("Warning: could not start container: %v\n", err)
+		}
+	}
+
+	printInfo("\nProject '%s' renamed to '%s'\n", oldProject, newProject)
+	return nil
+}
+
+// renameProjectContainers stops the running containers and renames each one
+// to its new LXC name. On failure or interrupt, the renames are undone.
+func renameProjectContainers(ctx context.Context, moves []projectMove) error {
+	var stopped, renamed []projectMove
+
+	for _, m := range moves {
+		if !m.wasRunning {
+			continue
+		}
+		printInfo("Stopping container '%s'...\n", m.name)
+		if err := lxc.Stop(m.oldLXC, 0); err != nil {
+			return rollbackProjectRename(renamed, stopped, err)
+		}
+		stopped = append(stopped, m)
+	}
+
+	for _, m := range moves {
+		if ctx.Err() != nil {
+			return rollbackProjectRename(renamed, stopped, fmt.Errorf("interrupted"))
+		}
+		printInfo("Renaming '%s' to '%s'...\n", m.oldLXC, m.newLXC)
+		err := lxc.Rename(m.oldLXC, m.newLXC)
+		if err == nil {
+			renamed = append(renamed, m)
+		}
+		// Ctrl+C also reaches lxc, so a failed rename may really be an interrupt
+		if ctx.Err() != nil {
+			return rollbackProjectRename(renamed, stopped, fmt.Errorf("interrupted"))
+		}
+		if err != nil {
+			return rollbackProjectRename(renamed, stopped, err)
+		}
+	}
+	return nil
+}
+
+// rollbackProjectRename renames containers back to their old LXC names, in
+// reverse order, and starts the ones that were stopped for the rename. The
+// returned error lists any step of the rollback that failed.
+func rollbackProjectRename(renamed, stopped []projectMove, cause error) error {
+	printInfo("Rolling back...\n")
+	var failures []string
+	for i := len(renamed) - 1; i >= 0; i-- {
+		m := renamed[i]
+		if err := lxc.Rename(m.newLXC, m.oldLXC); err != nil {
+			failures = append(failures, fmt.Sprintf("'%s' is still named '%s' (rename back failed: %v)", m.oldLXC, m.newLXC, err))
+		}
+	}
+	for _, m := range stopped {
+		if err := lxc.Start(m.oldLXC); err != nil {
+			failures = append(failures, fmt.Sprintf("'%s' was left stopped (restart failed: %v)", m.name, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("project rename aborted and the rollback was incomplete: %w\n  %s", cause, strings.Join(failures, "\n  "))
+	}
+	return fmt.Errorf("project rename aborted, nothing was changed: %w", cause)
+}
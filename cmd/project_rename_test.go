@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/validation"
+)
+
+const projectRenameConfig = `project: old
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+    depends_on: [dev1]
+`
+
+func TestProjectRename_Success(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+	env.setContainerExists("old-dev1", true)
+	env.setContainerExists("old-dev2", false)
+	env.setContainerNotExists("new-dev1")
+	env.setContainerNotExists("new-dev2")
+
+	env.captureStdout(func() {
+		if err := runProjectRename(nil, []string{"new"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for _, call := range [][]string{
+		{"stop", "old-dev1"},
+		{"rename", "old-dev1", "new-dev1"},
+		{"rename", "old-dev2", "new-dev2"},
+		{"start", "new-dev1"},
+	} {
+		if !env.mock.HasCall(call...) {
+			t.Errorf("expected call %v, got calls: %v", call, env.mock.Calls)
+		}
+	}
+	if env.mock.HasCall("start", "new-dev2") {
+		t.Error("container that was stopped should stay stopped")
+	}
+	if env.mock.HasCallPrefix("copy") || env.mock.HasCallPrefix("delete") {
+		t.Error("containers should be renamed in place, not copied")
+	}
+
+	cfg := env.readConfig()
+	if !strings.Contains(cfg, "project: new") {
+		t.Errorf("expected project to be renamed, got:\n%s", cfg)
+	}
+	if !strings.Contains(cfg, "dev1:") || !strings.Contains(cfg, "- dev1") {
+		t.Errorf("container names and dependencies should be unchanged, got:\n%s", cfg)
+	}
+}
+
+func TestProjectRename_RenameFailsRollsBack(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+	env.setContainerExists("old-dev1", true)
+	env.setContainerExists("old-dev2", false)
+	env.setContainerNotExists("new-dev1")
+	env.setContainerNotExists("new-dev2")
+	env.mock.SetError("rename old-dev2", "device busy")
+
+	var err error
+	env.captureStdout(func() {
+		err = runProjectRename(nil, []string{"new"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "nothing was changed") {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	if !env.mock.HasCall("rename", "new-dev1", "old-dev1") {
+		t.Error("expected the finished rename to be undone")
+	}
+	if env.mock.HasCallPrefix("rename", "new-dev2") {
+		t.Error("the failed rename should not be undone")
+	}
+	if !env.mock.HasCall("start", "old-dev1") {
+		t.Error("expected the stopped container to be restarted")
+	}
+	if !strings.Contains(env.readConfig(), "project: old") {
+		t.Error("config should keep the old project name")
+	}
+}
+
+func TestProjectRename_RollbackFailureReported(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+	env.setContainerExists("old-dev1", true)
+	env.setContainerExists("old-dev2", false)
+	env.setContainerNotExists("new-dev1")
+	env.setContainerNotExists("new-dev2")
+	env.mock.SetError("rename old-dev2", "device busy")
+	env.mock.SetError("rename new-dev1 old-dev1", "storage error")
+
+	var err error
+	env.captureStdout(func() {
+		err = runProjectRename(nil, []string{"new"})
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), "nothing was changed") {
+		t.Errorf("should not claim nothing changed when the rollback failed: %v", err)
+	}
+	for _, want := range []string{"rollback was incomplete", "'old-dev1' is still named 'new-dev1'", "device busy"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestProjectRename_NameTooLong(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+
+	// Long enough for the project alone, too long with "-dev1" appended
+	long := strings.Repeat("p", validation.MaxCombinedLength-3)
+	err := runProjectRename(nil, []string{long})
+	if err == nil || !strings.Contains(err.Error(), "too long") {
+		t.Fatalf("expected length error, got %v", err)
+	}
+	if len(env.mock.Calls) > 0 {
+		t.Errorf("LXC should not be touched when a new name is too long, got calls: %v", env.mock.Calls)
+	}
+	if !strings.Contains(env.readConfig(), "project: old") {
+		t.Error("config should keep the old project name")
+	}
+}
+
+func TestProjectRename_TargetExists(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+	env.setContainerExists("old-dev1", false)
+	env.setContainerExists("old-dev2", false)
+	env.setContainerExists("new-dev1", false)
+
+	err := runProjectRename(nil, []string{"new"})
+	if err == nil || !strings.Contains(err.Error(), "already exists in LXC") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if env.mock.HasCallPrefix("rename") || env.mock.HasCallPrefix("stop") {
+		t.Error("nothing should be renamed or stopped when a target name is taken")
+	}
+}
+
+func TestProjectRename_InvalidName(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+
+	err := runProjectRename(nil, []string{"bad name"})
+	if err == nil || !strings.Contains(err.Error(), "invalid project name") {
+		t.Errorf("expected invalid name error, got %v", err)
+	}
+}
+
+func TestProjectRename_SameName(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+
+	err := runProjectRename(nil, []string{"old"})
+	if err == nil || !strings.Contains(err.Error(), "already named") {
+		t.Errorf("expected same name error, got %v", err)
+	}
+}
+
+func TestProjectRename_SkipsMissingContainer(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(projectRenameConfig)
+	env.setContainerExists("old-dev1", false)
+	env.setContainerNotExists("old-dev2")
+	env.setContainerNotExists("new-dev1")
+	env.setContainerNotExists("new-dev2")
+
+	out := env.captureStdout(func() {
+		if err := runProjectRename(nil, []string{"new"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Skipping 'dev2'") {
+		t.Errorf("expected missing container to be skipped, got:\n%s", out)
+	}
+	if env.mock.HasCallPrefix("rename", "old-dev2") {
+		t.Error("missing container should not be renamed")
+	}
+	if !strings.Contains(env.readConfig(), "project: new") {
+		t.Error("expected project to be renamed")
+	}
+}
+
+func TestRenameProjectContainers_InterruptRollsBack(t *testing.T) {
+	env := setupTestEnv(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	// Simulate Ctrl+C arriving while the first rename runs
+	env.mock.SetCallback("rename old-dev1", func([]string) { cancel() })
+
+	moves := []projectMove{
+		{name: "dev1", oldLXC: "old-dev1", newLXC: "new-dev1", wasRunning: true},
+		{name: "dev2", oldLXC: "old-dev2", newLXC: "new-dev2"},
+	}
+
+	var err error
+	env.captureStdout(func() {
+		err = renameProjectContainers(ctx, moves)
+	})
+	if err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Fatalf("expected interrupted error, got %v", err)
+	}
+	if env.mock.HasCallPrefix("rename", "old-dev2") {
+		t.Error("no renames should start after an interrupt")
+	}
+	if !env.mock.HasCall("rename", "new-dev1", "old-dev1") {
+		t.Error("expected the rename made before the interrupt to be undone")
+	}
+	if !env.mock.HasCall("start", "old-dev1") {
+		t.Error("expected the stopped container to be restarted")
+	}
+}
@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/config"
+)
+
+func TestProjectDelete_DryRun(t *testing.T) {
+	env := setupTestEnv(t)
+	projectDeleteDryRun = true
+	t.Cleanup(func() { projectDeleteDryRun = false })
+
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", true)
+	env.setContainerNotExists("test-dev2")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runProjectDelete(nil, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if env.mock.HasCallPrefix("delete") {
+		t.Error("dry run should not delete containers")
+	}
+	if !env.configExists() {
+		t.Error("dry run should not remove config")
+	}
+	if !strings.Contains(out, "[DRY-RUN] Would run: lxc delete test-dev1 --force") {
+		t.Errorf("expected delete preview, got:\n%s", out)
+	}
+	if strings.Contains(out, "lxc delete test-dev2") {
+		t.Errorf("should not preview deleting a missing container:\n%s", out)
+	}
+	if !strings.Contains(out, "[DRY-RUN] Would remove containers.yaml") {
+		t.Errorf("expected config removal preview, got:\n%s", out)
+	}
+}
+
+func TestProjectDelete_ConfirmYes(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("delete test-dev1 --force", "")
+	env.setPromptInput("y\n")
+
+	env.captureStdout(func() {
+		if err := runProjectDelete(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("delete", "test-dev1", "--force") {
+		t.Error("expected dev1 to be deleted after confirming")
+	}
+	if env.configExists() {
+		t.Error("config should be removed after confirming")
+	}
+}
+
+func TestProjectDelete_ConfirmNo(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", false)
+	env.setPromptInput("n\n")
+
+	out := env.captureStdout(func() {
+		if err := runProjectDelete(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Cancelled.") {
+		t.Errorf("expected cancellation, got:\n%s", out)
+	}
+	if env.mock.HasCallPrefix("delete") {
+		t.Error("containers should not be deleted when declined")
+	}
+	if !env.configExists() {
+		t.Error("config should be kept when declined")
+	}
+}
+
+func TestProjectDelete_DeletesInParallel(t *testing.T) {
+	env := setupTestEnv(t)
+	projectDeleteForce = true
+	projectDeleteConcurrency = 2
+	t.Cleanup(func() {
+		projectDeleteForce = false
+		projectDeleteConcurrency = defaultConcurrency
+	})
+
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+  dev3:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("test-dev1", true)
+	env.setContainerNotExists("test-dev2")
+	env.setContainerExists("test-dev3", false)
+	env.mock.SetError("delete test-dev3 --force", "storage busy")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runProjectDelete(nil, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("delete", "test-dev1", "--force") {
+		t.Error("expected dev1 to be deleted")
+	}
+	if env.mock.HasCallPrefix("delete", "test-dev2") {
+		t.Error("missing container should not be deleted")
+	}
+	if env.configExists() {
+		t.Error("config should be removed")
+	}
+	for _, want := range []string{"✓ dev1 deleted", "✓ dev2 deleted", "✗ dev3 failed", "Some containers failed to delete"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+const exportTestConfig = `project: web
+defaults:
+  ports: [8000]
+  user:
+    name: dev
+    password: secret
+containers:
+  api:
+    image: ubuntu:24.04
+    user:
+      name: app
+      password: hunter2
+    snapshots:
+      clean:
+        description: fresh install
+        created_at: "2026-01-02T03:04:05Z"
+`
+
+func TestProjectExport_Stdout(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(exportTestConfig)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runProjectExport(nil, []string{})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(out, "created_at") || strings.Contains(out, "snapshots") {
+		t.Errorf("snapshots should be stripped, got:\n%s", out)
+	}
+	if !strings.Contains(out, "password: secret") {
+		t.Errorf("passwords should be kept without --strip-passwords, got:\n%s", out)
+	}
+	if !strings.Contains(env.readConfig(), "created_at") {
+		t.Error("export must not modify containers.yaml")
+	}
+}
+
+func TestProjectExport_FileStripPasswords(t *testing.T) {
+	env := setupTestEnv(t)
+	projectExportOutput = "template.yaml"
+	projectExportStripPasswords = true
+	t.Cleanup(func() {
+		projectExportOutput = ""
+		projectExportStripPasswords = false
+	})
+	env.writeConfig(exportTestConfig)
+
+	if err := runProjectExport(nil, []string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env.dir, "template.yaml"))
+	if err != nil {
+		t.Fatalf("template not written: %v", err)
+	}
+	out := string(data)
+	if strings.Contains(out, "password") {
+		t.Errorf("passwords should be stripped, got:\n%s", out)
+	}
+	if !strings.Contains(out, "name: app") || !strings.Contains(out, "image: ubuntu:24.04") {
+		t.Errorf("expected the rest of the config to be kept, got:\n%s", out)
+	}
+}
+
+func TestProjectExport_RefusesConfigFile(t *testing.T) {
+	env := setupTestEnv(t)
+	projectExportOutput = "./containers.yaml"
+	t.Cleanup(func() { projectExportOutput = "" })
+	env.writeConfig(exportTestConfig)
+
+	if err := runProjectExport(nil, []string{}); err == nil {
+		t.Fatal("expected error when exporting over containers.yaml")
+	}
+	if !strings.Contains(env.readConfig(), "created_at") {
+		t.Error("containers.yaml should be unchanged")
+	}
+}
+
+func TestProjectExport_NoProject(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runProjectExport(nil, []string{}); err == nil {
+		t.Fatal("expected error without a project")
+	}
+}
+
+func TestProjectImport(t *testing.T) {
+	env := setupTestEnv(t)
+	template := filepath.Join(env.dir, "template.yaml")
+	os.WriteFile(template, []byte(`project: web
+defaults:
+  ports: [8000]
+containers:
+  api:
+    image: ubuntu:24.04
+`), 0644)
+
+	if err := runProjectImport(nil, []string{template}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil || cfg == nil {
+		t.Fatalf("expected imported config, got %v (err %v)", cfg, err)
+	}
+	if cfg.Project != "web" || !cfg.HasContainer("api") {
+		t.Errorf("unexpected imported config: %+v", cfg)
+	}
+}
+
+func TestProjectImport_RelativeToWorkDir(t *testing.T) {
+	setupTestEnv(t)
+	// Run from elsewhere with -C: the template is next to the caller, not the project
+	caller := t.TempDir()
+	os.WriteFile(filepath.Join(caller, "tpl.yaml"), []byte("project: web\ncontainers: {}\n"), 0644)
+	workDir = caller
+
+	if err := runProjectImport(nil, []string{"./tpl.yaml"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil || cfg == nil || cfg.Project != "web" {
+		t.Fatalf("expected the template from the invocation directory, got %v (err %v)", cfg, err)
+	}
+}
+
+func TestProjectImport_ExistingProject(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig("project: current\ncontainers: {}\n")
+	template := filepath.Join(env.dir, "template.yaml")
+	os.WriteFile(template, []byte("project: web\ncontainers: {}\n"), 0644)
+
+	err := runProjectImport(nil, []string{template})
+	if err == nil {
+		t.Fatal("expected error when a project exists")
+	}
+	if !strings.Contains(err.Error(), "--force") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !strings.Contains(env.readConfig(), "project: current") {
+		t.Error("existing project should not be overwritten")
+	}
+
+	projectImportForce = true
+	t.Cleanup(func() { projectImportForce = false })
+
+	if err := runProjectImport(nil, []string{template}); err != nil {
+		t.Fatalf("unexpected error with --force: %v", err)
+	}
+	if !strings.Contains(env.readConfig(), "project: web") {
+		t.Errorf("expected project to be overwritten, got:\n%s", env.readConfig())
+	}
+}
+
+func TestProjectImport_Invalid(t *testing.T) {
+	env := setupTestEnv(t)
+	template := filepath.Join(env.dir, "template.yaml")
+	os.WriteFile(template, []byte(`project: "bad name"
+containers: {}
+`), 0644)
+
+	err := runProjectImport(nil, []string{template})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if env.configExists() {
+		t.Error("invalid template should not be written")
+	}
+}
+
+func TestProjectImport_MissingFile(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runProjectImport(nil, []string{"nope.yaml"}); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestProjectMerge(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: web
+defaults:
+  ports: [8000]
+containers:
+  api:
+    image: ubuntu:24.04
+`)
+	base := filepath.Join(env.dir, "base.yaml")
+	os.WriteFile(base, []byte(`defaults:
+  ports: [8000, 5432]
+  user:
+    name: team
+containers:
+  db:
+    image: debian:12
+`), 0644)
+
+	env.captureStdout(func() {
+		if err := runProjectMerge(nil, []string{base}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Project != "web" || !cfg.HasContainer("api") || !cfg.HasContainer("db") {
+		t.Errorf("unexpected merged config: %+v", cfg)
+	}
+	if got := formatPorts(cfg.Defaults.Ports); got != "8000,5432" {
+		t.Errorf("expected merged default ports 8000,5432, got %s", got)
+	}
+	if cfg.Defaults.User.Name != "team" {
+		t.Errorf("expected default user from base, got %+v", cfg.Defaults.User)
+	}
+}
+
+func TestProjectMerge_Duplicate(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("db", "ubuntu:24.04")
+	base := filepath.Join(env.dir, "base.yaml")
+	os.WriteFile(base, []byte("containers:\n  db:\n    image: debian:12\n"), 0644)
+
+	err := runProjectMerge(nil, []string{base})
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected duplicate error mentioning --force, got %v", err)
+	}
+	if !strings.Contains(env.readConfig(), "ubuntu:24.04") {
+		t.Error("config should not change when the merge fails")
+	}
+
+	projectMergeForce = true
+	t.Cleanup(func() { projectMergeForce = false })
+
+	env.captureStdout(func() {
+		if err := runProjectMerge(nil, []string{base}); err != nil {
+			t.Fatalf("unexpected error with --force: %v", err)
+		}
+	})
+	if !strings.Contains(env.readConfig(), "debian:12") {
+		t.Errorf("expected container to be overwritten, got:\n%s", env.readConfig())
+	}
+}
+
+func TestProjectMerge_Errors(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("api", "ubuntu:24.04")
+
+	if err := runProjectMerge(nil, []string{filepath.Join(env.dir, "missing.yaml")}); err == nil {
+		t.Error("expected error for a missing file")
+	}
+	if err := runProjectMerge(nil, []string{config.ConfigFile}); err == nil || !strings.Contains(err.Error(), "into itself") {
+		t.Errorf("expected error merging the project config into itself, got %v", err)
+	}
+}
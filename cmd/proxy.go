@@ -1,42 +1,180 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 	"lxc-dev-manager/internal/proxy"
+	"lxc-dev-manager/internal/validation"
 
 	"github.com/spf13/cobra"
 )
 
 var proxyCmd = &cobra.Command{
-	Use:   "proxy <name>",
+	Use:   "proxy [name]",
 	Short: "Proxy ports from localhost to container",
 	Long: `Start a TCP proxy to forward ports from localhost to the container.
 
 This allows you to access container services as if they were running locally.
 All ports defined in the config will be forwarded.
 
+With --daemon, the proxy runs in the background and is recorded in
+.lxc-proxies.json. Use 'proxy status' to list background proxies and
+'proxy stop <name>' to stop one.
+
+With --all, every running container in the project is proxied at once.
+Stopped containers are skipped with a warning. If two containers use the
+same local port, nothing is started and the conflicts are listed.
+
+Proxies listen on 127.0.0.1 only. Use --bind (or defaults.bind in
+containers.yaml) to listen on another address, e.g. 0.0.0.0 to expose
+the ports to your network.
+
+With --stats, the number of connections and bytes forwarded are printed
+when the proxy stops.
+
+With --watch, the container IPs are checked every few seconds. When a
+container comes back with a new IP (e.g. after a restart), its proxies are
+restarted to forward to the new address.
+
+With --health-check, each container port is tried first and a warning is
+printed for ports nothing listens on yet. The proxies start either way.
+
 Press Ctrl+C to stop the proxy.
 
 Example:
   lxc-dev-manager proxy dev1
+  lxc-dev-manager proxy dev1 --bind 0.0.0.0
+  lxc-dev-manager proxy dev1 --daemon
+  lxc-dev-manager proxy --all
+  lxc-dev-manager proxy dev1 --stats
+  lxc-dev-manager proxy --all --watch
+  lxc-dev-manager proxy dev1 --health-check
 
 Then access services at:
   http://localhost:5173  ->  container:5173
   http://localhost:8000  ->  container:8000`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runProxy,
 }
 
+var (
+	proxyBind        string
+	proxyAll         bool
+	proxyDaemon      bool
+	proxyDaemonChild bool
+	proxyStats       bool
+	proxyWatch       bool
+	proxyHealthCheck bool
+)
+
+// proxyWatchInterval is how often --watch re-resolves container IPs
+var proxyWatchInterval = 5 * time.Second
+
+// proxyManager is the subset of proxy.Manager used by the proxy command
+type proxyManager interface {
+	AddAll(specs []proxy.ProxySpec) error
+	Watch(ctx context.Context, interval time.Duration, resolve proxy.Resolver, report func(proxy.Change))
+	StopAll()
+	Stats() proxy.Stats
+}
+
+// newProxyManager creates the manager that binds local ports; replaced in tests
+var newProxyManager = func(bind string) proxyManager {
+	return proxy.NewManager(bind)
+}
+
+// remoteReachable reports whether a container port accepts connections;
+// replaced in tests
+var remoteReachable = func(ip string, port int) bool {
+	return proxy.New("", 0, ip, port).HealthCheck()
+}
+
+// waitForInterrupt blocks until Ctrl+C or SIGTERM; replaced in tests
+var waitForInterrupt = func() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+}
+
 func init() {
 	rootCmd.AddCommand(proxyCmd)
+	proxyCmd.Flags().StringVar(&proxyBind, "bind", "", "Local address to listen on (default: defaults.bind or 127.0.0.1)")
+	proxyCmd.Flags().StringVar(&proxyBind, "bind-addr", "", "Alias for --bind")
+	proxyCmd.Flags().MarkHidden("bind-addr")
+	proxyCmd.Flags().BoolVar(&proxyAll, "all", false, "Proxy every running container in the project")
+	proxyCmd.Flags().BoolVar(&proxyDaemon, "daemon", false, "Run the proxy in the background")
+	proxyCmd.Flags().BoolVar(&proxyDaemonChild, "daemon-child", false, "Run as the background process started by --daemon")
+	proxyCmd.Flags().MarkHidden("daemon-child")
+	proxyCmd.Flags().BoolVar(&proxyStats, "stats", false, "Print connection and traffic totals when the proxy stops")
+	proxyCmd.Flags().BoolVar(&proxyWatch, "watch", false, "Follow container IP changes and reconnect the proxies")
+	proxyCmd.Flags().BoolVar(&proxyHealthCheck, "health-check", false, "Warn about container ports that are not accepting connections yet")
+}
+
+// resolveBindAddr picks the proxy listen address: --bind, then defaults.bind, then loopback
+func resolveBindAddr(cfg *config.Config) (string, error) {
+	bind := proxyBind
+	if bind == "" {
+		bind = cfg.Defaults.Bind
+	}
+	if bind == "" {
+		return proxy.DefaultBindAddr, nil
+	}
+	if err := validation.ValidateBindAddr(bind); err != nil {
+		return "", err
+	}
+	return bind, nil
+}
+
+// displayHost returns how a bind address is shown in proxy output
+func displayHost(bind string) string {
+	if bind == proxy.DefaultBindAddr {
+		return "localhost"
+	}
+	return bind
+}
+
+// hostPort formats an address for display, bracketing IPv6 hosts
+func hostPort(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// proxyIP returns the address to forward to: the container's IPv4 address,
+// or its IPv6 address on IPv6-only networks
+func proxyIP(lxcName string) (string, error) {
+	v4, v6, err := lxc.GetAddresses(lxcName)
+	if err != nil {
+		return "", err
+	}
+	if v4 != "" {
+		return v4, nil
+	}
+	return v6, nil
 }
 
 func runProxy(cmd *cobra.Command, args []string) error {
+	if proxyAll {
+		if len(args) > 0 {
+			return fmt.Errorf("cannot combine a container name with --all")
+		}
+		if proxyDaemon {
+			return fmt.Errorf("--daemon proxies one container at a time; it cannot be combined with --all")
+		}
+		return runProxyAll()
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("requires a container name (or --all)")
+	}
 	name := args[0]
 
 	cfg, lxcName, err := requireRunningContainer(name)
@@ -45,38 +183,259 @@ func runProxy(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get container IP
-	ip, err := lxc.GetIP(lxcName)
+	ip, err := proxyIP(lxcName)
 	if err != nil {
 		return fmt.Errorf("failed to get container IP: %w", err)
 	}
 
-	// Get ports from config
-	ports := cfg.GetPorts(name)
+	// Get port mappings from config
+	ports := cfg.GetPortMappings(name)
 	if len(ports) == 0 {
 		return fmt.Errorf("no ports configured for container '%s'", name)
 	}
 
+	bind, err := resolveBindAddr(cfg)
+	if err != nil {
+		return err
+	}
+
+	if proxyHealthCheck {
+		checkRemotePorts(name, ip, ports)
+	}
+
+	if proxyDaemon {
+		return startProxyDaemon(name, ip, bind, ports)
+	}
+
 	// Start proxies
-	manager := proxy.NewManager()
+	manager := newProxyManager(bind)
 
-	fmt.Printf("Proxying %s (%s):\n", name, ip)
-	for _, port := range ports {
-		if err := manager.Add(port, ip, port); err != nil {
+	if err := manager.AddAll(proxySpecs(name, ip, ports)); err != nil {
+		return fmt.Errorf("failed to start proxies: %w", err)
+	}
+	printInfo("Proxying %s (%s):\n", name, ip)
+	for _, m := range ports {
+		printInfo("  %s -> %s\n", hostPort(displayHost(bind), m.Local), hostPort(ip, m.Remote))
+	}
+
+	if proxyDaemonChild {
+		// Register only once every port is bound, so the parent knows startup succeeded
+		if err := recordProxyDaemon(name, ip, bind, ports); err != nil {
 			manager.StopAll()
-			return fmt.Errorf("failed to start proxy for port %d: %w", port, err)
+			return err
 		}
-		fmt.Printf("  localhost:%d -> %s:%d\n", port, ip, port)
+		defer forgetProxyDaemon(name, os.Getpid())
+	} else {
+		printInfo("\nPress Ctrl+C to stop\n")
 	}
 
-	fmt.Println("\nPress Ctrl+C to stop")
+	stopWatch := watchProxies(manager, cfg, func(c proxy.Change) {
+		// Keep 'proxy status' showing the address actually forwarded to
+		if proxyDaemonChild && c.Err == nil {
+			recordProxyDaemon(name, c.NewIP, bind, ports)
+		}
+	})
+	waitForInterrupt()
+	stopWatch()
 
-	// Wait for interrupt
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	printInfo("\nStopping proxy...\n")
+	manager.StopAll()
+	if proxyStats {
+		printProxyStats(manager.Stats())
+	}
+
+	return nil
+}
+
+// proxyTarget is a running container and the ports to forward to it
+type proxyTarget struct {
+	name  string
+	ip    string
+	ports []config.PortMapping
+}
+
+func runProxyAll() error {
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	bind, err := resolveBindAddr(cfg)
+	if err != nil {
+		return err
+	}
+
+	var targets []proxyTarget
+	for _, name := range containerNames(cfg) {
+		lxcName := cfg.GetLXCName(name)
+		if !lxc.Exists(lxcName) {
+			fmt.Printf("Warning: skipping '%s': does not exist in LXC\n", name)
+			continue
+		}
+		status, err := lxc.GetStatus(lxcName)
+		if err != nil {
+			fmt.Printf("Warning: skipping '%s': %v\n", name, err)
+			continue
+		}
+		if status != "RUNNING" {
+			fmt.Printf("Warning: skipping '%s': not running (status: %s)\n", name, status)
+			continue
+		}
+		ports := cfg.GetPortMappings(name)
+		if len(ports) == 0 {
+			fmt.Printf("Warning: skipping '%s': no ports configured\n", name)
+			continue
+		}
+		ip, err := proxyIP(lxcName)
+		if err != nil {
+			fmt.Printf("Warning: skipping '%s': failed to get IP: %v\n", name, err)
+			continue
+		}
+		targets = append(targets, proxyTarget{name: name, ip: ip, ports: ports})
+	}
+
+	if len(targets) == 0 {
+		return fmt.Errorf("no running containers with ports to proxy")
+	}
+
+	if err := checkPortConflicts(targets); err != nil {
+		return err
+	}
+
+	if proxyHealthCheck {
+		for _, t := range targets {
+			checkRemotePorts(t.name, t.ip, t.ports)
+		}
+	}
+
+	manager := newProxyManager(bind)
+	host := displayHost(bind)
+
+	var specs []proxy.ProxySpec
+	for _, t := range targets {
+		specs = append(specs, proxySpecs(t.name, t.ip, t.ports)...)
+	}
+	if err := manager.AddAll(specs); err != nil {
+		return fmt.Errorf("failed to start proxies: %w", err)
+	}
+
+	printInfo("Proxying:\n")
+	for _, t := range targets {
+		for _, m := range t.ports {
+			printInfo("  %s -> %s -> %s\n", t.name, hostPort(host, m.Local), hostPort(t.ip, m.Remote))
+		}
+	}
 
-	fmt.Println("\nStopping proxy...")
+	printInfo("\nPress Ctrl+C to stop\n")
+
+	stopWatch := watchProxies(manager, cfg, nil)
+	waitForInterrupt()
+	stopWatch()
+
+	printInfo("\nStopping proxies...\n")
 	manager.StopAll()
+	if proxyStats {
+		printProxyStats(manager.Stats())
+	}
 
 	return nil
 }
+
+// proxySpecs returns a TCP proxy spec for each port of a container
+func proxySpecs(name, ip string, ports []config.PortMapping) []proxy.ProxySpec {
+	specs := make([]proxy.ProxySpec, 0, len(ports))
+	for _, m := range ports {
+		specs = append(specs, proxy.ProxySpec{Container: name, LocalPort: m.Local, RemoteHost: ip, RemotePort: m.Remote})
+	}
+	return specs
+}
+
+// checkRemotePorts warns about each container port nothing listens on yet.
+// The proxies are started regardless: the service may just be starting.
+func checkRemotePorts(name, ip string, ports []config.PortMapping) {
+	for _, m := range ports {
+		if !remoteReachable(ip, m.Remote) {
+			fmt.Printf("Warning: nothing is listening on %s in '%s' yet\n", hostPort(ip, m.Remote), name)
+		}
+	}
+}
+
+// containerIPResolver looks up the current IP of a project container
+func containerIPResolver(cfg *config.Config) proxy.Resolver {
+	return func(name string) (string, error) {
+		return proxyIP(cfg.GetLXCName(name))
+	}
+}
+
+// watchProxies starts following container IP changes when --watch is set,
+// printing each change and passing it to onChange (which may be nil).
+// The returned function stops watching.
+func watchProxies(manager proxyManager, cfg *config.Config, onChange func(proxy.Change)) func() {
+	if !proxyWatch {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager.Watch(ctx, proxyWatchInterval, containerIPResolver(cfg), func(c proxy.Change) {
+		if c.Err != nil {
+			fmt.Printf("Warning: IP of '%s' changed (%s -> %s) but %v\n", c.Container, c.OldIP, c.NewIP, c.Err)
+		} else {
+			printInfo("IP of '%s' changed (%s -> %s), proxies reconnected\n", c.Container, c.OldIP, c.NewIP)
+		}
+		if onChange != nil {
+			onChange(c)
+		}
+	})
+	return cancel
+}
+
+// printProxyStats prints the totals collected by --stats
+func printProxyStats(stats proxy.Stats) {
+	fmt.Println("\nProxy stats:")
+	fmt.Printf("  Ports:       %d\n", stats.Proxies)
+	fmt.Printf("  Connections: %d\n", stats.TotalConnections)
+	fmt.Printf("  Transferred: %s\n", formatBytes(stats.BytesTransferred))
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "1.5 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// checkPortConflicts returns an error listing every local port claimed by more than one container
+func checkPortConflicts(targets []proxyTarget) error {
+	owners := make(map[int][]string)
+	for _, t := range targets {
+		for _, m := range t.ports {
+			owners[m.Local] = append(owners[m.Local], t.name)
+		}
+	}
+
+	var conflicts []int
+	for port, names := range owners {
+		if len(names) > 1 {
+			conflicts = append(conflicts, port)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Ints(conflicts)
+
+	var b strings.Builder
+	b.WriteString("local port conflicts between containers:")
+	for _, port := range conflicts {
+		fmt.Fprintf(&b, "\n  %d: %s", port, strings.Join(owners[port], ", "))
+	}
+	b.WriteString("\nGive each container distinct local ports (e.g. ports: [\"8001:8000\"]) in containers.yaml")
+	return fmt.Errorf("%s", b.String())
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/proxy"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// proxyLogFile collects the output of background proxies
+	proxyLogFile = ".lxc-proxies.log"
+	// daemonStartTimeout is how long --daemon waits for the background proxy to bind its ports
+	daemonStartTimeout = 5 * time.Second
+	// daemonStopTimeout is how long 'proxy stop' waits for the process to exit
+	daemonStopTimeout = 5 * time.Second
+)
+
+var proxyStopCmd = &cobra.Command{
+	Use:   "stop <name>",
+	Short: "Stop a background proxy",
+	Long: `Stop a proxy started with 'proxy --daemon'.
+
+Example:
+  lxc-dev-manager proxy stop dev1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProxyStop,
+}
+
+var proxyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List background proxies",
+	Long: `List proxies started with 'proxy --daemon'.
+
+Entries whose process has exited are reported and removed.
+
+Example:
+  lxc-dev-manager proxy status`,
+	Args: cobra.NoArgs,
+	RunE: runProxyStatus,
+}
+
+func init() {
+	proxyCmd.AddCommand(proxyStopCmd)
+	proxyCmd.AddCommand(proxyStatusCmd)
+
+	proxyStopCmd.ValidArgsFunction = completeProxyDaemons
+}
+
+// spawnProxyDaemon starts 'proxy <name> --daemon-child' detached from the
+// terminal and returns its PID once it has registered; replaced in tests
+var spawnProxyDaemon = func(name, bind string) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find executable: %w", err)
+	}
+
+	logFile, err := os.OpenFile(proxyLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", proxyLogFile, err)
+	}
+	defer logFile.Close()
+
+	args := []string{"proxy", name, "--bind", bind, "--daemon-child"}
+	if proxyWatch {
+		args = append(args, "--watch")
+	}
+	c := exec.Command(exe, args...)
+	c.Stdout = logFile
+	c.Stderr = logFile
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start proxy daemon: %w", err)
+	}
+	pid := c.Process.Pid
+
+	exited := make(chan error, 1)
+	go func() { exited <- c.Wait() }()
+
+	deadline := time.After(daemonStartTimeout)
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case err := <-exited:
+			return 0, fmt.Errorf("proxy daemon exited during startup (%v), see %s", err, proxyLogFile)
+		case <-deadline:
+			c.Process.Kill()
+			return 0, fmt.Errorf("proxy daemon did not start within %s, see %s", daemonStartTimeout, proxyLogFile)
+		case <-tick.C:
+			state, err := proxy.LoadState(proxy.StateFile)
+			if err != nil {
+				continue
+			}
+			if entry, ok := state.Proxies[name]; ok && entry.PID == pid {
+				return pid, nil
+			}
+		}
+	}
+}
+
+// startProxyDaemon replaces any stale or outdated entry for the container and starts a background proxy
+func startProxyDaemon(name, ip, bind string, ports []config.PortMapping) error {
+	state, err := proxy.LoadState(proxy.StateFile)
+	if err != nil {
+		return err
+	}
+
+	for _, stale := range state.PruneStale() {
+		printInfo("Removed stale proxy entry for '%s'\n", stale)
+	}
+
+	if entry, ok := state.Proxies[name]; ok {
+		if entry.IP == ip && entry.Bind == bind && samePortForwards(entry.Ports, ports) {
+			return fmt.Errorf("proxy for '%s' is already running (pid %d). Stop it with: lxc-dev-manager proxy stop %s", name, entry.PID, name)
+		}
+		if entry.IP != ip {
+			printInfo("IP of '%s' changed (%s -> %s), replacing its proxy (pid %d)...\n", name, entry.IP, ip, entry.PID)
+		} else {
+			printInfo("Proxy settings for '%s' changed, replacing its proxy (pid %d)...\n", name, entry.PID)
+		}
+		if err := stopProcess(entry.PID); err != nil {
+			return err
+		}
+		delete(state.Proxies, name)
+	}
+
+	if err := proxy.SaveState(proxy.StateFile, state); err != nil {
+		return err
+	}
+
+	pid, err := spawnProxyDaemon(name, bind)
+	if err != nil {
+		return err
+	}
+
+	printInfo("Proxying %s (%s) in the background (pid %d):\n", name, ip, pid)
+	for _, m := range ports {
+		printInfo("  %s -> %s\n", hostPort(displayHost(bind), m.Local), hostPort(ip, m.Remote))
+	}
+	printInfo("\nStop with: lxc-dev-manager proxy stop %s\n", name)
+
+	return nil
+}
+
+// recordProxyDaemon adds the current process to the state file
+func recordProxyDaemon(name, ip, bind string, ports []config.PortMapping) error {
+	state, err := proxy.LoadState(proxy.StateFile)
+	if err != nil {
+		return err
+	}
+
+	forwards := make([]proxy.PortForward, len(ports))
+	for i, m := range ports {
+		forwards[i] = proxy.PortForward{Local: m.Local, Remote: m.Remote}
+	}
+
+	state.Proxies[name] = proxy.DaemonEntry{
+		Container: name,
+		IP:        ip,
+		Bind:      bind,
+		Ports:     forwards,
+		PID:       os.Getpid(),
+		StartedAt: time.Now().Format(time.RFC3339),
+	}
+	return proxy.SaveState(proxy.StateFile, state)
+}
+
+// forgetProxyDaemon removes the container's entry if it still belongs to pid
+func forgetProxyDaemon(name string, pid int) {
+	state, err := proxy.LoadState(proxy.StateFile)
+	if err != nil {
+		return
+	}
+	if entry, ok := state.Proxies[name]; ok && entry.PID == pid {
+		delete(state.Proxies, name)
+		proxy.SaveState(proxy.StateFile, state)
+	}
+}
+
+// stopProcess sends SIGTERM and waits for the process to exit
+func stopProcess(pid int) error {
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		if err == syscall.ESRCH {
+			return nil
+		}
+		return fmt.Errorf("failed to stop proxy (pid %d): %w", pid, err)
+	}
+
+	deadline := time.Now().Add(daemonStopTimeout)
+	for proxy.ProcessRunning(pid) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("proxy (pid %d) did not exit within %s", pid, daemonStopTimeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+func samePortForwards(forwards []proxy.PortForward, ports []config.PortMapping) bool {
+	if len(forwards) != len(ports) {
+		return false
+	}
+	for i, m := range ports {
+		if forwards[i].Local != m.Local || forwards[i].Remote != m.Remote {
+			return false
+		}
+	}
+	return true
+}
+
+func runProxyStop(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	state, err := proxy.LoadState(proxy.StateFile)
+	if err != nil {
+		return err
+	}
+
+	entry, ok := state.Proxies[name]
+	if !ok {
+		return fmt.Errorf("no background proxy for '%s'", name)
+	}
+
+	if !proxy.ProcessRunning(entry.PID) {
+		printInfo("Proxy for '%s' was not running (pid %d); removed stale entry\n", name, entry.PID)
+	} else {
+		printInfo("Stopping proxy for '%s' (pid %d)...\n", name, entry.PID)
+		if err := stopProcess(entry.PID); err != nil {
+			return err
+		}
+		printInfo("Proxy for '%s' stopped\n", name)
+	}
+
+	delete(state.Proxies, name)
+	return proxy.SaveState(proxy.StateFile, state)
+}
+
+func runProxyStatus(cmd *cobra.Command, args []string) error {
+	state, err := proxy.LoadState(proxy.StateFile)
+	if err != nil {
+		return err
+	}
+
+	stale := state.PruneStale()
+	if len(stale) > 0 {
+		if err := proxy.SaveState(proxy.StateFile, state); err != nil {
+			return err
+		}
+	}
+
+	if len(state.Proxies) == 0 {
+		fmt.Println("No background proxies running")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CONTAINER\tPID\tIP\tPORTS\tSTARTED")
+		for _, name := range state.Names() {
+			entry := state.Proxies[name]
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n",
+				name, entry.PID, entry.IP, formatPortForwards(entry), entry.StartedAt)
+		}
+		w.Flush()
+	}
+
+	for _, name := range stale {
+		printInfo("Removed stale entry for '%s' (process no longer running)\n", name)
+	}
+
+	return nil
+}
+
+// formatPortForwards renders an entry's ports as host:local->remote pairs
+func formatPortForwards(entry proxy.DaemonEntry) string {
+	parts := make([]string, len(entry.Ports))
+	for i, p := range entry.Ports {
+		parts[i] = fmt.Sprintf("%s:%d->%d", displayHost(entry.Bind), p.Local, p.Remote)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// completeProxyDaemons completes container names that have a background proxy
+func completeProxyDaemons(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	state, err := proxy.LoadState(proxy.StateFile)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return state.Names(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/proxy"
+)
+
+// deadPID returns the PID of a process that has already exited
+func deadPID(t *testing.T) int {
+	t.Helper()
+	c := exec.Command("true")
+	if err := c.Run(); err != nil {
+		t.Skipf("cannot run 'true': %v", err)
+	}
+	return c.Process.Pid
+}
+
+// startSleeper starts a background process that exits on SIGTERM
+func startSleeper(t *testing.T) int {
+	t.Helper()
+	c := exec.Command("sleep", "30")
+	if err := c.Start(); err != nil {
+		t.Skipf("cannot run 'sleep': %v", err)
+	}
+	// Reap the process so it does not linger as a zombie once signalled
+	go c.Wait()
+	t.Cleanup(func() { c.Process.Kill() })
+	return c.Process.Pid
+}
+
+func writeProxyState(t *testing.T, entries ...proxy.DaemonEntry) {
+	t.Helper()
+	state := &proxy.State{Proxies: map[string]proxy.DaemonEntry{}}
+	for _, e := range entries {
+		state.Proxies[e.Container] = e
+	}
+	if err := proxy.SaveState(proxy.StateFile, state); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProxyStatus_NoProxies(t *testing.T) {
+	env := setupTestEnv(t)
+
+	out := env.captureStdout(func() {
+		if err := runProxyStatus(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "No background proxies") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestProxyStatus_ListsAndPrunesStale(t *testing.T) {
+	env := setupTestEnv(t)
+	writeProxyState(t,
+		proxy.DaemonEntry{Container: "dev1", IP: "10.0.0.2", Bind: "127.0.0.1", PID: os.Getpid(),
+			Ports: []proxy.PortForward{{Local: 8080, Remote: 80}}},
+		proxy.DaemonEntry{Container: "dev2", IP: "10.0.0.3", PID: deadPID(t)},
+	)
+
+	out := env.captureStdout(func() {
+		if err := runProxyStatus(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "localhost:8080->80") {
+		t.Errorf("expected dev1 ports, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Removed stale entry for 'dev2'") {
+		t.Errorf("expected stale warning for dev2, got:\n%s", out)
+	}
+
+	state, _ := proxy.LoadState(proxy.StateFile)
+	if _, ok := state.Proxies["dev2"]; ok {
+		t.Error("stale entry should be removed from the state file")
+	}
+}
+
+func TestProxyStop_Running(t *testing.T) {
+	env := setupTestEnv(t)
+	pid := startSleeper(t)
+	writeProxyState(t, proxy.DaemonEntry{Container: "dev1", PID: pid})
+
+	env.captureStdout(func() {
+		if err := runProxyStop(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if proxy.ProcessRunning(pid) {
+		t.Error("expected proxy process to be stopped")
+	}
+	if _, err := os.Stat(proxy.StateFile); !os.IsNotExist(err) {
+		t.Error("expected state file to be removed once empty")
+	}
+}
+
+func TestProxyStop_Stale(t *testing.T) {
+	env := setupTestEnv(t)
+	writeProxyState(t, proxy.DaemonEntry{Container: "dev1", PID: deadPID(t)})
+
+	out := env.captureStdout(func() {
+		if err := runProxyStop(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "removed stale entry") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestProxyStop_Unknown(t *testing.T) {
+	setupTestEnv(t)
+
+	err := runProxyStop(nil, []string{"dev1"})
+	if err == nil || !strings.Contains(err.Error(), "no background proxy") {
+		t.Errorf("expected no background proxy error, got: %v", err)
+	}
+}
+
+// fakeSpawn replaces spawnProxyDaemon and records the containers it was asked to start
+func fakeSpawn(t *testing.T) *[]string {
+	t.Helper()
+	var spawned []string
+	old := spawnProxyDaemon
+	spawnProxyDaemon = func(name, bind string) (int, error) {
+		spawned = append(spawned, name)
+		return 12345, nil
+	}
+	t.Cleanup(func() { spawnProxyDaemon = old })
+	return &spawned
+}
+
+func TestStartProxyDaemon_AlreadyRunning(t *testing.T) {
+	setupTestEnv(t)
+	spawned := fakeSpawn(t)
+	ports := []config.PortMapping{{Local: 8080, Remote: 80}}
+	writeProxyState(t, proxy.DaemonEntry{Container: "dev1", IP: "10.0.0.2", Bind: "127.0.0.1",
+		PID: os.Getpid(), Ports: []proxy.PortForward{{Local: 8080, Remote: 80}}})
+
+	err := startProxyDaemon("dev1", "10.0.0.2", "127.0.0.1", ports)
+	if err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("expected already running error, got: %v", err)
+	}
+	if len(*spawned) != 0 {
+		t.Error("should not spawn a second daemon")
+	}
+}
+
+func TestStartProxyDaemon_IPChangedReplaces(t *testing.T) {
+	env := setupTestEnv(t)
+	spawned := fakeSpawn(t)
+	oldPID := startSleeper(t)
+	writeProxyState(t, proxy.DaemonEntry{Container: "dev1", IP: "10.0.0.2", Bind: "127.0.0.1",
+		PID: oldPID, Ports: []proxy.PortForward{{Local: 8080, Remote: 80}}})
+
+	out := env.captureStdout(func() {
+		err := startProxyDaemon("dev1", "10.0.0.9", "127.0.0.1", []config.PortMapping{{Local: 8080, Remote: 80}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if proxy.ProcessRunning(oldPID) {
+		t.Error("expected old proxy to be stopped")
+	}
+	if len(*spawned) != 1 {
+		t.Errorf("expected one new daemon, got %v", *spawned)
+	}
+	if !strings.Contains(out, "10.0.0.2 -> 10.0.0.9") {
+		t.Errorf("expected IP change message, got:\n%s", out)
+	}
+}
+
+func TestStartProxyDaemon_StaleEntryReplaced(t *testing.T) {
+	env := setupTestEnv(t)
+	spawned := fakeSpawn(t)
+	writeProxyState(t, proxy.DaemonEntry{Container: "dev1", IP: "10.0.0.2", PID: deadPID(t)})
+
+	env.captureStdout(func() {
+		if err := startProxyDaemon("dev1", "10.0.0.2", "127.0.0.1", []config.PortMapping{{Local: 8080, Remote: 80}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if len(*spawned) != 1 {
+		t.Errorf("expected daemon to be started, got %v", *spawned)
+	}
+}
+
+func TestRecordAndForgetProxyDaemon(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := recordProxyDaemon("dev1", "10.0.0.2", "127.0.0.1", []config.PortMapping{{Local: 8080, Remote: 80}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, _ := proxy.LoadState(proxy.StateFile)
+	entry, ok := state.Proxies["dev1"]
+	if !ok || entry.PID != os.Getpid() || entry.Ports[0].Remote != 80 {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+
+	// Another PID's entry is left alone
+	forgetProxyDaemon("dev1", os.Getpid()+1)
+	state, _ = proxy.LoadState(proxy.StateFile)
+	if _, ok := state.Proxies["dev1"]; !ok {
+		t.Error("entry for a different PID should be kept")
+	}
+
+	forgetProxyDaemon("dev1", os.Getpid())
+	state, _ = proxy.LoadState(proxy.StateFile)
+	if _, ok := state.Proxies["dev1"]; ok {
+		t.Error("entry should be removed")
+	}
+}
+
+func TestProxy_DaemonWithAll(t *testing.T) {
+	setupTestEnv(t)
+	proxyAll, proxyDaemon = true, true
+	t.Cleanup(func() { proxyAll, proxyDaemon = false, false })
+
+	if err := runProxy(nil, nil); err == nil {
+		t.Fatal("expected error combining --daemon with --all")
+	}
+}
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"lxc-dev-manager/internal/config"
+	"lxc-dev-manager/internal/proxy"
 )
 
 func TestProxy_ContainerNotExists(t *testing.T) {
@@ -212,3 +215,535 @@ containers:
 		t.Errorf("unexpected error: %v", proxyErr)
 	}
 }
+
+func TestResolveBindAddr(t *testing.T) {
+	t.Cleanup(func() { proxyBind = "" })
+
+	tests := []struct {
+		name    string
+		flag    string
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{"default loopback", "", "", "127.0.0.1", false},
+		{"from config", "", "0.0.0.0", "0.0.0.0", false},
+		{"flag overrides config", "192.168.1.10", "0.0.0.0", "192.168.1.10", false},
+		{"invalid flag", "not-an-ip", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxyBind = tt.flag
+			cfg := &config.Config{Defaults: config.Defaults{Bind: tt.config}}
+
+			got, err := resolveBindAddr(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// fakeProxyManager records AddForContainer calls instead of binding ports
+type fakeProxyManager struct {
+	adds     []string
+	stopped  bool
+	stats    proxy.Stats
+	watching bool
+	addErr   error
+}
+
+func (f *fakeProxyManager) AddAll(specs []proxy.ProxySpec) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+	for _, spec := range specs {
+		f.adds = append(f.adds, fmt.Sprintf("%d->%s:%d", spec.LocalPort, spec.RemoteHost, spec.RemotePort))
+	}
+	return nil
+}
+
+func (f *fakeProxyManager) Watch(ctx context.Context, interval time.Duration, resolve proxy.Resolver, report func(proxy.Change)) {
+	f.watching = true
+}
+
+func (f *fakeProxyManager) StopAll() {
+	f.stopped = true
+}
+
+func (f *fakeProxyManager) Stats() proxy.Stats {
+	return f.stats
+}
+
+// useFakeProxyManager swaps in a recording manager and a non-blocking interrupt wait
+func useFakeProxyManager(t *testing.T) *fakeProxyManager {
+	t.Helper()
+	fake := &fakeProxyManager{}
+	oldNew, oldWait := newProxyManager, waitForInterrupt
+	newProxyManager = func(bind string) proxyManager { return fake }
+	waitForInterrupt = func() {}
+	t.Cleanup(func() {
+		newProxyManager, waitForInterrupt = oldNew, oldWait
+		proxyAll = false
+		proxyStats = false
+		proxyWatch = false
+		proxyHealthCheck = false
+	})
+	return fake
+}
+
+// useFakeRemoteCheck makes the given container ports reachable and records
+// every port checked
+func useFakeRemoteCheck(t *testing.T, listening ...int) *[]string {
+	t.Helper()
+	var checked []string
+	old := remoteReachable
+	remoteReachable = func(ip string, port int) bool {
+		checked = append(checked, hostPort(ip, port))
+		return slices.Contains(listening, port)
+	}
+	t.Cleanup(func() { remoteReachable = old })
+	return &checked
+}
+
+func TestProxyAll_AddsRunningContainers(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+	proxyAll = true
+
+	env.writeConfig(`defaults:
+  ports: [5173]
+containers:
+  api:
+    image: ubuntu
+    ports: [8000, "8080:80"]
+  web:
+    image: ubuntu
+  worker:
+    image: ubuntu
+    ports: [9000]
+`)
+	env.setContainerExists("api", true)
+	env.mock.SetOutput("list api -c4 -f csv", "10.0.0.2 (eth0)")
+	env.setContainerExists("web", true)
+	env.mock.SetOutput("list web -c4 -f csv", "10.0.0.3 (eth0)")
+	env.setContainerExists("worker", false)
+
+	out := env.captureStdout(func() {
+		if err := runProxy(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	want := []string{"8000->10.0.0.2:8000", "8080->10.0.0.2:80", "5173->10.0.0.3:5173"}
+	if strings.Join(fake.adds, " ") != strings.Join(want, " ") {
+		t.Errorf("Add calls = %v, want %v", fake.adds, want)
+	}
+	if !fake.stopped {
+		t.Error("expected proxies to be stopped on interrupt")
+	}
+	if !strings.Contains(out, "skipping 'worker': not running") {
+		t.Errorf("expected warning for stopped container, got:\n%s", out)
+	}
+	if !strings.Contains(out, "api -> localhost:8080 -> 10.0.0.2:80") {
+		t.Errorf("expected grouped summary line, got:\n%s", out)
+	}
+}
+
+func TestProxyAll_PortConflict(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+	proxyAll = true
+
+	env.writeConfig(`defaults:
+  ports: [8000]
+containers:
+  api:
+    image: ubuntu
+  web:
+    image: ubuntu
+    ports: [8000, 3000]
+`)
+	env.setContainerExists("api", true)
+	env.setContainerExists("web", true)
+
+	var err error
+	env.captureStdout(func() {
+		err = runProxy(nil, nil)
+	})
+	if err == nil {
+		t.Fatal("expected conflict error")
+	}
+	if !strings.Contains(err.Error(), "8000: api, web") {
+		t.Errorf("expected conflict listing, got: %v", err)
+	}
+	if len(fake.adds) != 0 {
+		t.Errorf("no proxies should start on conflict, got %v", fake.adds)
+	}
+}
+
+func TestProxyAll_NoneRunning(t *testing.T) {
+	env := setupTestEnv(t)
+	useFakeProxyManager(t)
+	proxyAll = true
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+
+	var err error
+	env.captureStdout(func() {
+		err = runProxy(nil, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "no running containers") {
+		t.Errorf("expected no running containers error, got: %v", err)
+	}
+}
+
+func TestProxyAll_RejectsName(t *testing.T) {
+	setupTestEnv(t)
+	useFakeProxyManager(t)
+	proxyAll = true
+
+	if err := runProxy(nil, []string{"dev1"}); err == nil {
+		t.Fatal("expected error when combining a name with --all")
+	}
+}
+
+func TestProxy_RequiresName(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runProxy(nil, nil); err == nil {
+		t.Fatal("expected error without a container name")
+	}
+}
+
+func TestProxy_StatsPrintedOnStop(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+	fake.stats = proxy.Stats{Proxies: 2, TotalConnections: 7, BytesTransferred: 3 * 1024 * 1024}
+	proxyStats = true
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000, 5173]
+`)
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for _, want := range []string{"Proxy stats:", "Ports:       2", "Connections: 7", "Transferred: 3.0 MiB"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestProxy_NoStatsByDefault(t *testing.T) {
+	env := setupTestEnv(t)
+	useFakeProxyManager(t)
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000]
+`)
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if strings.Contains(out, "Proxy stats:") {
+		t.Errorf("stats should only be printed with --stats, got:\n%s", out)
+	}
+}
+
+func TestProxy_Watch(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+	proxyWatch = true
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000]
+`)
+	env.setContainerExists("dev1", true)
+
+	env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !fake.watching {
+		t.Error("expected --watch to start watching container IPs")
+	}
+}
+
+func TestProxy_NoWatchByDefault(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000]
+`)
+	env.setContainerExists("dev1", true)
+
+	env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if fake.watching {
+		t.Error("IPs should only be watched with --watch")
+	}
+}
+
+func TestProxy_AddFailure(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+	fake.addErr = fmt.Errorf("failed to listen on 127.0.0.1:8000: address already in use")
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000, 5173]
+`)
+	env.setContainerExists("dev1", true)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runProxy(nil, []string{"dev1"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to start proxies: failed to listen on 127.0.0.1:8000") {
+		t.Errorf("expected start error, got %v", err)
+	}
+	if strings.Contains(out, "Proxying") {
+		t.Errorf("nothing should be reported as proxied, got:\n%s", out)
+	}
+}
+
+func TestProxy_HealthCheckWarns(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+	checked := useFakeRemoteCheck(t, 8000)
+	proxyHealthCheck = true
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000, "5173:3000"]
+`)
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if strings.Join(*checked, " ") != "10.10.10.100:8000 10.10.10.100:3000" {
+		t.Errorf("unexpected ports checked: %v", *checked)
+	}
+	if !strings.Contains(out, "Warning: nothing is listening on 10.10.10.100:3000 in 'dev1' yet") {
+		t.Errorf("expected warning for the closed port, got:\n%s", out)
+	}
+	if strings.Contains(out, "10.10.10.100:8000 in 'dev1'") {
+		t.Errorf("did not expect a warning for the open port, got:\n%s", out)
+	}
+	if len(fake.adds) != 2 {
+		t.Errorf("expected both proxies to start despite the warning, got %v", fake.adds)
+	}
+}
+
+func TestProxyAll_HealthCheck(t *testing.T) {
+	env := setupTestEnv(t)
+	useFakeProxyManager(t)
+	checked := useFakeRemoteCheck(t)
+	proxyAll = true
+	proxyHealthCheck = true
+
+	env.writeConfig(`containers:
+  api:
+    image: ubuntu
+    ports: [8000]
+`)
+	env.setContainerExists("api", true)
+
+	out := env.captureStdout(func() {
+		if err := runProxy(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if len(*checked) != 1 {
+		t.Errorf("expected one port to be checked, got %v", *checked)
+	}
+	if !strings.Contains(out, "nothing is listening on 10.10.10.100:8000 in 'api' yet") {
+		t.Errorf("expected warning, got:\n%s", out)
+	}
+}
+
+func TestProxy_NoHealthCheckByDefault(t *testing.T) {
+	env := setupTestEnv(t)
+	useFakeProxyManager(t)
+	checked := useFakeRemoteCheck(t)
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000]
+`)
+	env.setContainerExists("dev1", true)
+
+	env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if len(*checked) != 0 {
+		t.Errorf("ports should only be checked with --health-check, got %v", *checked)
+	}
+}
+
+func TestContainerIPResolver_FollowsIPChange(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: myproj
+containers:
+  dev1:
+    image: ubuntu
+`)
+	env.mock.SetOutput("list myproj-dev1 -c4 -f csv", "127.0.0.2 (eth0)")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolve := containerIPResolver(cfg)
+
+	ip, err := resolve("dev1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := proxy.NewManager("127.0.0.1")
+	defer manager.StopAll()
+	if err := manager.AddForContainer("dev1", 59174, ip, 80); err != nil {
+		t.Fatal(err)
+	}
+
+	// Container restarted and came back with a new address
+	env.mock.SetOutput("list myproj-dev1 -c4 -f csv", "127.0.0.3 (eth0)")
+
+	changes := manager.Refresh(resolve)
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", changes)
+	}
+	if changes[0].OldIP != "127.0.0.2" || changes[0].NewIP != "127.0.0.3" || changes[0].Err != nil {
+		t.Errorf("unexpected change: %+v", changes[0])
+	}
+}
+
+func TestProxy_FallsBackToIPv6(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000]
+`)
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("list dev1 -c4 -f csv", "")
+	env.mock.SetOutput("list dev1 -c6 -f csv", "fd42::5 (eth0)")
+
+	out := env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if len(fake.adds) != 1 || fake.adds[0] != "8000->fd42::5:8000" {
+		t.Errorf("expected proxy to the IPv6 address, got %v", fake.adds)
+	}
+	if !strings.Contains(out, "localhost:8000 -> [fd42::5]:8000") {
+		t.Errorf("expected bracketed IPv6 address in output, got:\n%s", out)
+	}
+}
+
+func TestProxy_PrefersIPv4(t *testing.T) {
+	env := setupTestEnv(t)
+	fake := useFakeProxyManager(t)
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu
+    ports: [8000]
+`)
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("list dev1 -c6 -f csv", "fd42::5 (eth0)")
+
+	env.captureStdout(func() {
+		if err := runProxy(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if len(fake.adds) != 1 || fake.adds[0] != "8000->10.10.10.100:8000" {
+		t.Errorf("expected proxy to the IPv4 address, got %v", fake.adds)
+	}
+}
+
+func TestHostPort(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.5":  "10.0.0.5:80",
+		"fd42::5":   "[fd42::5]:80",
+		"localhost": "localhost:80",
+	}
+	for host, want := range tests {
+		if got := hostPort(host, 80); got != want {
+			t.Errorf("hostPort(%q, 80) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1024:            "1.0 KiB",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 30:         "3.0 GiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestProxy_BindAddrAlias(t *testing.T) {
+	t.Cleanup(func() {
+		proxyBind = ""
+		proxyCmd.Flags().Lookup("bind-addr").Changed = false
+	})
+
+	if err := proxyCmd.Flags().Set("bind-addr", "0.0.0.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proxyBind != "0.0.0.0" {
+		t.Errorf("--bind-addr should set the bind address, got %q", proxyBind)
+	}
+}
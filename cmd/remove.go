@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strings"
 
+	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 
 	"github.com/spf13/cobra"
@@ -18,19 +16,25 @@ var removeCmd = &cobra.Command{
 
 This will forcefully delete the container even if it's running.
 By default, asks for confirmation. Use --force to skip.
+Use --dry-run to print what would be deleted without deleting anything.
 
 Example:
   lxc-dev-manager remove dev1
-  lxc-dev-manager remove dev1 --force`,
+  lxc-dev-manager remove dev1 --force
+  lxc-dev-manager remove dev1 --dry-run`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRemove,
 }
 
-var removeForce bool
+var (
+	removeForce  bool
+	removeDryRun bool
+)
 
 func init() {
 	rootCmd.AddCommand(removeCmd)
 	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Skip confirmation prompt")
+	removeCmd.Flags().BoolVar(&removeDryRun, "dry-run", false, "Show what would be deleted without deleting")
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
@@ -59,15 +63,25 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		status, _ := lxc.GetStatus(lxcName)
 		ip, _ := lxc.GetIP(lxcName)
 
-		fmt.Printf("\nContainer: %s (LXC: %s)\n", name, lxcName)
-		fmt.Printf("  Status: %s\n", status)
+		printInfo("\nContainer: %s (LXC: %s)\n", name, lxcName)
+		printInfo("  Status: %s\n", status)
 		if ip != "" {
-			fmt.Printf("  IP: %s\n", ip)
+			printInfo("  IP: %s\n", ip)
 		}
 		if existsInConfig {
-			fmt.Printf("  In config: yes\n")
+			printInfo("  In config: yes\n")
 		}
-		fmt.Println()
+		printInfo("\n")
+	}
+
+	if removeDryRun {
+		if existsInLXC {
+			dryRunf("Would run: lxc delete %s --force", lxc.InstanceRef(lxcName))
+		}
+		if existsInConfig {
+			dryRunf("Would remove container '%s' from %s", name, config.ConfigFile)
+		}
+		return nil
 	}
 
 	// Ask for confirmation unless --force
@@ -80,7 +94,7 @@ func runRemove(cmd *cobra.Command, args []string) error {
 
 	// Delete from LXC if exists
 	if existsInLXC {
-		fmt.Printf("Deleting container '%s'...\n", name)
+		printInfo("Deleting container '%s'...\n", name)
 		if err := lxc.Delete(lxcName); err != nil {
 			return err
 		}
@@ -94,21 +108,6 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("Container '%s' removed\n", name)
+	printInfo("Container '%s' removed\n", name)
 	return nil
 }
-
-// confirmPrompt asks user for yes/no confirmation
-func confirmPrompt(question string) bool {
-	reader := bufio.NewReader(os.Stdin)
-
-	fmt.Printf("%s [y/N]: ", question)
-
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		return false
-	}
-
-	response = strings.ToLower(strings.TrimSpace(response))
-	return response == "y" || response == "yes"
-}
@@ -151,3 +151,112 @@ func TestRemove_BothConfigAndLXC(t *testing.T) {
 		t.Error("dev1 should be removed from config")
 	}
 }
+
+func TestRemove_DryRun(t *testing.T) {
+	env := setupTestEnv(t)
+	removeDryRun = true
+	t.Cleanup(func() { removeDryRun = false })
+
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runRemove(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if env.mock.HasCallPrefix("delete") {
+		t.Error("dry run should not delete")
+	}
+	if !strings.Contains(out, "[DRY-RUN] Would run: lxc delete dev1 --force") {
+		t.Errorf("expected delete preview, got:\n%s", out)
+	}
+	if !strings.Contains(out, "[DRY-RUN] Would remove container 'dev1'") {
+		t.Errorf("expected config preview, got:\n%s", out)
+	}
+
+	cfg, _ := config.Load()
+	if !cfg.HasContainer("dev1") {
+		t.Error("dry run should not change config")
+	}
+}
+
+func TestRemove_ConfirmYes(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("delete dev1 --force", "")
+	env.setPromptInput("y\n")
+
+	out := env.captureStdout(func() {
+		if err := runRemove(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Are you sure you want to delete container 'dev1'? [y/N]") {
+		t.Errorf("expected confirmation prompt, got:\n%s", out)
+	}
+	if !env.mock.HasCall("delete", "dev1", "--force") {
+		t.Error("expected delete command after confirming")
+	}
+	cfg, _ := config.Load()
+	if cfg.HasContainer("dev1") {
+		t.Error("dev1 should be removed from config")
+	}
+}
+
+func TestRemove_ConfirmNo(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+	env.setPromptInput("n\n")
+
+	out := env.captureStdout(func() {
+		if err := runRemove(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Cancelled") {
+		t.Errorf("expected cancellation, got:\n%s", out)
+	}
+	if env.mock.HasCallPrefix("delete") {
+		t.Error("container should not be deleted when declined")
+	}
+	cfg, _ := config.Load()
+	if !cfg.HasContainer("dev1") {
+		t.Error("dev1 should stay in config when declined")
+	}
+}
+
+func TestRemove_NoAnswerCancels(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+
+	env.captureStdout(func() {
+		if err := runRemove(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if env.mock.HasCallPrefix("delete") {
+		t.Error("container should not be deleted without an answer")
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"time"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var restartCmd = &cobra.Command{
+	Use:   "restart <name>",
+	Short: "Restart a container",
+	Long: `Stop and start a container.
+
+If the container is not running, it is simply started.
+Use --timeout to change how long to wait for a clean shutdown.
+
+Example:
+  lxc-dev-manager restart dev1
+  lxc-dev-manager restart dev1 --timeout 60s`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestart,
+}
+
+var restartTimeout time.Duration
+
+// restartIPTimeout is how long restart waits for the container to get an IP
+// address, like up's default --timeout
+var restartIPTimeout = 15 * time.Second
+
+func init() {
+	rootCmd.AddCommand(restartCmd)
+	restartCmd.Flags().DurationVar(&restartTimeout, "timeout", 30*time.Second, "How long to wait for the container to stop")
+}
+
+func runRestart(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	_, lxcName, err := requireContainer(name)
+	if err != nil {
+		return err
+	}
+
+	// Check current status
+	status, err := lxc.GetStatus(lxcName)
+	if err != nil {
+		return err
+	}
+
+	if status == "RUNNING" {
+		printInfo("Restarting container '%s'...\n", name)
+		if err := lxc.Restart(lxcName, restartTimeout); err != nil {
+			return err
+		}
+	} else {
+		printInfo("Container '%s' was not running (status: %s)\n", name, status)
+		printInfo("Starting container '%s'...\n", name)
+		if err := lxc.Start(lxcName); err != nil {
+			return err
+		}
+	}
+
+	// Networking takes a moment to come up after start
+	ip, err := lxc.WaitForIP(lxcName, restartIPTimeout)
+	if err != nil {
+		ip = "(pending)"
+	}
+
+	printInfo("Container '%s' restarted\n", name)
+	printInfo("  IP: %s\n", ip)
+
+	return nil
+}
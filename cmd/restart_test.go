@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestart_Running(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true) // Running
+	env.mock.SetOutput("stop dev1", "")
+	env.mock.SetOutput("start dev1", "")
+
+	err := runRestart(nil, []string{"dev1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("stop", "dev1", "--timeout", "30") {
+		t.Error("expected stop command with default timeout")
+	}
+	if !env.mock.HasCall("start", "dev1") {
+		t.Error("expected start command")
+	}
+}
+
+func TestRestart_AlreadyStopped(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false) // Stopped
+	env.mock.SetOutput("start dev1", "")
+
+	err := runRestart(nil, []string{"dev1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Should not call stop
+	if env.mock.HasCallPrefix("stop", "dev1") {
+		t.Error("should not stop already stopped container")
+	}
+	if !env.mock.HasCall("start", "dev1") {
+		t.Error("expected start command")
+	}
+}
+
+func TestRestart_StartFails(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("stop dev1", "")
+	env.mock.SetError("start dev1", "failed to start")
+
+	err := runRestart(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "failed to start") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRestart_NotExists(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerNotExists("dev1")
+
+	err := runRestart(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRestart_CustomTimeout(t *testing.T) {
+	env := setupTestEnv(t)
+	restartTimeout = 90 * time.Second
+	t.Cleanup(func() { restartTimeout = 30 * time.Second })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("stop dev1", "")
+	env.mock.SetOutput("start dev1", "")
+
+	if err := runRestart(nil, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("stop", "dev1", "--timeout", "90") {
+		t.Error("expected stop with --timeout 90")
+	}
+}
+
+func TestRestart_StopFails(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetError("stop dev1", "timed out")
+
+	err := runRestart(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if env.mock.HasCall("start", "dev1") {
+		t.Error("should not start when stop fails")
+	}
+}
@@ -1,24 +1,142 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+
+	"lxc-dev-manager/internal/config"
 
 	"github.com/spf13/cobra"
 )
 
+const (
+	// searchDepthEnv overrides how many parent directories are searched for containers.yaml
+	searchDepthEnv = "LXC_DEV_MANAGER_SEARCH_DEPTH"
+	// noProjectSearch marks commands that act on the current directory itself
+	noProjectSearch = "no-project-search"
+)
+
+// workDir is the directory the command was started from when it moved to a
+// parent project root or --project-dir; empty otherwise
+var workDir string
+
+// projectDir is the --project-dir flag
+var projectDir string
+
+// quiet is the --quiet flag: progress and success messages are not printed
+var quiet bool
+
 var rootCmd = &cobra.Command{
 	Use:   "lxc-dev-manager",
 	Short: "Manage LXC containers for local development",
 	Long: `lxc-dev-manager is a CLI tool to manage LXC containers for local development.
 
 It provides easy container lifecycle management and port proxying to make
-containers feel like local services.`,
+containers feel like local services.
+
+Commands can be run from any subdirectory of a project: the nearest
+containers.yaml up to 5 directories above is used (set
+LXC_DEV_MANAGER_SEARCH_DEPTH to change the limit). Use --project-dir to
+work on a project in another directory instead.`,
+	PersistentPreRunE: enterProjectRoot,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&projectDir, "project-dir", "C", "", "Run as if started in this directory")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print errors, warnings and requested output")
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// exitCodeError carries a child process exit code up to Execute
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// enterProjectRoot moves to the project root when run from a subdirectory
+func enterProjectRoot(cmd *cobra.Command, args []string) error {
+	if projectDir != "" {
+		return enterProjectDir(projectDir)
+	}
+	if cmd.Annotations[noProjectSearch] != "" {
+		return nil
+	}
+
+	if v := os.Getenv(searchDepthEnv); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			return fmt.Errorf("invalid %s %q: must be a non-negative number", searchDepthEnv, v)
+		}
+		config.MaxSearchDepth = depth
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	root, err := config.EnterProjectRoot()
+	if err != nil {
+		return err
+	}
+	if root != "" {
+		workDir = wd
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Using project at %s\n", root)
+		}
+	}
+	return nil
+}
+
+// enterProjectDir moves to the directory given with --project-dir. No parent
+// directories are searched: the project must be in dir itself.
+func enterProjectDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid --project-dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid --project-dir: %s is not a directory", dir)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if abs == wd {
+		return nil
+	}
+	if err := os.Chdir(abs); err != nil {
+		return fmt.Errorf("failed to change to %s: %w", abs, err)
+	}
+	workDir = wd
+	return nil
+}
+
+// hostPath resolves a relative host path against the directory the command
+// was started from, so paths keep working after moving to the project root
+func hostPath(p string) string {
+	if workDir == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(workDir, p)
+}
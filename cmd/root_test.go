@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lxc-dev-manager/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// enterSubdir creates and changes to a subdirectory of the test project
+func (e *testEnv) enterSubdir(parts ...string) string {
+	e.t.Helper()
+	sub := filepath.Join(append([]string{e.dir}, parts...)...)
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		e.t.Fatal(err)
+	}
+	if err := os.Chdir(sub); err != nil {
+		e.t.Fatal(err)
+	}
+	return sub
+}
+
+func TestEnterProjectRoot_FromSubdir(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+	sub := env.enterSubdir("src", "app")
+
+	if err := enterProjectRoot(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wd, _ := os.Getwd()
+	if wd != env.dir {
+		t.Errorf("expected working directory %s, got %s", env.dir, wd)
+	}
+	if workDir != sub {
+		t.Errorf("expected workDir %s, got %s", sub, workDir)
+	}
+	if _, err := requireProject(); err != nil {
+		t.Errorf("expected project to load from subdirectory: %v", err)
+	}
+}
+
+func TestEnterProjectRoot_AtRoot(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	if err := enterProjectRoot(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if workDir != "" {
+		t.Errorf("expected workDir to stay empty at the project root, got %s", workDir)
+	}
+}
+
+func TestEnterProjectRoot_SkippedForAnnotatedCommands(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+	sub := env.enterSubdir("nested")
+
+	cmd := &cobra.Command{Annotations: map[string]string{noProjectSearch: "true"}}
+	if err := enterProjectRoot(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wd, _ := os.Getwd()
+	if wd != sub {
+		t.Errorf("expected working directory to stay %s, got %s", sub, wd)
+	}
+}
+
+func TestEnterProjectRoot_SearchDepthEnv(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+	sub := env.enterSubdir("a", "b")
+
+	oldDepth := config.MaxSearchDepth
+	t.Cleanup(func() { config.MaxSearchDepth = oldDepth })
+	t.Setenv(searchDepthEnv, "1")
+
+	if err := enterProjectRoot(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wd, _ := os.Getwd()
+	if wd != sub {
+		t.Errorf("expected project two levels up to be out of reach, moved to %s", wd)
+	}
+}
+
+func TestEnterProjectRoot_InvalidSearchDepthEnv(t *testing.T) {
+	setupTestEnv(t)
+	t.Setenv(searchDepthEnv, "deep")
+
+	err := enterProjectRoot(&cobra.Command{}, nil)
+	if err == nil {
+		t.Fatal("expected error for invalid search depth")
+	}
+	if !strings.Contains(err.Error(), searchDepthEnv) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHostPath(t *testing.T) {
+	setupTestEnv(t)
+
+	if got := hostPath("file.txt"); got != "file.txt" {
+		t.Errorf("expected path unchanged without workDir, got %s", got)
+	}
+
+	workDir = "/home/user/project/src"
+	if got := hostPath("file.txt"); got != "/home/user/project/src/file.txt" {
+		t.Errorf("expected path relative to workDir, got %s", got)
+	}
+	if got := hostPath("../README.md"); got != "/home/user/project/README.md" {
+		t.Errorf("expected cleaned path, got %s", got)
+	}
+	if got := hostPath("/etc/hosts"); got != "/etc/hosts" {
+		t.Errorf("expected absolute path unchanged, got %s", got)
+	}
+}
+
+func TestMv_FromSubdirUsesStartingDirectory(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	env.mock.SetOutput("exec dev1 -- test -d /home/dev", "")
+	env.mock.SetOutput("file push", "")
+	env.mock.SetOutput("exec dev1 -- chown dev:dev /home/dev/notes.txt", "")
+
+	sub := env.enterSubdir("docs")
+	os.WriteFile("notes.txt", []byte("notes"), 0644)
+
+	if err := enterProjectRoot(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := runMv(nil, []string{"notes.txt", "dev1:/home/dev/notes.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("file", "push", filepath.Join(sub, "notes.txt"), "dev1//home/dev/notes.txt") {
+		t.Errorf("expected push of the file in the starting directory, got calls: %v", env.mock.Calls)
+	}
+}
+
+// withProjectDir sets --project-dir for one test
+func withProjectDir(t *testing.T, dir string) {
+	t.Helper()
+	projectDir = dir
+	t.Cleanup(func() { projectDir = "" })
+}
+
+func TestEnterProjectRoot_ProjectDir(t *testing.T) {
+	env := setupTestEnv(t)
+	other := t.TempDir()
+	os.WriteFile(filepath.Join(other, "containers.yaml"), []byte("project: other\ncontainers: {}\n"), 0644)
+	withProjectDir(t, other)
+
+	if err := enterProjectRoot(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := requireProject()
+	if err != nil {
+		t.Fatalf("expected project to load from --project-dir: %v", err)
+	}
+	if cfg.Project != "other" {
+		t.Errorf("expected project 'other', got %q", cfg.Project)
+	}
+	if got := hostPath("file.txt"); got != filepath.Join(env.dir, "file.txt") {
+		t.Errorf("relative host paths should resolve against the start directory, got %s", got)
+	}
+}
+
+func TestEnterProjectRoot_ProjectDirRelative(t *testing.T) {
+	env := setupTestEnv(t)
+	env.enterSubdir("projects", "api")
+	os.Chdir(env.dir)
+	withProjectDir(t, filepath.Join("projects", "api"))
+
+	// Applies to commands that skip the parent search too, e.g. project create
+	cmd := &cobra.Command{Annotations: map[string]string{noProjectSearch: "true"}}
+	if err := enterProjectRoot(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wd, _ := os.Getwd()
+	if wd != filepath.Join(env.dir, "projects", "api") {
+		t.Errorf("expected to move into the project dir, got %s", wd)
+	}
+}
+
+func TestEnterProjectRoot_ProjectDirNoSearch(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+	sub := filepath.Join(env.dir, "src")
+	os.MkdirAll(sub, 0755)
+	withProjectDir(t, sub)
+
+	if err := enterProjectRoot(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The parent's config is not picked up
+	if _, err := requireProject(); err == nil {
+		t.Error("expected no project in --project-dir without containers.yaml")
+	}
+}
+
+func TestEnterProjectRoot_ProjectDirInvalid(t *testing.T) {
+	env := setupTestEnv(t)
+	os.WriteFile(filepath.Join(env.dir, "file"), nil, 0644)
+
+	for _, dir := range []string{filepath.Join(env.dir, "missing"), filepath.Join(env.dir, "file")} {
+		withProjectDir(t, dir)
+		err := enterProjectRoot(&cobra.Command{}, nil)
+		if err == nil || !strings.Contains(err.Error(), "invalid --project-dir") {
+			t.Errorf("expected error for %s, got %v", dir, err)
+		}
+	}
+}
+
+// setQuiet enables --quiet for the duration of the test
+func setQuiet(t *testing.T) {
+	t.Helper()
+	quiet = true
+	t.Cleanup(func() { quiet = false })
+}
+
+func TestQuiet_SuppressesProgress(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+	setQuiet(t)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runDown(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "" {
+		t.Errorf("expected no output with --quiet, got: %s", out)
+	}
+	if !env.mock.HasCall("stop", "dev1") {
+		t.Error("expected container to be stopped")
+	}
+}
+
+func TestQuiet_ProgressPrintedByDefault(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	out := env.captureStdout(func() {
+		if err := runDown(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Container 'dev1' stopped") {
+		t.Errorf("expected progress output, got: %s", out)
+	}
+}
+
+func TestQuiet_KeepsWarnings(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`defaults:
+  ports: [8000]
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ports: [3000]
+`)
+	setQuiet(t)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runPortAdd(nil, []string{"dev1", "8000"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Warning: port 8000 is already in the project defaults") {
+		t.Errorf("expected warning with --quiet, got: %s", out)
+	}
+	if strings.Contains(out, "added to") {
+		t.Errorf("expected success message to be suppressed, got: %s", out)
+	}
+}
+
+func TestQuiet_KeepsRequestedOutput(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setListAllContainers("dev1,RUNNING,10.10.10.100 (eth0)")
+	setQuiet(t)
+
+	out := env.captureStdout(func() {
+		if err := runList(nil, []string{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(out, "dev1") {
+		t.Errorf("expected list output with --quiet, got: %s", out)
+	}
+}
+
+func TestQuiet_ErrorsStillReturned(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerNotExists("dev1")
+	setQuiet(t)
+
+	if err := runDown(nil, []string{"dev1"}); err == nil {
+		t.Fatal("expected error with --quiet")
+	}
+}
+
+func TestInfoOutput(t *testing.T) {
+	if infoOutput() != os.Stdout {
+		t.Error("expected progress to go to stdout by default")
+	}
+	setQuiet(t)
+	if infoOutput() != io.Discard {
+		t.Error("expected progress to be discarded with --quiet")
+	}
+}
+
+func TestConfirmPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers string
+		want    bool
+	}{
+		{"y", "y\n", true},
+		{"yes", "yes\n", true},
+		{"uppercase", "YES\n", true},
+		{"padded", "  y  \n", true},
+		{"no newline", "y", true},
+		{"n", "n\n", false},
+		{"empty line", "\n", false},
+		{"other", "sure\n", false},
+		{"no input", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestEnv(t)
+			env.setPromptInput(tt.answers)
+
+			var got bool
+			out := env.captureStdout(func() {
+				got = confirmPrompt("Continue?")
+			})
+			if got != tt.want {
+				t.Errorf("confirmPrompt with %q = %v, want %v", tt.answers, got, tt.want)
+			}
+			if out != "Continue? [y/N]: " {
+				t.Errorf("unexpected prompt %q", out)
+			}
+		})
+	}
+}
+
+func TestConfirmPrompt_AnswersInOrder(t *testing.T) {
+	env := setupTestEnv(t)
+	env.setPromptInput("y\nn\n")
+
+	env.captureStdout(func() {
+		if !confirmPrompt("First?") {
+			t.Error("expected first prompt to be confirmed")
+		}
+		if confirmPrompt("Second?") {
+			t.Error("expected second prompt to be declined")
+		}
+	})
+}
+
+func TestExitCodeError(t *testing.T) {
+	err := &exitCodeError{code: 3}
+	if err.Error() != "exit status 3" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+}
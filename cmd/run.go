@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:     "run <name> -- <command> [args...]",
+	Aliases: []string{"exec"},
+	Short:   "Run a command inside a container",
+	Long: `Run a single command inside a container without opening a shell.
+
+By default, runs as the user defined in containers.yaml (defaults to 'dev')
+through a login shell, so PATH and groups match an ssh session.
+Use -u to override with a different user.
+
+stdin, stdout and stderr are passed through, and the command's exit status
+is returned as lxc-dev-manager's exit status, so run can be used in scripts
+like the command itself. exec is an alias of run.
+
+Example:
+  lxc-dev-manager run dev1 -- npm install
+  lxc-dev-manager run dev1 -u root -- apt-get update
+  echo "SELECT 1" | lxc-dev-manager exec dev1 -- psql`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runRun,
+}
+
+var runUser string
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().StringVarP(&runUser, "user", "u", "", "Override user (e.g., -u root)")
+}
+
+// buildRunArgs constructs the lxc exec arguments for running a command
+func buildRunArgs(lxcName, user string, command []string) []string {
+	args := []string{"exec", lxc.InstanceRef(lxcName), "--"}
+
+	if user != "" {
+		// Use su -l like ssh, so the command gets a login environment
+		args = append(args, "su", "-l", user, "-c", lxc.ShellJoin(command))
+	} else {
+		args = append(args, command...)
+	}
+
+	return args
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	command := args[1:]
+
+	cfg, lxcName, err := requireRunningContainer(name)
+	if err != nil {
+		return err
+	}
+
+	// Determine which user to use
+	user := runUser
+	if !cmd.Flags().Changed("user") {
+		// No -u flag provided, use config user
+		user = cfg.GetUser(name).Name
+	}
+
+	return execLXC(buildRunArgs(lxcName, user, command))
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRun_ContainerNotRunning(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+
+	err := runRun(runCmd, []string{"dev1", "ls"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "not running") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRun_ContainerNotFound(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	err := runRun(runCmd, []string{"dev1", "ls"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// setRunUser sets -u on runCmd as if it was passed on the command line
+func setRunUser(t *testing.T, user string) {
+	t.Helper()
+	if err := runCmd.Flags().Set("user", user); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		runUser = ""
+		runCmd.Flags().Lookup("user").Changed = false
+	})
+}
+
+func TestRun_DefaultsToConfigUser(t *testing.T) {
+	env := setupTestEnv(t)
+	got := stubExecLXC(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	if err := runRun(runCmd, []string{"dev1", "npm", "test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "exec dev1 -- su -l dev -c npm test"
+	if strings.Join(*got, " ") != want {
+		t.Errorf("expected %q, got %q", want, strings.Join(*got, " "))
+	}
+}
+
+func TestRun_UserFlag(t *testing.T) {
+	env := setupTestEnv(t)
+	got := stubExecLXC(t)
+	setRunUser(t, "root")
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", true)
+
+	if err := runRun(runCmd, []string{"dev1", "apt-get", "update"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "exec dev1 -- su -l root -c apt-get update"
+	if strings.Join(*got, " ") != want {
+		t.Errorf("expected %q, got %q", want, strings.Join(*got, " "))
+	}
+}
+
+func TestRun_ExecAlias(t *testing.T) {
+	found, _, err := rootCmd.Find([]string{"exec", "dev1", "--", "ls"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != runCmd {
+		t.Errorf("expected exec to resolve to run, got %s", found.Name())
+	}
+}
+
+func TestBuildRunArgs_WithUser(t *testing.T) {
+	args := buildRunArgs("mycontainer", "dev", []string{"npm", "install"})
+
+	expected := []string{"exec", "mycontainer", "--", "su", "-l", "dev", "-c", "npm install"}
+	if len(args) != len(expected) {
+		t.Fatalf("expected %d args, got %d: %v", len(expected), len(args), args)
+	}
+	for i, arg := range args {
+		if arg != expected[i] {
+			t.Errorf("arg[%d]: expected %q, got %q", i, expected[i], arg)
+		}
+	}
+}
+
+func TestBuildRunArgs_WithoutUser(t *testing.T) {
+	args := buildRunArgs("mycontainer", "", []string{"ls", "-la", "/tmp"})
+
+	expected := []string{"exec", "mycontainer", "--", "ls", "-la", "/tmp"}
+	if len(args) != len(expected) {
+		t.Fatalf("expected %d args, got %d: %v", len(expected), len(args), args)
+	}
+	for i, arg := range args {
+		if arg != expected[i] {
+			t.Errorf("arg[%d]: expected %q, got %q", i, expected[i], arg)
+		}
+	}
+}
+
+func TestBuildRunArgs_QuotesArguments(t *testing.T) {
+	args := buildRunArgs("mycontainer", "dev", []string{"echo", "hello world", "it's", ""})
+
+	expected := `echo 'hello world' 'it'\''s' ''`
+	if got := args[len(args)-1]; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"syscall"
 
+	"lxc-dev-manager/internal/lxc"
+
 	"github.com/spf13/cobra"
 )
 
@@ -35,7 +37,7 @@ func init() {
 
 // buildSSHArgs constructs the lxc exec arguments for SSH
 func buildSSHArgs(lxcName, user string) []string {
-	args := []string{"exec", lxcName, "--"}
+	args := []string{"exec", lxc.InstanceRef(lxcName), "--"}
 
 	if user != "" {
 		// Use su -l to get a proper login shell with all supplementary groups loaded
@@ -64,15 +66,16 @@ func runSSH(cmd *cobra.Command, args []string) error {
 		user = cfg.GetUser(name).Name
 	}
 
-	// Build lxc exec command
-	lxcArgs := buildSSHArgs(lxcName, user)
-
 	// Replace current process with lxc exec (interactive shell)
+	return execLXC(buildSSHArgs(lxcName, user))
+}
+
+// execLXC replaces the current process with lxc, so TTY handling and exit
+// status pass straight through. Tests swap it out to record the arguments.
+var execLXC = func(lxcArgs []string) error {
 	lxcPath, err := exec.LookPath("lxc")
 	if err != nil {
 		return fmt.Errorf("lxc command not found: %w", err)
 	}
-
-	// Use syscall.Exec to replace the process for proper TTY handling
 	return syscall.Exec(lxcPath, append([]string{"lxc"}, lxcArgs...), os.Environ())
 }
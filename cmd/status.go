@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"lxc-dev-manager/internal/lxc"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status <name>",
+	Short: "Show status of a single container",
+	Long: `Show the status of a single container: state, IP, image, ports,
+user, snapshot count, and (when running) PID and memory usage.
+
+Containers that are in the project config but missing from LXC are
+reported as NOT FOUND.
+
+Example:
+  lxc-dev-manager status dev1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	if !cfg.HasContainer(name) {
+		return fmt.Errorf("container '%s' not found in project config", name)
+	}
+
+	lxcName := cfg.GetLXCName(name)
+
+	info := lxc.ContainerInfo{Name: lxcName, Status: "NOT FOUND"}
+	if lxc.Exists(lxcName) {
+		details, err := lxc.GetInfo(lxcName)
+		if err != nil {
+			return err
+		}
+		info = details.ContainerInfo
+	}
+
+	fmt.Printf("Container: %s (LXC: %s)\n", name, lxcName)
+	fmt.Printf("  Status: %s\n", info.Status)
+	fmt.Printf("  IP: %s\n", orDash(info.IP))
+	fmt.Printf("  Image: %s\n", cfg.Containers[name].Image)
+	fmt.Printf("  Ports: %s\n", formatPorts(cfg.GetPortMappings(name)))
+	fmt.Printf("  User: %s\n", cfg.GetUser(name).Name)
+	fmt.Printf("  Snapshots: %d\n", len(cfg.GetSnapshots(name)))
+	if info.PID > 0 {
+		fmt.Printf("  PID: %d\n", info.PID)
+	}
+	if info.Memory != "" {
+		fmt.Printf("  Memory: %s\n", info.Memory)
+	}
+
+	return nil
+}
+
+// orDash returns s, or "-" when s is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatus_Running(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ports: [3000]
+    snapshots:
+      initial-state:
+        created_at: "2024-01-15T10:30:00Z"
+      checkpoint:
+        created_at: "2024-01-16T10:30:00Z"
+`)
+	env.mock.SetOutput("info test-dev1", `Name: test-dev1
+Status: RUNNING
+PID: 4242
+
+Resources:
+  Memory usage:
+    Memory (current): 128.00MiB
+  Network usage:
+    eth0:
+      IP addresses:
+        inet:  10.10.10.5/24 (global)
+`)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runStatus(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"Status: RUNNING",
+		"IP: 10.10.10.5",
+		"Image: ubuntu:24.04",
+		"Ports: 3000",
+		"User: dev",
+		"Snapshots: 2",
+		"PID: 4242",
+		"Memory: 128.00MiB",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatus_NotFoundInLXC(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerNotExists("test-dev1")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runStatus(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Status: NOT FOUND") {
+		t.Errorf("expected NOT FOUND status:\n%s", out)
+	}
+	if strings.Contains(out, "PID") {
+		t.Errorf("should not show PID for missing container:\n%s", out)
+	}
+}
+
+func TestStatus_NotInConfig(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeMinimalConfig()
+
+	err := runStatus(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "not found in project config") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
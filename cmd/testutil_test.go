@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"lxc-dev-manager/internal/lxc"
@@ -34,6 +37,20 @@ func setupTestEnv(t *testing.T) *testEnv {
 	mock := lxc.NewMockExecutor()
 	lxc.SetExecutor(mock)
 
+	// Don't wait for an IP in tests of stopped containers
+	oldIPPollInterval := lxc.IPPollInterval
+	oldUpTimeout := upTimeout
+	oldRestartIPTimeout := restartIPTimeout
+	oldLaunchRetryBackoff := launchRetryBackoff
+	launchRetryBackoff = 0
+	lxc.IPPollInterval = 0
+	upTimeout = 0
+	restartIPTimeout = 0
+
+	// Prompts are declined unless a test answers them with setPromptInput
+	oldPromptInput := promptInput
+	promptInput = bufio.NewReader(strings.NewReader(""))
+
 	env := &testEnv{
 		t:      t,
 		dir:    dir,
@@ -46,6 +63,13 @@ func setupTestEnv(t *testing.T) *testEnv {
 	t.Cleanup(func() {
 		os.Chdir(oldDir)
 		lxc.ResetExecutor()
+		lxc.SetRemote("")
+		lxc.IPPollInterval = oldIPPollInterval
+		upTimeout = oldUpTimeout
+		restartIPTimeout = oldRestartIPTimeout
+		launchRetryBackoff = oldLaunchRetryBackoff
+		workDir = ""
+		promptInput = oldPromptInput
 	})
 
 	return env
@@ -78,6 +102,12 @@ func (e *testEnv) configExists() bool {
 	return err == nil
 }
 
+// setPromptInput answers the next confirmation prompts in order, one per
+// line, e.g. "y\n" or "y\nn\n"
+func (e *testEnv) setPromptInput(answers string) {
+	promptInput = bufio.NewReader(strings.NewReader(answers))
+}
+
 // setContainerExists mocks a container as existing
 func (e *testEnv) setContainerExists(name string, running bool) {
 	e.mock.SetOutput("info "+name, "Name: "+name)
@@ -128,3 +158,41 @@ containers:
     image: ` + image + `
 `)
 }
+
+// captureStdout runs fn and returns everything it wrote to os.Stdout
+func (e *testEnv) captureStdout(fn func()) string {
+	e.t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		e.t.Fatal(err)
+	}
+
+	old := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+// stubExecLXC replaces execLXC so commands that hand over to an interactive
+// lxc process return instead; the returned slice holds the lxc arguments
+func stubExecLXC(t *testing.T) *[]string {
+	t.Helper()
+	var got []string
+	old := execLXC
+	execLXC = func(lxcArgs []string) error {
+		got = lxcArgs
+		return nil
+	}
+	t.Cleanup(func() { execLXC = old })
+	return &got
+}
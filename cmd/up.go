@@ -4,30 +4,168 @@ import (
 	"fmt"
 	"time"
 
+	"lxc-dev-manager/internal/config"
 	"lxc-dev-manager/internal/lxc"
 
 	"github.com/spf13/cobra"
 )
 
 var upCmd = &cobra.Command{
-	Use:   "up <name>",
+	Use:   "up [name]",
 	Short: "Start a container",
 	Long: `Start a stopped container.
 
+Use --all to start every container in the project in parallel, or pass a
+pattern ("dev*", "*") to start only the matching containers. Quote patterns
+so the shell does not expand them.
+
+With --all, containers listing others in depends_on are started after them;
+containers at the same level still start in parallel.
+
+After starting, up waits up to --timeout for the container to get an IP
+address. If none is assigned in time, a warning is printed but the command
+still succeeds.
+
+With --wait, up first waits until the container is ready, using its
+ready_strategy (cloud-init by default), for up to --wait-timeout. Containers
+with an explicit ready_strategy are always waited for.
+
+With --attach, up opens a shell in the container once it has started, just
+like the ssh command. --attach has no short form, since -a is --all.
+
 Example:
-  lxc-dev-manager up dev1`,
-	Args: cobra.ExactArgs(1),
+  lxc-dev-manager up dev1
+  lxc-dev-manager up dev1 --wait
+  lxc-dev-manager up dev1 --attach
+  lxc-dev-manager up --all
+  lxc-dev-manager up "dev*"
+  lxc-dev-manager up --all --concurrency 8`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runUp,
 }
 
+var (
+	upAll         bool
+	upAttach      bool
+	upConcurrency int
+	upTimeout     time.Duration
+	upWait        bool
+	upWaitTimeout time.Duration
+)
+
 func init() {
 	rootCmd.AddCommand(upCmd)
+	upCmd.Flags().BoolVarP(&upAll, "all", "a", false, "Start all containers in the project")
+	upCmd.Flags().BoolVar(&upAttach, "attach", false, "Open a shell in the container once it has started")
+	upCmd.Flags().DurationVar(&upTimeout, "timeout", 15*time.Second, "How long to wait for the container to get an IP address")
+	upCmd.Flags().BoolVar(&upWait, "wait", false, "Wait until the container is ready (cloud-init or its ready_strategy)")
+	upCmd.Flags().DurationVar(&upWaitTimeout, "wait-timeout", 0, "How long to wait for the container to be ready (default: wait_timeout, or 60s)")
+	upCmd.Flags().IntVar(&upConcurrency, "concurrency", defaultConcurrency, "Maximum number of containers to start at once (with --all or a pattern)")
 }
 
 func runUp(cmd *cobra.Command, args []string) error {
-	name := args[0]
+	if upAttach && (upAll || (len(args) == 1 && isContainerPattern(args[0]))) {
+		return fmt.Errorf("--attach needs a single container name")
+	}
+	if upAll {
+		if len(args) > 0 {
+			return fmt.Errorf("cannot specify a container name with --all")
+		}
+		return runUpAll()
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("requires a container name (or --all)")
+	}
+	if isContainerPattern(args[0]) {
+		return runUpMatching(args[0])
+	}
+
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+	if err := startContainer(cfg, args[0]); err != nil {
+		return err
+	}
+	if upAttach {
+		return attachShell(cfg, args[0])
+	}
+	return nil
+}
+
+// attachShell replaces the process with a login shell in a started
+// container, as the ssh command does
+func attachShell(cfg *config.Config, name string) error {
+	return execLXC(buildSSHArgs(cfg.GetLXCName(name), cfg.GetUser(name).Name))
+}
+
+// runUpMatching starts every container whose name matches pattern, in parallel
+func runUpMatching(pattern string) error {
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	names := matchContainers(cfg, pattern)
+	if len(names) == 0 {
+		return fmt.Errorf("no containers match pattern %q", pattern)
+	}
+
+	printInfo("Starting %d container(s): %s\n", len(names), joinNames(names))
+	return runForEach(names, upConcurrency, startEach(cfg))
+}
 
-	_, lxcName, err := requireContainer(name)
+// runUpAll starts every container in the project. Containers are started
+// in depends_on order; those at the same level start in parallel.
+func runUpAll() error {
+	cfg, err := requireProject()
+	if err != nil {
+		return err
+	}
+
+	names := containerNames(cfg)
+	if len(names) == 0 {
+		printInfo("No containers defined in config\n")
+		return nil
+	}
+
+	levels, err := cfg.StartOrder(names)
+	if err != nil {
+		return err
+	}
+
+	printInfo("Starting %d container(s): %s\n", len(names), joinNames(names))
+	if len(levels) == 1 {
+		return runForEach(names, upConcurrency, startEach(cfg))
+	}
+
+	for i, level := range levels {
+		printInfo("\nStep %d/%d: %s\n", i+1, len(levels), joinNames(level))
+		if err := runForEach(level, upConcurrency, startEach(cfg)); err != nil {
+			var skipped []string
+			for _, rest := range levels[i+1:] {
+				skipped = append(skipped, rest...)
+			}
+			if len(skipped) > 0 {
+				fmt.Printf("Not started: %s\n", joinNames(skipped))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// startEach returns a runForEach worker that starts containers from cfg,
+// so that parallel starts share one config snapshot
+func startEach(cfg *config.Config) func(name string) error {
+	return func(name string) error {
+		return startContainer(cfg, name)
+	}
+}
+
+// startContainer starts a single container and prints its IP
+func startContainer(cfg *config.Config, name string) error {
+	lxcName, err := containerLXCName(cfg, name)
 	if err != nil {
 		return err
 	}
@@ -39,31 +177,60 @@ func runUp(cmd *cobra.Command, args []string) error {
 	}
 
 	if status == "RUNNING" {
-		fmt.Printf("Container '%s' is already running\n", name)
+		printInfo("Container '%s' is already running\n", name)
 		ip, _ := lxc.GetIP(lxcName)
 		if ip != "" {
-			fmt.Printf("  IP: %s\n", ip)
+			printInfo("  IP: %s\n", ip)
 		}
 		return nil
 	}
 
-	// Start container
-	fmt.Printf("Starting container '%s'...\n", name)
+	// Apply resource limits from config if they changed
+	if err := applyLimits(lxcName, cfg.GetLimits(name)); err != nil {
+		return err
+	}
+
+	// LXD restores the memory state saved by 'down --stateful' on start
+	if saved, _ := lxc.HasSavedState(lxcName); saved {
+		printInfo("Resuming container '%s' from its saved state...\n", name)
+	} else {
+		printInfo("Starting container '%s'...\n", name)
+	}
 	if err := lxc.Start(lxcName); err != nil {
 		return err
 	}
 
-	// Wait a moment for network
-	time.Sleep(2 * time.Second)
+	// Wait for an explicitly configured ready check, or cloud-init with --wait (Ctrl+C aborts the wait)
+	if c := cfg.Containers[name]; c.ReadyStrategy != "" || upWait {
+		strategy := lxc.ReadyStrategy(c.ReadyStrategy)
+		if strategy == "" {
+			strategy = lxc.ReadyCloudInit
+		}
+		printInfo("Waiting for '%s' to be ready (%s)...\n", name, strategy)
+		ctx, stop := interruptContext()
+		err := lxc.WaitForReady(ctx, lxcName, strategy, c.ReadyCommand, resolveWaitTimeout(cfg, name, upWaitTimeout))
+		stop()
+		if err != nil {
+			return fmt.Errorf("container '%s' started but is not ready: %w", name, err)
+		}
+	}
 
-	// Get IP
-	ip, err := lxc.GetIP(lxcName)
+	// Networking takes a moment to come up after start (DHCP can be slow)
+	ip, err := lxc.WaitForIP(lxcName, upTimeout)
 	if err != nil {
+		fmt.Printf("Warning: container '%s' has no IP address after %s\n", name, upTimeout)
 		ip = "(pending)"
 	}
 
-	fmt.Printf("Container '%s' started\n", name)
-	fmt.Printf("  IP: %s\n", ip)
+	// Re-apply environment in case it changed while stopped
+	if env := cfg.GetEnv(name); len(env) > 0 {
+		if err := lxc.SetEnv(lxcName, env); err != nil {
+			fmt.Printf("Warning: failed to write environment: %v\n", err)
+		}
+	}
+
+	printInfo("Container '%s' started\n", name)
+	printInfo("  IP: %s\n", ip)
 
 	return nil
 }
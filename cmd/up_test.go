@@ -3,6 +3,9 @@ package cmd
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"lxc-dev-manager/internal/lxc"
 )
 
 func TestUp_Success(t *testing.T) {
@@ -22,6 +25,28 @@ func TestUp_Success(t *testing.T) {
 	}
 }
 
+func TestUp_ResumesSavedState(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+	env.mock.SetOutput("query /1.0/instances/dev1?recursion=1", `{"name": "dev1", "status": "Stopped", "stateful": true}`)
+	env.mock.SetOutput("start dev1", "")
+	env.mock.SetOutput("list dev1 -c4 -f csv", "10.10.10.100 (eth0)")
+
+	out := env.captureStdout(func() {
+		if err := runUp(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "Resuming container 'dev1' from its saved state") {
+		t.Errorf("expected resume message, got:\n%s", out)
+	}
+	if !env.mock.HasCall("start", "dev1") {
+		t.Error("expected start command")
+	}
+}
+
 func TestUp_AlreadyRunning(t *testing.T) {
 	env := setupTestEnv(t)
 	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
@@ -78,3 +103,602 @@ func TestUp_GetStatusFails(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+// withUpAll enables --all for the duration of a test
+func withUpAll(t *testing.T) {
+	t.Helper()
+	upAll = true
+	upConcurrency = defaultConcurrency
+	t.Cleanup(func() { upAll = false })
+}
+
+func TestUp_All(t *testing.T) {
+	env := setupTestEnv(t)
+	withUpAll(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", false)
+	env.setContainerExists("dev2", true)
+	env.mock.SetOutput("start dev1", "")
+
+	err := runUp(nil, []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("start", "dev1") {
+		t.Error("expected start command for dev1")
+	}
+	if env.mock.HasCall("start", "dev2") {
+		t.Error("should not start already running dev2")
+	}
+}
+
+func TestUp_AllPartialFailure(t *testing.T) {
+	env := setupTestEnv(t)
+	withUpAll(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", true)
+	env.setContainerNotExists("dev2")
+
+	err := runUp(nil, []string{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "failed for 1 container(s)") || !strings.Contains(err.Error(), "dev2") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestUp_AllUsesOneConfigSnapshot checks that --all works from the config
+// loaded at the start. The parallel paths are also run under -race:
+//
+//	go test -race -run 'TestUp_All|TestDown_All' ./cmd
+func TestUp_AllUsesOneConfigSnapshot(t *testing.T) {
+	env := setupTestEnv(t)
+	withUpAll(t)
+	upConcurrency = 1
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", false)
+	env.setContainerExists("dev2", false)
+	env.mock.SetOutput("start", "")
+	env.mock.SetCallback("start dev1", func([]string) {
+		env.writeMinimalConfig()
+	})
+
+	if err := runUp(nil, []string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCall("start", "dev2") {
+		t.Errorf("expected dev2 to start from the initial config, got calls: %v", env.mock.Calls)
+	}
+}
+
+// callIndex returns the position of the first call with the given args, or -1
+func callIndex(calls []lxc.MockCall, args ...string) int {
+	want := strings.Join(args, " ")
+	for i, c := range calls {
+		if strings.Join(c.Args, " ") == want {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestUp_AllDependsOnOrder(t *testing.T) {
+	env := setupTestEnv(t)
+	withUpAll(t)
+	env.writeConfig(`project: ""
+containers:
+  app:
+    image: ubuntu:24.04
+    depends_on: [db, cache]
+  cache:
+    image: ubuntu:24.04
+  db:
+    image: ubuntu:24.04
+`)
+	for _, name := range []string{"app", "cache", "db"} {
+		env.setContainerExists(name, false)
+		env.mock.SetOutput("start "+name, "")
+	}
+
+	out := env.captureStdout(func() {
+		if err := runUp(nil, []string{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	calls := env.mock.Calls
+	app := callIndex(calls, "start", "app")
+	for _, dep := range []string{"db", "cache"} {
+		i := callIndex(calls, "start", dep)
+		if i < 0 || app < 0 || i > app {
+			t.Errorf("expected %s to start before app (calls: %v)", dep, calls)
+		}
+	}
+	if !strings.Contains(out, "Step 1/2: cache, db") || !strings.Contains(out, "Step 2/2: app") {
+		t.Errorf("expected start levels in output:\n%s", out)
+	}
+}
+
+func TestUp_AllDependencyFailureSkipsDependents(t *testing.T) {
+	env := setupTestEnv(t)
+	withUpAll(t)
+	env.writeConfig(`project: ""
+containers:
+  app:
+    image: ubuntu:24.04
+    depends_on: [db]
+  db:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("app", false)
+	env.setContainerExists("db", false)
+	env.mock.SetError("start db", "boom")
+
+	out := env.captureStdout(func() {
+		if err := runUp(nil, []string{}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	if env.mock.HasCall("start", "app") {
+		t.Error("app should not start when db failed")
+	}
+	if !strings.Contains(out, "Not started: app") {
+		t.Errorf("expected skipped containers in output:\n%s", out)
+	}
+}
+
+func TestUp_AllDependencyCycle(t *testing.T) {
+	env := setupTestEnv(t)
+	withUpAll(t)
+	env.writeConfig(`project: ""
+containers:
+  a:
+    image: ubuntu:24.04
+    depends_on: [b]
+  b:
+    image: ubuntu:24.04
+    depends_on: [a]
+  c:
+    image: ubuntu:24.04
+`)
+
+	err := runUp(nil, []string{})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle between containers: a, b") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if env.mock.HasCallPrefix("start") {
+		t.Error("nothing should start when dependencies form a cycle")
+	}
+}
+
+func TestUp_AllWithName(t *testing.T) {
+	env := setupTestEnv(t)
+	withUpAll(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runUp(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "--all") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUp_NoName(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runUp(nil, []string{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUp_WithRemote(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: web
+defaults:
+  remote: lab
+containers:
+  dev1:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("lab:web-dev1", false)
+	env.mock.SetOutput("start lab:web-dev1", "")
+
+	err := runUp(nil, []string{"dev1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("start", "lab:web-dev1") {
+		t.Error("expected start on remote as remote:project-name")
+	}
+}
+
+func TestUp_AppliesEnv(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    env:
+      TZ: UTC
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("start test-dev1", "")
+
+	err := runUp(nil, []string{"dev1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCallPrefix("exec", "test-dev1", "--", "bash", "-c") {
+		t.Error("expected environment to be written on start")
+	}
+}
+
+func TestUp_AppliesMissingLimits(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    limits:
+      cpu: "2"
+      memory: 2GiB
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("config get test-dev1 limits.cpu", "2")
+	env.mock.SetOutput("config get test-dev1 limits.memory", "")
+	env.mock.SetOutput("start test-dev1", "")
+
+	err := runUp(nil, []string{"dev1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if env.mock.HasCallPrefix("config", "set", "test-dev1", "limits.cpu") {
+		t.Error("should not reset an unchanged CPU limit")
+	}
+	if !env.mock.HasCall("config", "set", "test-dev1", "limits.memory", "2GiB") {
+		t.Error("expected memory limit to be applied")
+	}
+}
+
+func TestUp_LimitFails(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    limits:
+      cpu: "4"
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetError("config set test-dev1 limits.cpu 4", "not enough CPUs")
+
+	err := runUp(nil, []string{"dev1"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if env.mock.HasCall("start", "test-dev1") {
+		t.Error("should not start when limits cannot be applied")
+	}
+}
+
+func TestUp_WaitsForReadyStrategy(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ready_strategy: custom
+    ready_command: test -f /tmp/ready
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("start test-dev1", "")
+	env.mock.SetOutput("exec test-dev1 -- sh -c test -f /tmp/ready", "")
+
+	err := runUp(nil, []string{"dev1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("exec", "test-dev1", "--", "sh", "-c", "test -f /tmp/ready") {
+		t.Error("expected ready command to be run")
+	}
+}
+
+func TestUp_ReadyTimeout(t *testing.T) {
+	env := setupTestEnv(t)
+	oldTimeout := upWaitTimeout
+	upWaitTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { upWaitTimeout = oldTimeout })
+
+	env.writeConfig(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ready_strategy: systemd
+`)
+	env.setContainerExists("test-dev1", false)
+	env.mock.SetOutput("start test-dev1", "")
+	env.mock.SetOutput("exec test-dev1 -- systemctl is-system-running", "starting")
+
+	err := runUp(nil, []string{"dev1"})
+	if err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Errorf("expected not ready error, got: %v", err)
+	}
+}
+
+func TestUp_NoReadyStrategySkipsWait(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+	env.mock.SetOutput("start dev1", "")
+
+	if err := runUp(nil, []string{"dev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if env.mock.HasCallPrefix("exec", "dev1", "--", "cloud-init") {
+		t.Error("should not wait for cloud-init without an explicit ready_strategy")
+	}
+}
+
+func TestUp_Pattern(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+  db:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", false)
+	env.setContainerExists("dev2", false)
+	env.setContainerExists("db", false)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runUp(nil, []string{"dev*"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !env.mock.HasCall("start", "dev1") || !env.mock.HasCall("start", "dev2") {
+		t.Error("expected matching containers to be started")
+	}
+	if env.mock.HasCall("start", "db") {
+		t.Error("should not start non-matching container")
+	}
+	if !strings.Contains(out, "Starting 2 container(s): dev1, dev2") {
+		t.Errorf("expected matched containers to be listed, got:\n%s", out)
+	}
+	if !strings.Contains(out, "✓ dev1 done") || !strings.Contains(out, "✓ dev2 done") {
+		t.Errorf("expected per-container summary, got:\n%s", out)
+	}
+}
+
+func TestUp_PatternPartialFailure(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfig(`project: ""
+containers:
+  dev1:
+    image: ubuntu:24.04
+  dev2:
+    image: ubuntu:24.04
+`)
+	env.setContainerExists("dev1", false)
+	env.setContainerNotExists("dev2")
+
+	err := runUp(nil, []string{"*"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "failed for 1 container(s)") || !strings.Contains(err.Error(), "dev2") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !env.mock.HasCall("start", "dev1") {
+		t.Error("expected dev1 to be started despite dev2 failure")
+	}
+}
+
+func TestUp_PatternNoMatch(t *testing.T) {
+	env := setupTestEnv(t)
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+
+	err := runUp(nil, []string{"web*"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), `no containers match pattern "web*"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUp_WaitsForIP(t *testing.T) {
+	env := setupTestEnv(t)
+	upTimeout = time.Second
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+	env.mock.SetOutputs("list dev1 -c4 -f csv", "", "", "10.10.10.7 (eth0)")
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runUp(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "IP: 10.10.10.7") {
+		t.Errorf("expected IP once assigned, got:\n%s", out)
+	}
+	if strings.Contains(out, "Warning") {
+		t.Errorf("unexpected warning:\n%s", out)
+	}
+}
+
+func TestUp_TimeoutWithoutIPWarns(t *testing.T) {
+	env := setupTestEnv(t)
+	upTimeout = 10 * time.Millisecond
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+
+	var err error
+	out := env.captureStdout(func() {
+		err = runUp(nil, []string{"dev1"})
+	})
+	if err != nil {
+		t.Fatalf("missing IP should not be an error: %v", err)
+	}
+	if !strings.Contains(out, "Warning: container 'dev1' has no IP address after 10ms") {
+		t.Errorf("expected timeout warning, got:\n%s", out)
+	}
+	if !strings.Contains(out, "IP: (pending)") {
+		t.Errorf("expected pending IP, got:\n%s", out)
+	}
+}
+
+func TestUp_WaitBlocksUntilCloudInitDone(t *testing.T) {
+	env := setupTestEnv(t)
+	upWait = true
+	t.Cleanup(func() { upWait = false })
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+	env.mock.SetOutput("start dev1", "")
+	env.mock.SetOutputs("exec dev1 -- cloud-init status", "status: running", "status: done")
+
+	out := env.captureStdout(func() {
+		if err := runUp(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	checks := 0
+	for _, call := range env.mock.Calls {
+		if strings.Join(call.Args, " ") == "exec dev1 -- cloud-init status" {
+			checks++
+		}
+	}
+	if checks != 2 {
+		t.Errorf("expected up to poll cloud-init until done (2 checks), got %d", checks)
+	}
+	if !strings.Contains(out, "Waiting for 'dev1' to be ready (cloud-init)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestUp_WaitTimeout(t *testing.T) {
+	env := setupTestEnv(t)
+	upWait = true
+	oldTimeout := upWaitTimeout
+	upWaitTimeout = 10 * time.Millisecond
+	t.Cleanup(func() {
+		upWait = false
+		upWaitTimeout = oldTimeout
+	})
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+	env.mock.SetOutput("start dev1", "")
+	env.mock.SetOutput("exec dev1 -- cloud-init status", "status: running")
+
+	var err error
+	env.captureStdout(func() {
+		err = runUp(nil, []string{"dev1"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Errorf("expected not ready error, got: %v", err)
+	}
+}
+
+func TestUp_Attach(t *testing.T) {
+	env := setupTestEnv(t)
+	upAttach = true
+	t.Cleanup(func() { upAttach = false })
+	execArgs := stubExecLXC(t)
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+	env.mock.SetOutput("start dev1", "")
+	env.mock.SetOutput("list dev1 -c4 -f csv", "10.10.10.100 (eth0)")
+
+	env.captureStdout(func() {
+		if err := runUp(nil, []string{"dev1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !env.mock.HasCall("start", "dev1") {
+		t.Error("expected container to be started before attaching")
+	}
+	if got := strings.Join(*execArgs, " "); got != "exec dev1 -- su -l dev" {
+		t.Errorf("expected shell as configured user, got %q", got)
+	}
+}
+
+func TestUp_AttachStartFails(t *testing.T) {
+	env := setupTestEnv(t)
+	upAttach = true
+	t.Cleanup(func() { upAttach = false })
+	execArgs := stubExecLXC(t)
+
+	env.writeConfigWithContainer("dev1", "ubuntu:24.04")
+	env.setContainerExists("dev1", false)
+	env.mock.SetError("start dev1", "failed to start")
+
+	env.captureStdout(func() {
+		if err := runUp(nil, []string{"dev1"}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	if *execArgs != nil {
+		t.Errorf("should not attach when start fails, got %v", *execArgs)
+	}
+}
+
+func TestUp_AttachRequiresSingleContainer(t *testing.T) {
+	setupTestEnv(t)
+	upAttach = true
+	t.Cleanup(func() { upAttach = false })
+	stubExecLXC(t)
+
+	if err := runUp(nil, []string{"dev*"}); err == nil || !strings.Contains(err.Error(), "single container") {
+		t.Errorf("expected error for pattern, got %v", err)
+	}
+
+	upAll = true
+	t.Cleanup(func() { upAll = false })
+	if err := runUp(nil, nil); err == nil || !strings.Contains(err.Error(), "single container") {
+		t.Errorf("expected error for --all, got %v", err)
+	}
+}
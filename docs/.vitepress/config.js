@@ -33,7 +33,8 @@ export default defineConfig({
           { text: 'Project', link: '/reference/commands/project' },
           { text: 'Container', link: '/reference/commands/container' },
           { text: 'Snapshot', link: '/reference/commands/snapshot' },
-          { text: 'Image', link: '/reference/commands/image' }
+          { text: 'Image', link: '/reference/commands/image' },
+          { text: 'Config', link: '/reference/commands/config' }
         ]
       },
       {
@@ -458,6 +458,51 @@ func TestE2E_MvDirectory(t *testing.T) {
 	}
 }
 
+func TestE2E_MvDirectoryProgress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	dir := setupProject(t)
+	lxcName := lxcContainerName("dev")
+	defer func() {
+		runInDir(t, dir, "remove", "dev", "--force")
+	}()
+
+	_, err := runInDir(t, dir, "container", "create", "dev", testImage)
+	if err != nil {
+		t.Fatalf("container create failed: %v", err)
+	}
+
+	// A directory large enough for lxc to report progress
+	testDir := filepath.Join(dir, "bigdir")
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		name := filepath.Join(testDir, fmt.Sprintf("blob%d.bin", i))
+		if err := os.WriteFile(name, make([]byte, 8<<20), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	output, err := runInDir(t, dir, "mv", testDir, "dev:~/bigdir", "-y")
+	if err != nil {
+		t.Fatalf("mv failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "4 file(s), 32.0 MiB") {
+		t.Errorf("expected directory size summary, got:\n%s", output)
+	}
+
+	output, err = lxc(t, "exec", lxcName, "--", "sh", "-c", "cat /home/dev/bigdir/* | wc -c")
+	if err != nil {
+		t.Fatalf("reading copied files failed: %v\n%s", err, output)
+	}
+	if strings.TrimSpace(output) != "33554432" {
+		t.Errorf("expected 32 MiB copied, got %s", output)
+	}
+}
+
 // Helper to check if port is available
 func portAvailable(port int) bool {
 	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"lxc-dev-manager/internal/validation"
+)
+
+// FieldError is a configuration problem tied to a field path such as
+// "containers.dev1.ports"
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Check reports every problem in the configuration, where Validate stops at
+// the first. It also flags things Load tolerates: containers without an image
+// and snapshot timestamps that are not RFC3339.
+func (c *Config) Check() []FieldError {
+	return c.check(true)
+}
+
+// check runs the checks shared by Check and Validate; strict adds the ones
+// only Check reports
+func (c *Config) check(strict bool) []FieldError {
+	var errs []FieldError
+	add := func(field string, err error) {
+		if err != nil {
+			errs = append(errs, FieldError{Field: field, Message: err.Error()})
+		}
+	}
+
+	add("version", checkVersion(c.Version))
+
+	if c.Project != "" && !IsValidProjectName(c.Project) {
+		add("project", fmt.Errorf("invalid project name %q (allowed: letters, numbers, hyphens, underscores)", c.Project))
+	}
+
+	add("defaults.ports", validatePortList(c.Defaults.Ports))
+	if c.Defaults.Remote != "" {
+		add("defaults.remote", validation.ValidateRemoteName(c.Defaults.Remote))
+	}
+	if c.Defaults.Bind != "" {
+		add("defaults.bind", validation.ValidateBindAddr(c.Defaults.Bind))
+	}
+	if c.Defaults.Image != "" {
+		add("defaults.image", validation.ValidateImageName(c.Defaults.Image))
+	}
+	add("defaults.env", validation.ValidateEnv(c.Defaults.Env))
+	add("defaults.limits", validateLimits(c.Defaults.Limits))
+	add("defaults.setup", validateSetup(c.Defaults.Setup))
+	add("defaults.wait_timeout", validateWaitTimeout(c.Defaults.WaitTimeout))
+
+	names := make([]string, 0, len(c.Containers))
+	for name := range c.Containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		container := c.Containers[name]
+		prefix := "containers." + name
+
+		add(prefix, validation.ValidateFullContainerName(c.Project, name))
+		if strict && container.Image == "" {
+			add(prefix+".image", fmt.Errorf("image cannot be empty"))
+		}
+		add(prefix+".ports", validatePortList(container.Ports))
+		add(prefix+".env", validation.ValidateEnv(container.Env))
+		add(prefix+".limits", validateLimits(container.Limits))
+		add(prefix+".ready_strategy", validation.ValidateReadyStrategy(container.ReadyStrategy, container.ReadyCommand))
+		add(prefix+".setup", validateSetup(container.Setup))
+		add(prefix+".wait_timeout", validateWaitTimeout(container.WaitTimeout))
+		add(prefix+".mounts", ValidateMounts(container.Mounts))
+		if container.Profile != "" {
+			add(prefix+".profile", validation.ValidateProfileName(container.Profile))
+		}
+		add(prefix+".depends_on", c.validateDependsOn(name, container.DependsOn))
+
+		if !strict {
+			continue
+		}
+
+		snapNames := make([]string, 0, len(container.Snapshots))
+		for snapName := range container.Snapshots {
+			snapNames = append(snapNames, snapName)
+		}
+		sort.Strings(snapNames)
+
+		for _, snapName := range snapNames {
+			createdAt := container.Snapshots[snapName].CreatedAt
+			if _, err := time.Parse(time.RFC3339, createdAt); err != nil {
+				add(prefix+".snapshots."+snapName+".created_at",
+					fmt.Errorf("invalid timestamp %q (expected RFC3339, e.g. 2024-01-15T10:30:00Z)", createdAt))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateWaitTimeout rejects negative wait_timeout values; 0 means unset
+func validateWaitTimeout(seconds int) error {
+	if seconds < 0 {
+		return fmt.Errorf("invalid wait_timeout %d: must not be negative", seconds)
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -18,9 +20,13 @@ const (
 	ConfigFile  = "containers.yaml"
 	lockFile    = "containers.yaml.lock"
 	lockTimeout = 5 * time.Second
+
+	// lockTimeoutEnv overrides lockTimeout, in seconds (e.g. for slow NFS mounts)
+	lockTimeoutEnv = "LXC_DEV_LOCK_TIMEOUT_SECONDS"
 )
 
 type Config struct {
+	Version    int                  `yaml:"version"` // Schema version, see CurrentVersion
 	Project    string               `yaml:"project"`
 	Defaults   Defaults             `yaml:"defaults"`
 	Containers map[string]Container `yaml:"containers"`
@@ -31,24 +37,77 @@ type User struct {
 	Password string `yaml:"password,omitempty"`
 }
 
+// Limits are resource limits applied as limits.cpu and limits.memory
+type Limits struct {
+	CPU    string `yaml:"cpu,omitempty" json:"cpu,omitempty"`       // e.g. "2" or "0-3"
+	Memory string `yaml:"memory,omitempty" json:"memory,omitempty"` // e.g. "2GB" or "512MiB"
+}
+
 type Defaults struct {
-	Ports []int `yaml:"ports"`
-	User  User  `yaml:"user,omitempty"`
+	Ports       PortList          `yaml:"ports"`
+	User        User              `yaml:"user,omitempty"`
+	Remote      string            `yaml:"remote,omitempty"` // LXD remote to use (empty = local daemon)
+	Env         map[string]string `yaml:"env,omitempty"`
+	Limits      Limits            `yaml:"limits,omitempty"`
+	Bind        string            `yaml:"bind,omitempty"`         // Proxy listen address (empty = 127.0.0.1)
+	Autostart   bool              `yaml:"autostart,omitempty"`    // Start containers when the LXD host boots
+	Setup       []string          `yaml:"setup,omitempty"`        // Shell commands run as root at the end of 'container create'
+	WaitTimeout int               `yaml:"wait_timeout,omitempty"` // Seconds to wait for containers to be ready (0 = 60)
+	Nesting     *bool             `yaml:"nesting,omitempty"`      // Enable nesting (Docker support) on create; nil = true
+	Image       string            `yaml:"image,omitempty"`        // Image for 'container create' when none is given
 }
 
 type Snapshot struct {
-	Description string `yaml:"description,omitempty"`
-	CreatedAt   string `yaml:"created_at"`
+	Name        string `yaml:"-" json:"name"` // Filled in for output; the map key in config
+	Description string `yaml:"description,omitempty" json:"description"`
+	CreatedAt   string `yaml:"created_at" json:"created_at"`
 }
 
 type Container struct {
-	Image     string              `yaml:"image"`
-	Ports     []int               `yaml:"ports,omitempty"`
-	User      User                `yaml:"user,omitempty"`
-	Snapshots map[string]Snapshot `yaml:"snapshots,omitempty"`
+	Image         string              `yaml:"image"`
+	Ports         PortList            `yaml:"ports,omitempty"`
+	User          User                `yaml:"user,omitempty"`
+	Env           map[string]string   `yaml:"env,omitempty"`
+	Limits        Limits              `yaml:"limits,omitempty"`
+	ReadyStrategy string              `yaml:"ready_strategy,omitempty"` // cloud-init (default), systemd, or custom
+	ReadyCommand  string              `yaml:"ready_command,omitempty"`  // Health check for ready_strategy: custom
+	Mounts        []Mount             `yaml:"mounts,omitempty"`
+	Profile       string              `yaml:"profile,omitempty"`      // LXD profile applied at creation
+	DependsOn     []string            `yaml:"depends_on,omitempty"`   // Containers 'up --all' starts first
+	Autostart     *bool               `yaml:"autostart,omitempty"`    // Overrides defaults.autostart when set
+	Setup         []string            `yaml:"setup,omitempty"`        // Run after defaults.setup on create
+	WaitTimeout   int                 `yaml:"wait_timeout,omitempty"` // Overrides defaults.wait_timeout when set
+	SSHKey        string              `yaml:"ssh_key,omitempty"`      // Public key deployed by 'container create --ssh-key'
+	Snapshots     map[string]Snapshot `yaml:"snapshots,omitempty"`
 }
 
 func Load() (*Config, error) {
+	cfg, err := LoadUnvalidated()
+	if err != nil || cfg == nil {
+		return cfg, err
+	}
+
+	// Upgrade files written by older versions
+	migrated, err := cfg.migrate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if migrated {
+		cfg.saveMigrated()
+	}
+
+	return cfg, nil
+}
+
+// LoadUnvalidated parses the config file without running Validate.
+// Use Check on the result to report every problem at once.
+func LoadUnvalidated() (*Config, error) {
 	data, err := os.ReadFile(ConfigFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -58,48 +117,87 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	return Parse(data, ConfigFile)
+}
+
+// LoadFile reads and validates a config from any path, e.g. an exported
+// project template. Unlike Load, a missing file is an error.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := Parse(data, path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cfg.migrate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration in %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes config YAML without validating it; source names the input in errors
+func Parse(data []byte, source string) (*Config, error) {
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("invalid YAML in %s: %w", ConfigFile, err)
+		return nil, fmt.Errorf("invalid YAML in %s: %w", source, err)
 	}
 
 	if cfg.Containers == nil {
 		cfg.Containers = make(map[string]Container)
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
 	return &cfg, nil
 }
 
-// Validate checks all configuration values for correctness
+// Validate checks all configuration values for correctness and returns the
+// first problem Check would report, leaving out what Load tolerates
 func (c *Config) Validate() error {
-	// Validate project name
-	if c.Project != "" && !IsValidProjectName(c.Project) {
-		return fmt.Errorf("invalid project name %q", c.Project)
+	if errs := c.check(false); len(errs) > 0 {
+		return errs[0]
 	}
+	return nil
+}
 
-	// Validate default ports
-	if err := validation.ValidatePorts(c.Defaults.Ports); err != nil {
-		return fmt.Errorf("invalid default ports: %w", err)
+// validatePortList checks both sides of each mapping; local ports must be unique
+func validatePortList(ports PortList) error {
+	if err := validation.ValidatePorts(ports.LocalPorts()); err != nil {
+		return err
 	}
-
-	// Validate each container
-	for name, container := range c.Containers {
-		if err := validation.ValidateFullContainerName(c.Project, name); err != nil {
-			return fmt.Errorf("container '%s': %w", name, err)
+	for _, m := range ports {
+		if err := validation.ValidatePort(m.Remote); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if len(container.Ports) > 0 {
-			if err := validation.ValidatePorts(container.Ports); err != nil {
-				return fmt.Errorf("container '%s': %w", name, err)
-			}
+// validateSetup rejects blank setup commands, which are usually a YAML mistake
+func validateSetup(commands []string) error {
+	for i, command := range commands {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("setup command %d is empty", i+1)
 		}
 	}
+	return nil
+}
 
+func validateLimits(limits Limits) error {
+	if limits.CPU != "" {
+		if err := validation.ValidateCPULimit(limits.CPU); err != nil {
+			return err
+		}
+	}
+	if limits.Memory != "" {
+		if err := validation.ValidateMemoryLimit(limits.Memory); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -111,6 +209,16 @@ func (c *Config) GetLXCName(shortName string) string {
 	return c.Project + "-" + shortName
 }
 
+// GetAllLXCNames returns the LXC name of every container, sorted
+func (c *Config) GetAllLXCNames() []string {
+	names := make([]string, 0, len(c.Containers))
+	for name := range c.Containers {
+		names = append(names, c.GetLXCName(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetShortName extracts short name from LXC name by stripping project prefix
 func (c *Config) GetShortName(lxcName string) string {
 	if c.Project == "" {
@@ -144,11 +252,25 @@ func IsValidProjectName(name string) bool {
 }
 
 func (c *Config) Save() error {
-	data, err := yaml.Marshal(c)
+	return c.SaveAs(ConfigFile)
+}
+
+// SaveAs writes the config to path atomically, stamping the current
+// schema version on configs that have none
+func (c *Config) SaveAs(path string) error {
+	if c.Version == 0 {
+		c.Version = CurrentVersion
+	}
+	data, err := c.Marshal()
 	if err != nil {
 		return err
 	}
-	return atomicWriteFile(ConfigFile, data, 0644)
+	return atomicWriteFile(path, data, 0644)
+}
+
+// Marshal returns the config as YAML
+func (c *Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
 }
 
 // atomicWriteFile writes data to a file atomically using temp file + rename.
@@ -204,15 +326,42 @@ type ConfigLock struct {
 	file *os.File
 }
 
-// AcquireLock acquires an exclusive lock on the config file with timeout.
-func AcquireLock() (*ConfigLock, error) {
-	lockPath := lockFile
-	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+func openLockFile() (*os.File, error) {
+	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open lock file: %w", err)
 	}
+	return f, nil
+}
+
+// lockWait returns how long AcquireLock waits: lockTimeoutEnv if set,
+// otherwise lockTimeout
+func lockWait() (time.Duration, error) {
+	v := os.Getenv(lockTimeoutEnv)
+	if v == "" {
+		return lockTimeout, nil
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 1 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive number of seconds", lockTimeoutEnv, v)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// AcquireLock acquires an exclusive lock on the config file, waiting up to
+// lockTimeout (or lockTimeoutEnv seconds) for another instance to release it.
+func AcquireLock() (*ConfigLock, error) {
+	timeout, err := lockWait()
+	if err != nil {
+		return nil, err
+	}
 
-	deadline := time.Now().Add(lockTimeout)
+	f, err := openLockFile()
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
 	for {
 		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
 		if err == nil {
@@ -220,7 +369,7 @@ func AcquireLock() (*ConfigLock, error) {
 		}
 		if time.Now().After(deadline) {
 			f.Close()
-			return nil, fmt.Errorf("timeout waiting for config lock (another instance may be running)")
+			return nil, fmt.Errorf("timeout waiting for config lock after %s (another instance may be running; set %s to wait longer)", timeout, lockTimeoutEnv)
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
@@ -262,17 +411,87 @@ func (c *Config) AddContainer(name, image string) {
 	}
 }
 
+// RemoveContainer deletes a container entry and any depends_on references to it
 func (c *Config) RemoveContainer(name string) {
 	delete(c.Containers, name)
+	c.removeDependency(name)
 }
 
-func (c *Config) GetPorts(name string) []int {
+// RenameContainer moves a container entry (ports, user, snapshots) to a new name
+// and updates depends_on references to it
+func (c *Config) RenameContainer(oldName, newName string) {
+	container, ok := c.Containers[oldName]
+	if !ok {
+		return
+	}
+	c.Containers[newName] = container
+	delete(c.Containers, oldName)
+	c.replaceDependency(oldName, newName)
+}
+
+// GetPortMappings returns the port mappings for a container (per-container > defaults)
+func (c *Config) GetPortMappings(name string) []PortMapping {
 	if container, ok := c.Containers[name]; ok && len(container.Ports) > 0 {
 		return container.Ports
 	}
 	return c.Defaults.Ports
 }
 
+// GetPorts returns the local ports forwarded for a container
+func (c *Config) GetPorts(name string) []int {
+	return PortList(c.GetPortMappings(name)).LocalPorts()
+}
+
+// AddPort forwards another port for a container. A container without its own
+// ports inherits the defaults, so they are copied first to stay forwarded.
+func (c *Config) AddPort(name string, m PortMapping) error {
+	container, ok := c.Containers[name]
+	if !ok {
+		return fmt.Errorf("container '%s' not found", name)
+	}
+
+	ports := append(PortList{}, c.GetPortMappings(name)...)
+	for _, existing := range ports {
+		if existing.Local == m.Local {
+			return fmt.Errorf("port %d is already forwarded for '%s' (%s)", m.Local, name, existing)
+		}
+	}
+
+	container.Ports = append(ports, m)
+	c.Containers[name] = container
+	return nil
+}
+
+// RemovePort stops forwarding a local port for a container. Inherited defaults
+// are copied first so only the removed port stops being forwarded.
+func (c *Config) RemovePort(name string, local int) error {
+	container, ok := c.Containers[name]
+	if !ok {
+		return fmt.Errorf("container '%s' not found", name)
+	}
+
+	current := c.GetPortMappings(name)
+	ports := make(PortList, 0, len(current))
+	for _, m := range current {
+		if m.Local != local {
+			ports = append(ports, m)
+		}
+	}
+	if len(ports) == len(current) {
+		return fmt.Errorf("port %d is not forwarded for '%s'", local, name)
+	}
+	if len(ports) == 0 && len(c.Defaults.Ports) > 0 {
+		return fmt.Errorf("cannot remove the last port of '%s': a container without ports inherits the project defaults", name)
+	}
+
+	if len(ports) == 0 {
+		ports = nil
+	}
+	container.Ports = ports
+	c.Containers[name] = container
+	return nil
+}
+
 // GetUser returns the user config for a container (per-container > defaults > hardcoded)
 func (c *Config) GetUser(name string) User {
 	// Check per-container first
@@ -299,6 +518,111 @@ func (c *Config) GetUser(name string) User {
 	return User{Name: "dev", Password: "dev"}
 }
 
+// GetEnv returns environment variables for a container, merging defaults
+// with per-container overrides (per-container wins)
+func (c *Config) GetEnv(name string) map[string]string {
+	env := make(map[string]string)
+	for k, v := range c.Defaults.Env {
+		env[k] = v
+	}
+	if container, ok := c.Containers[name]; ok {
+		for k, v := range container.Env {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// GetSetup returns the setup commands for a container: the project
+// defaults first, then the container's own
+func (c *Config) GetSetup(name string) []string {
+	setup := append([]string{}, c.Defaults.Setup...)
+	if container, ok := c.Containers[name]; ok {
+		setup = append(setup, container.Setup...)
+	}
+	return setup
+}
+
+// GetDefaultImage returns the image 'container create' uses when none is
+// given, or "" if defaults.image is not set
+func (c *Config) GetDefaultImage() string {
+	return c.Defaults.Image
+}
+
+// NestingEnabled reports whether new containers get nesting (Docker support),
+// which is on unless defaults.nesting is false
+func (c *Config) NestingEnabled() bool {
+	return c.Defaults.Nesting == nil || *c.Defaults.Nesting
+}
+
+// GetWaitTimeout returns how many seconds to wait for a container to be
+// ready, falling back to the project default. 0 means not configured.
+func (c *Config) GetWaitTimeout(name string) int {
+	if container, ok := c.Containers[name]; ok && container.WaitTimeout > 0 {
+		return container.WaitTimeout
+	}
+	return c.Defaults.WaitTimeout
+}
+
+// GetLimits returns resource limits for a container.
+// Each limit falls back to the project default when not set on the container.
+func (c *Config) GetLimits(name string) Limits {
+	limits := c.Defaults.Limits
+	if container, ok := c.Containers[name]; ok {
+		if container.Limits.CPU != "" {
+			limits.CPU = container.Limits.CPU
+		}
+		if container.Limits.Memory != "" {
+			limits.Memory = container.Limits.Memory
+		}
+	}
+	return limits
+}
+
+// GetAutostart reports whether a container starts when the LXD host boots
+// (per-container > defaults)
+func (c *Config) GetAutostart(name string) bool {
+	if container, ok := c.Containers[name]; ok && container.Autostart != nil {
+		return *container.Autostart
+	}
+	return c.Defaults.Autostart
+}
+
+// SetAutostart records whether a container starts when the LXD host boots
+func (c *Config) SetAutostart(name string, enabled bool) {
+	container := c.Containers[name]
+	container.Autostart = &enabled
+	c.Containers[name] = container
+}
+
+// SetEnv sets a per-container environment variable
+func (c *Config) SetEnv(name, key, value string) {
+	container := c.Containers[name]
+	if container.Env == nil {
+		container.Env = make(map[string]string)
+	}
+	container.Env[key] = value
+	c.Containers[name] = container
+}
+
+// UnsetEnv removes a per-container environment variable.
+// Returns false if the variable was not set on the container.
+func (c *Config) UnsetEnv(name, key string) bool {
+	container, ok := c.Containers[name]
+	if !ok {
+		return false
+	}
+	if _, exists := container.Env[key]; !exists {
+		return false
+	}
+	delete(container.Env, key)
+	if len(container.Env) == 0 {
+		container.Env = nil
+	}
+	c.Containers[name] = container
+	return true
+}
+
 func (c *Config) HasContainer(name string) bool {
 	_, ok := c.Containers[name]
 	return ok
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Helper to run tests in a temp directory
@@ -61,8 +63,8 @@ containers:
 		if len(cfg.Defaults.Ports) != 2 {
 			t.Errorf("expected 2 default ports, got %d", len(cfg.Defaults.Ports))
 		}
-		if cfg.Defaults.Ports[0] != 3000 {
-			t.Errorf("expected port 3000, got %d", cfg.Defaults.Ports[0])
+		if cfg.Defaults.Ports[0] != (PortMapping{Local: 3000, Remote: 3000}) {
+			t.Errorf("expected port 3000, got %v", cfg.Defaults.Ports[0])
 		}
 
 		if len(cfg.Containers) != 2 {
@@ -116,7 +118,7 @@ func TestSave_CreatesFile(t *testing.T) {
 	withTempDir(t, func(dir string) {
 		cfg := &Config{
 			Defaults: Defaults{
-				Ports: []int{5173, 8000},
+				Ports: NewPortList(5173, 8000),
 			},
 			Containers: map[string]Container{
 				"test1": {Image: "ubuntu:24.04"},
@@ -147,7 +149,7 @@ func TestSave_OverwritesFile(t *testing.T) {
 	withTempDir(t, func(dir string) {
 		// Create initial config
 		cfg1 := &Config{
-			Defaults:   Defaults{Ports: []int{3000}},
+			Defaults:   Defaults{Ports: NewPortList(3000)},
 			Containers: map[string]Container{"old": {Image: "old-image"}},
 		}
 		if err := cfg1.Save(); err != nil {
@@ -156,7 +158,7 @@ func TestSave_OverwritesFile(t *testing.T) {
 
 		// Overwrite with new config
 		cfg2 := &Config{
-			Defaults:   Defaults{Ports: []int{8000}},
+			Defaults:   Defaults{Ports: NewPortList(8000)},
 			Containers: map[string]Container{"new": {Image: "new-image"}},
 		}
 		if err := cfg2.Save(); err != nil {
@@ -239,11 +241,11 @@ func TestRemoveContainer_NotExists(t *testing.T) {
 
 func TestGetPorts_ContainerSpecific(t *testing.T) {
 	cfg := &Config{
-		Defaults: Defaults{Ports: []int{3000, 8000}},
+		Defaults: Defaults{Ports: NewPortList(3000, 8000)},
 		Containers: map[string]Container{
 			"dev1": {
 				Image: "ubuntu",
-				Ports: []int{5000, 6000, 7000},
+				Ports: NewPortList(5000, 6000, 7000),
 			},
 		},
 	}
@@ -260,7 +262,7 @@ func TestGetPorts_ContainerSpecific(t *testing.T) {
 
 func TestGetPorts_DefaultFallback(t *testing.T) {
 	cfg := &Config{
-		Defaults: Defaults{Ports: []int{3000, 8000}},
+		Defaults: Defaults{Ports: NewPortList(3000, 8000)},
 		Containers: map[string]Container{
 			"dev1": {Image: "ubuntu"}, // No ports specified
 		},
@@ -278,7 +280,7 @@ func TestGetPorts_DefaultFallback(t *testing.T) {
 
 func TestGetPorts_EmptyDefaults(t *testing.T) {
 	cfg := &Config{
-		Defaults: Defaults{Ports: []int{}},
+		Defaults: Defaults{Ports: NewPortList()},
 		Containers: map[string]Container{
 			"dev1": {Image: "ubuntu"},
 		},
@@ -293,7 +295,7 @@ func TestGetPorts_EmptyDefaults(t *testing.T) {
 
 func TestGetPorts_NonexistentContainer(t *testing.T) {
 	cfg := &Config{
-		Defaults:   Defaults{Ports: []int{3000}},
+		Defaults:   Defaults{Ports: NewPortList(3000)},
 		Containers: map[string]Container{},
 	}
 
@@ -764,3 +766,805 @@ containers:
 		}
 	})
 }
+
+func TestRenameContainer(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{
+			"dev1": {
+				Image: "ubuntu:24.04",
+				Ports: NewPortList(8080),
+				Snapshots: map[string]Snapshot{
+					"initial-state": {Description: "Initial"},
+				},
+			},
+		},
+	}
+
+	cfg.RenameContainer("dev1", "api")
+
+	if cfg.HasContainer("dev1") {
+		t.Error("dev1 should be removed")
+	}
+	if !cfg.HasContainer("api") {
+		t.Fatal("api should exist")
+	}
+	if ports := cfg.GetPorts("api"); len(ports) != 1 || ports[0] != 8080 {
+		t.Errorf("expected ports to be preserved, got %v", ports)
+	}
+	if !cfg.HasSnapshot("api", "initial-state") {
+		t.Error("expected snapshots to be preserved")
+	}
+}
+
+func TestRenameContainer_NotExists(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{},
+	}
+
+	// Should not panic or create an entry
+	cfg.RenameContainer("nonexistent", "api")
+
+	if cfg.HasContainer("api") {
+		t.Error("api should not be created")
+	}
+}
+
+func TestLoad_WithRemote(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+defaults:
+  remote: lab
+containers: {}
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Defaults.Remote != "lab" {
+			t.Errorf("expected remote lab, got %q", cfg.Defaults.Remote)
+		}
+		// Remote does not change the project prefix
+		if cfg.GetLXCName("dev1") != "test-dev1" {
+			t.Errorf("expected test-dev1, got %s", cfg.GetLXCName("dev1"))
+		}
+	})
+}
+
+func TestLoad_InvalidRemote(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+defaults:
+  remote: "lab:"
+containers: {}
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected error for invalid remote")
+		}
+	})
+}
+
+func TestGetEnv_ContainerOverridesDefaults(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Env: map[string]string{"NODE_ENV": "development", "TZ": "UTC"}},
+		Containers: map[string]Container{
+			"dev1": {Image: "ubuntu:24.04", Env: map[string]string{"NODE_ENV": "test", "DEBUG": "1"}},
+		},
+	}
+
+	env := cfg.GetEnv("dev1")
+
+	if env["NODE_ENV"] != "test" {
+		t.Errorf("expected container value to win, got %s", env["NODE_ENV"])
+	}
+	if env["TZ"] != "UTC" {
+		t.Errorf("expected default TZ, got %s", env["TZ"])
+	}
+	if env["DEBUG"] != "1" {
+		t.Errorf("expected DEBUG=1, got %s", env["DEBUG"])
+	}
+}
+
+func TestGetEnv_Empty(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{
+			"dev1": {Image: "ubuntu:24.04"},
+		},
+	}
+
+	env := cfg.GetEnv("dev1")
+	if env == nil || len(env) != 0 {
+		t.Errorf("expected empty non-nil map, got %v", env)
+	}
+
+	// Unknown container falls back to defaults only
+	if len(cfg.GetEnv("nonexistent")) != 0 {
+		t.Error("expected empty env for nonexistent container")
+	}
+}
+
+func TestGetEnv_DoesNotMutateDefaults(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Env: map[string]string{"A": "1"}},
+		Containers: map[string]Container{
+			"dev1": {Env: map[string]string{"A": "2"}},
+		},
+	}
+
+	cfg.GetEnv("dev1")
+	if cfg.Defaults.Env["A"] != "1" {
+		t.Error("defaults should not be modified")
+	}
+}
+
+func TestLoad_InvalidEnv(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    env:
+      1BAD: value
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(); err == nil {
+			t.Fatal("expected error for invalid env name")
+		}
+	})
+}
+
+func TestSetEnv_CreatesMap(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{
+			"dev1": {Image: "ubuntu:24.04"},
+		},
+	}
+
+	cfg.SetEnv("dev1", "TZ", "UTC")
+
+	if cfg.Containers["dev1"].Env["TZ"] != "UTC" {
+		t.Errorf("expected TZ=UTC, got %v", cfg.Containers["dev1"].Env)
+	}
+	if cfg.Containers["dev1"].Image != "ubuntu:24.04" {
+		t.Error("other container fields should be preserved")
+	}
+}
+
+func TestUnsetEnv(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{
+			"dev1": {Env: map[string]string{"TZ": "UTC"}},
+		},
+	}
+
+	if cfg.UnsetEnv("dev1", "MISSING") {
+		t.Error("expected false for unset variable")
+	}
+	if !cfg.UnsetEnv("dev1", "TZ") {
+		t.Fatal("expected true for existing variable")
+	}
+	if cfg.Containers["dev1"].Env != nil {
+		t.Errorf("expected empty env to be cleared, got %v", cfg.Containers["dev1"].Env)
+	}
+	if cfg.UnsetEnv("nonexistent", "TZ") {
+		t.Error("expected false for nonexistent container")
+	}
+}
+
+func TestAddPort_CopiesInheritedDefaults(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Ports: NewPortList(5173, 8000)},
+		Containers: map[string]Container{
+			"dev1": {Image: "ubuntu:24.04"},
+		},
+	}
+
+	if err := cfg.AddPort("dev1", PortMapping{Local: 9000, Remote: 90}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := cfg.Containers["dev1"].Ports
+	want := PortList{{5173, 5173}, {8000, 8000}, {9000, 90}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("port %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+	if len(cfg.Defaults.Ports) != 2 {
+		t.Errorf("defaults should not be modified, got %v", cfg.Defaults.Ports)
+	}
+}
+
+func TestAddPort_DuplicateLocal(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{
+			"dev1": {Ports: NewPortList(3000)},
+		},
+	}
+
+	if err := cfg.AddPort("dev1", PortMapping{Local: 3000, Remote: 80}); err == nil {
+		t.Error("expected error for duplicate local port")
+	}
+	if err := cfg.AddPort("missing", PortMapping{Local: 1, Remote: 1}); err == nil {
+		t.Error("expected error for unknown container")
+	}
+}
+
+func TestRemovePort(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Ports: NewPortList(8000)},
+		Containers: map[string]Container{
+			"dev1": {Ports: NewPortList(3000, 4000)},
+			"dev2": {},
+		},
+	}
+
+	if err := cfg.RemovePort("dev1", 3000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.GetPorts("dev1"); len(got) != 1 || got[0] != 4000 {
+		t.Errorf("expected [4000], got %v", got)
+	}
+
+	if err := cfg.RemovePort("dev1", 3000); err == nil {
+		t.Error("expected error for port that is not forwarded")
+	}
+
+	// Removing the last port would silently fall back to the defaults
+	if err := cfg.RemovePort("dev1", 4000); err == nil {
+		t.Error("expected error when removing the last port")
+	}
+	if err := cfg.RemovePort("dev2", 8000); err == nil {
+		t.Error("expected error when removing the only inherited port")
+	}
+}
+
+func TestRemovePort_NoDefaults(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{
+			"dev1": {Ports: NewPortList(3000)},
+		},
+	}
+
+	if err := cfg.RemovePort("dev1", 3000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Containers["dev1"].Ports != nil {
+		t.Errorf("expected ports to be cleared, got %v", cfg.Containers["dev1"].Ports)
+	}
+}
+
+func TestGetLimits_FallsBackToDefaults(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Limits: Limits{CPU: "2", Memory: "2GB"}},
+		Containers: map[string]Container{
+			"dev1": {Limits: Limits{Memory: "4GB"}},
+			"dev2": {},
+		},
+	}
+
+	if got := cfg.GetLimits("dev1"); got.CPU != "2" || got.Memory != "4GB" {
+		t.Errorf("expected cpu=2 memory=4GB, got %+v", got)
+	}
+	if got := cfg.GetLimits("dev2"); got.CPU != "2" || got.Memory != "2GB" {
+		t.Errorf("expected defaults, got %+v", got)
+	}
+	if got := (&Config{}).GetLimits("dev1"); got != (Limits{}) {
+		t.Errorf("expected no limits, got %+v", got)
+	}
+}
+
+func TestLoad_InvalidLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"container memory", `project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    limits:
+      memory: plenty
+`},
+		{"default cpu", `project: test
+defaults:
+  limits:
+    cpu: all
+containers: {}
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTempDir(t, func(dir string) {
+				if err := os.WriteFile(ConfigFile, []byte(tt.yaml), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err := Load(); err == nil {
+					t.Fatal("expected error for invalid limits")
+				}
+			})
+		})
+	}
+}
+
+func TestLoad_InvalidBind(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+defaults:
+  bind: everywhere
+containers: {}
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(); err == nil {
+			t.Fatal("expected error for invalid bind address")
+		}
+	})
+}
+
+func TestCheck_Valid(t *testing.T) {
+	cfg := &Config{
+		Project:  "myapp",
+		Defaults: Defaults{Ports: NewPortList(8000)},
+		Containers: map[string]Container{
+			"dev1": {
+				Image: "ubuntu:24.04",
+				Snapshots: map[string]Snapshot{
+					"snap1": {CreatedAt: "2024-01-15T10:30:00Z"},
+				},
+			},
+		},
+	}
+
+	if errs := cfg.Check(); len(errs) != 0 {
+		t.Errorf("expected no problems, got %v", errs)
+	}
+}
+
+func TestCheck_CollectsAllProblems(t *testing.T) {
+	cfg := &Config{
+		Project:  "my app",
+		Defaults: Defaults{Bind: "localhost"},
+		Containers: map[string]Container{
+			"dev1": {Image: ""},
+			"dev2": {
+				Image: "ubuntu",
+				Snapshots: map[string]Snapshot{
+					"snap1": {CreatedAt: "2024-01-15 10:30"},
+				},
+			},
+		},
+	}
+
+	errs := cfg.Check()
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+
+	want := []string{"project", "defaults.bind", "containers.dev1.image", "containers.dev2.snapshots.snap1.created_at"}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestCheck_NegativeWaitTimeout(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{WaitTimeout: -1},
+		Containers: map[string]Container{
+			"dev1": {Image: "ubuntu:24.04", WaitTimeout: -5},
+		},
+	}
+
+	errs := cfg.Check()
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+	want := []string{"defaults.wait_timeout", "containers.dev1.wait_timeout"}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+
+	// Validate reports the first of the same problems
+	if err := cfg.Validate(); err == nil || err.Error() != errs[0].Error() {
+		t.Errorf("Validate() = %v, want %v", err, errs[0])
+	}
+}
+
+func TestValidate_ToleratesWhatLoadAccepts(t *testing.T) {
+	cfg := &Config{
+		Containers: map[string]Container{
+			"dev1": {Snapshots: map[string]Snapshot{"snap1": {CreatedAt: "yesterday"}}},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate should ignore a missing image and bad timestamps, got %v", err)
+	}
+	if len(cfg.Check()) != 2 {
+		t.Errorf("Check should report both, got %v", cfg.Check())
+	}
+}
+
+func TestLoadUnvalidated_SkipsValidation(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte("defaults:\n  ports: [70000]\n"), 0644)
+
+		if _, err := Load(); err == nil {
+			t.Fatal("Load should reject invalid ports")
+		}
+
+		cfg, err := LoadUnvalidated()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Containers == nil {
+			t.Error("Containers map should be initialized")
+		}
+	})
+}
+
+func TestLoad_ReadyStrategy(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ready_strategy: custom
+    ready_command: curl -sf localhost:8000/health
+`), 0644)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c := cfg.Containers["dev1"]
+		if c.ReadyStrategy != "custom" || c.ReadyCommand != "curl -sf localhost:8000/health" {
+			t.Errorf("unexpected ready settings: %+v", c)
+		}
+	})
+}
+
+func TestLoad_InvalidReadyStrategy(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte(`project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    ready_strategy: custom
+`), 0644)
+
+		if _, err := Load(); err == nil {
+			t.Fatal("expected error for custom strategy without ready_command")
+		}
+	})
+}
+
+func TestGetAutostart(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Autostart: true},
+		Containers: map[string]Container{
+			"inherit": {Image: "ubuntu:24.04"},
+			"off":     {Image: "ubuntu:24.04"},
+		},
+	}
+	cfg.SetAutostart("off", false)
+
+	if !cfg.GetAutostart("inherit") {
+		t.Error("expected container without a setting to inherit defaults.autostart")
+	}
+	if cfg.GetAutostart("off") {
+		t.Error("expected per-container false to override defaults")
+	}
+
+	cfg.Defaults.Autostart = false
+	if cfg.GetAutostart("inherit") {
+		t.Error("expected autostart off by default")
+	}
+}
+
+func TestAcquireLock_TimeoutFromEnv(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		t.Setenv(lockTimeoutEnv, "1")
+		holdLock(t, 3*time.Second)
+
+		start := time.Now()
+		lock, err := AcquireLock()
+		elapsed := time.Since(start)
+		if err == nil {
+			lock.Release()
+			t.Fatal("expected a timeout while the lock is held")
+		}
+		if !strings.Contains(err.Error(), "timeout waiting for config lock after 1s") {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if elapsed < time.Second || elapsed > 2500*time.Millisecond {
+			t.Errorf("expected to give up after about 1s, took %v", elapsed)
+		}
+	})
+}
+
+func TestAcquireLock_WaitsForRelease(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		t.Setenv(lockTimeoutEnv, "2")
+		holdLock(t, 300*time.Millisecond)
+
+		lock, err := AcquireLock()
+		if err != nil {
+			t.Fatalf("expected the lock once released, got %v", err)
+		}
+		lock.Release()
+	})
+}
+
+func TestAcquireLock_InvalidTimeoutEnv(t *testing.T) {
+	for _, v := range []string{"abc", "0", "-3", "1.5"} {
+		t.Run(v, func(t *testing.T) {
+			withTempDir(t, func(dir string) {
+				t.Setenv(lockTimeoutEnv, v)
+
+				_, err := AcquireLock()
+				if err == nil || !strings.Contains(err.Error(), "invalid "+lockTimeoutEnv) {
+					t.Errorf("expected invalid value error, got %v", err)
+				}
+			})
+		})
+	}
+}
+
+// holdLock takes the config lock in a goroutine and keeps it for d, or
+// until the test ends
+func holdLock(t *testing.T, d time.Duration) {
+	t.Helper()
+	held := make(chan error)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lock, err := AcquireLock()
+		held <- err
+		if err != nil {
+			return
+		}
+		select {
+		case <-time.After(d):
+		case <-stop:
+		}
+		lock.Release()
+	}()
+	if err := <-held; err != nil {
+		t.Fatalf("failed to take lock: %v", err)
+	}
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+	})
+}
+
+func TestLockWait_Default(t *testing.T) {
+	t.Setenv(lockTimeoutEnv, "")
+
+	timeout, err := lockWait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout != lockTimeout {
+		t.Errorf("expected default %v, got %v", lockTimeout, timeout)
+	}
+}
+
+func TestGetAllLXCNames(t *testing.T) {
+	cfg := &Config{
+		Project: "web",
+		Containers: map[string]Container{
+			"worker": {Image: "ubuntu:24.04"},
+			"api":    {Image: "ubuntu:24.04"},
+		},
+	}
+
+	got := cfg.GetAllLXCNames()
+	want := []string{"web-api", "web-worker"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("GetAllLXCNames() = %v, want %v", got, want)
+	}
+
+	cfg.Project = ""
+	if got := cfg.GetAllLXCNames(); strings.Join(got, ",") != "api,worker" {
+		t.Errorf("without project, got %v", got)
+	}
+
+	if got := (&Config{}).GetAllLXCNames(); len(got) != 0 {
+		t.Errorf("expected no names, got %v", got)
+	}
+}
+
+func TestGetSetup_DefaultsThenContainer(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Setup: []string{"apt-get update", "apt-get install -y git"}},
+		Containers: map[string]Container{
+			"dev1": {Image: "ubuntu:24.04", Setup: []string{"npm install -g pnpm"}},
+			"dev2": {Image: "ubuntu:24.04"},
+		},
+	}
+
+	got := strings.Join(cfg.GetSetup("dev1"), "; ")
+	if got != "apt-get update; apt-get install -y git; npm install -g pnpm" {
+		t.Errorf("unexpected setup for dev1: %s", got)
+	}
+	if got := cfg.GetSetup("dev2"); len(got) != 2 {
+		t.Errorf("expected defaults only for dev2, got %v", got)
+	}
+	if got := cfg.GetSetup("nonexistent"); len(got) != 2 {
+		t.Errorf("expected defaults only for unknown container, got %v", got)
+	}
+	if len(cfg.Defaults.Setup) != 2 {
+		t.Error("defaults should not be modified")
+	}
+}
+
+func TestGetSetup_Empty(t *testing.T) {
+	cfg := &Config{Containers: map[string]Container{"dev1": {Image: "ubuntu:24.04"}}}
+
+	if got := cfg.GetSetup("dev1"); len(got) != 0 {
+		t.Errorf("expected no setup commands, got %v", got)
+	}
+}
+
+func TestLoad_Setup(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+defaults:
+  setup:
+    - apt-get update
+containers:
+  dev1:
+    image: ubuntu:24.04
+    setup:
+      - make install
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := cfg.GetSetup("dev1"); len(got) != 2 || got[1] != "make install" {
+			t.Errorf("unexpected setup: %v", got)
+		}
+	})
+}
+
+func TestLoad_EmptySetupCommand(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    setup:
+      - ""
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(); err == nil {
+			t.Fatal("expected error for empty setup command")
+		}
+	})
+}
+
+func TestGetWaitTimeout(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{WaitTimeout: 120},
+		Containers: map[string]Container{
+			"alpine": {Image: "images:alpine/3.19", WaitTimeout: 15},
+			"ubuntu": {Image: "ubuntu:24.04"},
+		},
+	}
+
+	if got := cfg.GetWaitTimeout("alpine"); got != 15 {
+		t.Errorf("expected container value 15, got %d", got)
+	}
+	if got := cfg.GetWaitTimeout("ubuntu"); got != 120 {
+		t.Errorf("expected default 120, got %d", got)
+	}
+
+	cfg.Defaults.WaitTimeout = 0
+	if got := cfg.GetWaitTimeout("ubuntu"); got != 0 {
+		t.Errorf("expected 0 when unset, got %d", got)
+	}
+}
+
+func TestLoad_NegativeWaitTimeout(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    wait_timeout: -5
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(); err == nil {
+			t.Fatal("expected error for negative wait_timeout")
+		}
+	})
+}
+
+func TestNestingEnabled(t *testing.T) {
+	enabled, disabled := true, false
+
+	if !(&Config{}).NestingEnabled() {
+		t.Error("nesting should be enabled when unset")
+	}
+	if !(&Config{Defaults: Defaults{Nesting: &enabled}}).NestingEnabled() {
+		t.Error("nesting should be enabled when set to true")
+	}
+	if (&Config{Defaults: Defaults{Nesting: &disabled}}).NestingEnabled() {
+		t.Error("nesting should be disabled when set to false")
+	}
+}
+
+func TestGetDefaultImage(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+defaults:
+  image: images:debian/12
+containers: {}
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := cfg.GetDefaultImage(); got != "images:debian/12" {
+			t.Errorf("expected images:debian/12, got %q", got)
+		}
+	})
+
+	if got := (&Config{}).GetDefaultImage(); got != "" {
+		t.Errorf("expected no default image when unset, got %q", got)
+	}
+}
+
+func TestLoad_InvalidDefaultImage(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+defaults:
+  image: "ubuntu; rm -rf /"
+containers: {}
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "defaults.image") {
+			t.Fatalf("expected invalid default image error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validateDependsOn checks that every dependency of a container names
+// another container in the config
+func (c *Config) validateDependsOn(name string, deps []string) error {
+	seen := make(map[string]bool, len(deps))
+	for _, dep := range deps {
+		if dep == name {
+			return fmt.Errorf("container cannot depend on itself")
+		}
+		if _, ok := c.Containers[dep]; !ok {
+			return fmt.Errorf("depends on unknown container '%s'", dep)
+		}
+		if seen[dep] {
+			return fmt.Errorf("duplicate dependency '%s'", dep)
+		}
+		seen[dep] = true
+	}
+	return nil
+}
+
+// StartOrder groups containers into levels that can be started in order:
+// every container's dependencies are in an earlier level, so containers in
+// the same level can be started in parallel. Dependencies outside names are
+// ignored. Each level is sorted. Returns an error if dependencies form a cycle.
+func (c *Config) StartOrder(names []string) ([][]string, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	// Count unmet dependencies and record who waits on whom
+	pending := make(map[string]int, len(names))
+	dependents := make(map[string][]string)
+	for name := range wanted {
+		pending[name] = 0
+		for _, dep := range c.Containers[name].DependsOn {
+			if wanted[dep] {
+				pending[name]++
+				dependents[dep] = append(dependents[dep], name)
+			}
+		}
+	}
+
+	var levels [][]string
+	for len(pending) > 0 {
+		var level []string
+		for name, n := range pending {
+			if n == 0 {
+				level = append(level, name)
+			}
+		}
+		if len(level) == 0 {
+			cycle := make([]string, 0, len(pending))
+			for name := range pending {
+				cycle = append(cycle, name)
+			}
+			sort.Strings(cycle)
+			return nil, fmt.Errorf("dependency cycle between containers: %s", strings.Join(cycle, ", "))
+		}
+
+		sort.Strings(level)
+		for _, name := range level {
+			delete(pending, name)
+			for _, d := range dependents[name] {
+				pending[d]--
+			}
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// removeDependency drops name from every container's depends_on
+func (c *Config) removeDependency(name string) {
+	c.replaceDependency(name, "")
+}
+
+// replaceDependency points depends_on entries for oldName at newName, or
+// drops them when newName is empty
+func (c *Config) replaceDependency(oldName, newName string) {
+	for cname, container := range c.Containers {
+		if len(container.DependsOn) == 0 {
+			continue
+		}
+		deps := make([]string, 0, len(container.DependsOn))
+		changed := false
+		for _, dep := range container.DependsOn {
+			if dep != oldName {
+				deps = append(deps, dep)
+				continue
+			}
+			changed = true
+			if newName != "" {
+				deps = append(deps, newName)
+			}
+		}
+		if !changed {
+			continue
+		}
+		if len(deps) == 0 {
+			deps = nil
+		}
+		container.DependsOn = deps
+		c.Containers[cname] = container
+	}
+}
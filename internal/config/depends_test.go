@@ -0,0 +1,140 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func dependsConfig(deps map[string][]string) *Config {
+	cfg := &Config{Project: "test", Containers: map[string]Container{}}
+	for name, d := range deps {
+		cfg.Containers[name] = Container{Image: "ubuntu:24.04", DependsOn: d}
+	}
+	return cfg
+}
+
+func TestStartOrder(t *testing.T) {
+	cfg := dependsConfig(map[string][]string{
+		"web":   {"api"},
+		"api":   {"db", "cache"},
+		"db":    nil,
+		"cache": nil,
+		"docs":  nil,
+	})
+
+	levels, err := cfg.StartOrder([]string{"api", "cache", "db", "docs", "web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]string{{"cache", "db", "docs"}, {"api"}, {"web"}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+}
+
+func TestStartOrder_IgnoresUnselectedDependencies(t *testing.T) {
+	cfg := dependsConfig(map[string][]string{
+		"api": {"db"},
+		"db":  nil,
+	})
+
+	levels, err := cfg.StartOrder([]string{"api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(levels, [][]string{{"api"}}) {
+		t.Errorf("levels = %v", levels)
+	}
+}
+
+func TestStartOrder_Cycle(t *testing.T) {
+	cfg := dependsConfig(map[string][]string{
+		"a":     {"b"},
+		"b":     {"c"},
+		"c":     {"a"},
+		"other": nil,
+	})
+
+	_, err := cfg.StartOrder([]string{"a", "b", "c", "other"})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle between containers: a, b, c") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestValidate_DependsOn(t *testing.T) {
+	tests := []struct {
+		name   string
+		deps   []string
+		errMsg string
+	}{
+		{"unknown", []string{"missing"}, "depends on unknown container 'missing'"},
+		{"self", []string{"app"}, "cannot depend on itself"},
+		{"duplicate", []string{"db", "db"}, "duplicate dependency 'db'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := dependsConfig(map[string][]string{"app": tt.deps, "db": nil})
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+
+	cfg := dependsConfig(map[string][]string{"app": {"db"}, "db": nil})
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCheck_DependsOn(t *testing.T) {
+	cfg := dependsConfig(map[string][]string{"app": {"missing"}})
+	problems := cfg.Check()
+	if len(problems) != 1 || problems[0].Field != "containers.app.depends_on" {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+}
+
+func TestRemoveContainer_DropsDependency(t *testing.T) {
+	cfg := dependsConfig(map[string][]string{"app": {"db", "cache"}, "db": nil, "cache": nil})
+
+	cfg.RemoveContainer("db")
+
+	if got := cfg.Containers["app"].DependsOn; !reflect.DeepEqual(got, []string{"cache"}) {
+		t.Errorf("DependsOn = %v, want [cache]", got)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("config should stay valid: %v", err)
+	}
+}
+
+func TestRenameContainer_UpdatesDependency(t *testing.T) {
+	cfg := dependsConfig(map[string][]string{"app": {"db"}, "db": nil})
+
+	cfg.RenameContainer("db", "postgres")
+
+	if got := cfg.Containers["app"].DependsOn; !reflect.DeepEqual(got, []string{"postgres"}) {
+		t.Errorf("DependsOn = %v, want [postgres]", got)
+	}
+}
+
+func TestDependsOn_RoundTrip(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		cfg := dependsConfig(map[string][]string{"app": {"db"}, "db": nil})
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+
+		loaded, err := Load()
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if got := loaded.Containers["app"].DependsOn; !reflect.DeepEqual(got, []string{"db"}) {
+			t.Errorf("DependsOn = %v, want [db]", got)
+		}
+		if loaded.Containers["db"].DependsOn != nil {
+			t.Error("db should have no dependencies")
+		}
+	})
+}
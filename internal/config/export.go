@@ -0,0 +1,50 @@
+package config
+
+// ExportOptions controls what Export strips from a config
+type ExportOptions struct {
+	StripPasswords bool
+}
+
+// Export returns a copy of the config suitable for sharing as a project
+// template. Snapshot records (and their created_at timestamps) and deployed
+// SSH keys describe containers on this machine only, so they are always
+// dropped.
+func (c *Config) Export(opts ExportOptions) *Config {
+	out := &Config{
+		Version:    c.Version,
+		Project:    c.Project,
+		Defaults:   c.Defaults,
+		Containers: make(map[string]Container, len(c.Containers)),
+	}
+	out.Defaults.Ports = append(PortList(nil), c.Defaults.Ports...)
+	out.Defaults.Env = copyEnv(c.Defaults.Env)
+	if opts.StripPasswords {
+		out.Defaults.User.Password = ""
+	}
+
+	for name, container := range c.Containers {
+		container.Ports = append(PortList(nil), container.Ports...)
+		container.Env = copyEnv(container.Env)
+		container.Mounts = append([]Mount(nil), container.Mounts...)
+		container.DependsOn = append([]string(nil), container.DependsOn...)
+		container.Snapshots = nil
+		container.SSHKey = ""
+		if opts.StripPasswords {
+			container.User.Password = ""
+		}
+		out.Containers[name] = container
+	}
+
+	return out
+}
+
+func copyEnv(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+	out := make(map[string]string, len(env))
+	for k, v := range env {
+		out[k] = v
+	}
+	return out
+}
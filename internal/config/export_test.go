@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExport_DropsSnapshots(t *testing.T) {
+	cfg := &Config{
+		Project: "web",
+		Containers: map[string]Container{
+			"api": {
+				Image:  "ubuntu:24.04",
+				User:   User{Name: "app", Password: "hunter2"},
+				SSHKey: "ssh-ed25519 AAAAC3Nz me@laptop",
+				Snapshots: map[string]Snapshot{
+					"clean": {Description: "fresh", CreatedAt: "2026-01-02T03:04:05Z"},
+				},
+			},
+		},
+	}
+
+	out := cfg.Export(ExportOptions{})
+
+	if out.Containers["api"].Snapshots != nil {
+		t.Errorf("expected snapshots to be dropped, got %v", out.Containers["api"].Snapshots)
+	}
+	if out.Containers["api"].SSHKey != "" {
+		t.Errorf("expected ssh_key to be dropped, got %q", out.Containers["api"].SSHKey)
+	}
+	if out.Containers["api"].User.Password != "hunter2" {
+		t.Error("password should be kept without StripPasswords")
+	}
+	if len(cfg.Containers["api"].Snapshots) != 1 {
+		t.Error("original config should not be modified")
+	}
+}
+
+func TestExport_StripPasswords(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{User: User{Name: "dev", Password: "secret"}},
+		Containers: map[string]Container{
+			"api": {User: User{Name: "app", Password: "hunter2"}},
+		},
+	}
+
+	out := cfg.Export(ExportOptions{StripPasswords: true})
+
+	if out.Defaults.User.Password != "" || out.Containers["api"].User.Password != "" {
+		t.Errorf("expected passwords to be stripped, got %+v", out)
+	}
+	if out.Defaults.User.Name != "dev" || out.Containers["api"].User.Name != "app" {
+		t.Error("user names should be kept")
+	}
+	if cfg.Defaults.User.Password != "secret" || cfg.Containers["api"].User.Password != "hunter2" {
+		t.Error("original config should not be modified")
+	}
+}
+
+func TestExport_CopiesEnv(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Env: map[string]string{"TZ": "UTC"}},
+		Containers: map[string]Container{
+			"api": {Env: map[string]string{"A": "1"}},
+		},
+	}
+
+	out := cfg.Export(ExportOptions{})
+	out.Defaults.Env["TZ"] = "changed"
+	out.Containers["api"].Env["A"] = "changed"
+
+	if cfg.Defaults.Env["TZ"] != "UTC" || cfg.Containers["api"].Env["A"] != "1" {
+		t.Error("export should not share env maps with the original")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "template.yaml")
+	os.WriteFile(path, []byte("project: web\ncontainers:\n  api:\n    image: ubuntu:24.04\n"), 0644)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Project != "web" || !cfg.HasContainer("api") {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LoadFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(invalid, []byte("project: \"bad name\"\n"), 0644)
+	_, err := LoadFile(invalid)
+	if err == nil || !strings.Contains(err.Error(), "invalid.yaml") {
+		t.Errorf("expected validation error naming the file, got %v", err)
+	}
+
+	broken := filepath.Join(dir, "broken.yaml")
+	os.WriteFile(broken, []byte("project: [\n"), 0644)
+	if _, err := LoadFile(broken); err == nil {
+		t.Error("expected YAML error")
+	}
+}
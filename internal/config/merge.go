@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeOptions controls how MergeWithOptions handles conflicts
+type MergeOptions struct {
+	Overwrite bool // Replace containers that are already defined
+}
+
+// Merge adds another config, e.g. a shared base.yaml, to this one.
+// It fails without changing anything if other defines a container this
+// config already has.
+func (c *Config) Merge(other *Config) error {
+	return c.MergeWithOptions(other, MergeOptions{})
+}
+
+// MergeWithOptions adds other's containers, default ports missing from this
+// config and its default user if this config has none. Everything else
+// (project name, remote, other defaults) is kept from this config. Snapshot
+// records describe containers on another machine, so they are not merged.
+func (c *Config) MergeWithOptions(other *Config, opts MergeOptions) error {
+	if !opts.Overwrite {
+		var duplicates []string
+		for name := range other.Containers {
+			if c.HasContainer(name) {
+				duplicates = append(duplicates, name)
+			}
+		}
+		if len(duplicates) > 0 {
+			sort.Strings(duplicates)
+			return fmt.Errorf("container(s) already defined: %s", strings.Join(duplicates, ", "))
+		}
+	}
+
+	if c.Containers == nil {
+		c.Containers = make(map[string]Container, len(other.Containers))
+	}
+	exported := other.Export(ExportOptions{})
+	for name, container := range exported.Containers {
+		c.Containers[name] = container
+	}
+
+	for _, m := range other.Defaults.Ports {
+		if !hasLocalPort(c.Defaults.Ports, m.Local) {
+			c.Defaults.Ports = append(c.Defaults.Ports, m)
+		}
+	}
+
+	if c.Defaults.User.Name == "" {
+		c.Defaults.User = other.Defaults.User
+	}
+
+	return nil
+}
+
+// hasLocalPort reports whether ports already forwards the local port
+func hasLocalPort(ports PortList, local int) bool {
+	for _, m := range ports {
+		if m.Local == local {
+			return true
+		}
+	}
+	return false
+}
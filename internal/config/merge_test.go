@@ -0,0 +1,123 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge_AddsContainers(t *testing.T) {
+	cfg := &Config{
+		Project:    "web",
+		Containers: map[string]Container{"api": {Image: "ubuntu:24.04"}},
+	}
+	base := &Config{
+		Project: "base",
+		Containers: map[string]Container{
+			"db": {
+				Image:     "debian:12",
+				Ports:     NewPortList(5432),
+				Snapshots: map[string]Snapshot{"clean": {CreatedAt: "2026-01-02T03:04:05Z"}},
+			},
+		},
+	}
+
+	if err := cfg.Merge(base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Project != "web" {
+		t.Errorf("expected project name to be kept, got %q", cfg.Project)
+	}
+	if !cfg.HasContainer("api") || !cfg.HasContainer("db") {
+		t.Fatalf("expected both containers, got %v", cfg.Containers)
+	}
+	if cfg.Containers["db"].Image != "debian:12" || len(cfg.Containers["db"].Ports) != 1 {
+		t.Errorf("unexpected merged container: %+v", cfg.Containers["db"])
+	}
+	if cfg.Containers["db"].Snapshots != nil {
+		t.Error("snapshot records should not be merged")
+	}
+
+	// The merged config must not share slices with the source
+	cfg.Containers["db"].Ports[0].Local = 9999
+	if base.Containers["db"].Ports[0].Local != 5432 {
+		t.Error("source config should not be modified")
+	}
+}
+
+func TestMerge_RejectsDuplicates(t *testing.T) {
+	cfg := &Config{Containers: map[string]Container{
+		"api": {Image: "ubuntu:24.04"},
+		"db":  {Image: "ubuntu:24.04"},
+	}}
+	base := &Config{Containers: map[string]Container{
+		"db":    {Image: "debian:12"},
+		"api":   {Image: "debian:12"},
+		"cache": {Image: "debian:12"},
+	}}
+
+	err := cfg.Merge(base)
+	if err == nil {
+		t.Fatal("expected error for duplicate containers")
+	}
+	if !strings.Contains(err.Error(), "api, db") {
+		t.Errorf("expected sorted duplicate names in error, got: %v", err)
+	}
+	if cfg.HasContainer("cache") || cfg.Containers["db"].Image != "ubuntu:24.04" {
+		t.Error("config should not be changed when the merge fails")
+	}
+}
+
+func TestMergeWithOptions_Overwrite(t *testing.T) {
+	cfg := &Config{Containers: map[string]Container{"db": {Image: "ubuntu:24.04"}}}
+	base := &Config{Containers: map[string]Container{"db": {Image: "debian:12"}}}
+
+	if err := cfg.MergeWithOptions(base, MergeOptions{Overwrite: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Containers["db"].Image != "debian:12" {
+		t.Errorf("expected container to be overwritten, got %+v", cfg.Containers["db"])
+	}
+}
+
+func TestMerge_DefaultPorts(t *testing.T) {
+	cfg := &Config{Defaults: Defaults{Ports: PortList{{Local: 8000, Remote: 8000}, {Local: 5173, Remote: 5173}}}}
+	base := &Config{Defaults: Defaults{Ports: PortList{{Local: 8000, Remote: 9000}, {Local: 5432, Remote: 5432}}}}
+
+	if err := cfg.Merge(base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := PortList{{Local: 8000, Remote: 8000}, {Local: 5173, Remote: 5173}, {Local: 5432, Remote: 5432}}
+	if len(cfg.Defaults.Ports) != len(want) {
+		t.Fatalf("expected %v, got %v", want, cfg.Defaults.Ports)
+	}
+	for i := range want {
+		if cfg.Defaults.Ports[i] != want[i] {
+			t.Errorf("port %d: expected %v, got %v", i, want[i], cfg.Defaults.Ports[i])
+		}
+	}
+}
+
+func TestMerge_DefaultUser(t *testing.T) {
+	base := &Config{Defaults: Defaults{User: User{Name: "team", Password: "team"}}}
+
+	cfg := &Config{}
+	if err := cfg.Merge(base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Defaults.User.Name != "team" {
+		t.Errorf("expected default user from base, got %+v", cfg.Defaults.User)
+	}
+	if cfg.Containers == nil {
+		t.Error("expected containers map to be initialized")
+	}
+
+	cfg = &Config{Defaults: Defaults{User: User{Name: "me"}}}
+	if err := cfg.Merge(base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Defaults.User.Name != "me" {
+		t.Errorf("expected existing default user to be kept, got %+v", cfg.Defaults.User)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// CurrentVersion is the config schema version written by Save
+const CurrentVersion = 1
+
+// migrations[i] upgrades a config from version i to i+1
+var migrations = []func(*Config) error{
+	// 0 -> 1: files from before versioning already use the v1 layout
+	func(c *Config) error { return nil },
+}
+
+// checkVersion rejects versions this build does not understand
+func checkVersion(version int) error {
+	if version < 0 {
+		return fmt.Errorf("invalid config version %d", version)
+	}
+	if version > CurrentVersion {
+		return fmt.Errorf("config version %d is newer than this lxc-dev-manager supports (%d); please upgrade", version, CurrentVersion)
+	}
+	return nil
+}
+
+// migrate upgrades the config in memory to CurrentVersion.
+// Returns true if any migration ran.
+func (c *Config) migrate() (bool, error) {
+	if err := checkVersion(c.Version); err != nil {
+		return false, err
+	}
+
+	from := c.Version
+	for c.Version < CurrentVersion {
+		if err := migrations[c.Version](c); err != nil {
+			return false, fmt.Errorf("failed to migrate config from version %d: %w", c.Version, err)
+		}
+		c.Version++
+	}
+	return c.Version != from, nil
+}
+
+// saveMigrated rewrites the config file after a migration. It only takes the
+// lock if it is free: a caller holding it (LoadWithLock) saves the new
+// version itself, and a failed rewrite is retried on the next Load.
+func (c *Config) saveMigrated() {
+	lock, err := tryLock()
+	if err != nil {
+		return
+	}
+	defer lock.Release()
+	c.Save()
+}
+
+// tryLock takes the config lock without waiting
+func tryLock() (*ConfigLock, error) {
+	f, err := openLockFile()
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &ConfigLock{file: f}, nil
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const versionZeroConfig = `project: web
+defaults:
+  ports: [5173, "8080:80"]
+  env:
+    TZ: UTC
+containers:
+  api:
+    image: ubuntu:24.04
+    limits:
+      memory: 2GB
+    snapshots:
+      clean:
+        description: fresh install
+        created_at: "2026-01-02T03:04:05Z"
+`
+
+func TestLoad_MigratesVersionZero(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte(versionZeroConfig), 0644)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Version != CurrentVersion {
+			t.Errorf("expected version %d, got %d", CurrentVersion, cfg.Version)
+		}
+
+		data, _ := os.ReadFile(ConfigFile)
+		if !strings.Contains(string(data), "version: 1") {
+			t.Errorf("expected migrated file to be re-saved with its version, got:\n%s", data)
+		}
+
+		// Reload the rewritten file and check nothing was lost
+		cfg, err = Load()
+		if err != nil {
+			t.Fatalf("failed to reload migrated config: %v", err)
+		}
+		if cfg.Project != "web" {
+			t.Errorf("project = %q, want web", cfg.Project)
+		}
+		if got := PortList(cfg.Defaults.Ports); len(got) != 2 || got[1] != (PortMapping{Local: 8080, Remote: 80}) {
+			t.Errorf("unexpected default ports: %v", got)
+		}
+		if cfg.Defaults.Env["TZ"] != "UTC" {
+			t.Errorf("unexpected default env: %v", cfg.Defaults.Env)
+		}
+		api := cfg.Containers["api"]
+		if api.Image != "ubuntu:24.04" || api.Limits.Memory != "2GB" {
+			t.Errorf("unexpected container: %+v", api)
+		}
+		if snap := api.Snapshots["clean"]; snap.Description != "fresh install" || snap.CreatedAt != "2026-01-02T03:04:05Z" {
+			t.Errorf("unexpected snapshot: %+v", snap)
+		}
+	})
+}
+
+func TestLoad_MigrationSkippedWhileLocked(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte(versionZeroConfig), 0644)
+
+		cfg, lock, err := LoadWithLock()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer lock.Release()
+
+		if cfg.Version != CurrentVersion {
+			t.Errorf("expected in-memory migration, got version %d", cfg.Version)
+		}
+		data, _ := os.ReadFile(ConfigFile)
+		if strings.Contains(string(data), "version:") {
+			t.Error("file should not be rewritten while the lock is held")
+		}
+	})
+}
+
+func TestLoad_CurrentVersionNotRewritten(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		content := "version: 1\nproject: web\ncontainers: {}\n"
+		os.WriteFile(ConfigFile, []byte(content), 0644)
+
+		if _, err := Load(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, _ := os.ReadFile(ConfigFile)
+		if string(data) != content {
+			t.Errorf("file should be untouched, got:\n%s", data)
+		}
+	})
+}
+
+func TestLoad_NewerVersion(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte("version: 99\nproject: web\n"), 0644)
+
+		_, err := Load()
+		if err == nil {
+			t.Fatal("expected error for newer version")
+		}
+		if !strings.Contains(err.Error(), "newer") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestLoad_MissingFileStillNil(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		cfg, err := Load()
+		if err != nil || cfg != nil {
+			t.Errorf("expected nil config and no error, got %v, %v", cfg, err)
+		}
+	})
+}
+
+func TestSave_StampsCurrentVersion(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		cfg := &Config{Project: "web", Containers: map[string]Container{}}
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, _ := os.ReadFile(ConfigFile)
+		if !strings.HasPrefix(string(data), "version: 1\n") {
+			t.Errorf("expected version first in file, got:\n%s", data)
+		}
+	})
+}
+
+func TestCheck_NewerVersion(t *testing.T) {
+	cfg := &Config{Version: CurrentVersion + 1}
+
+	errs := cfg.Check()
+	if len(errs) == 0 || errs[0].Field != "version" {
+		t.Errorf("expected version error, got %v", errs)
+	}
+}
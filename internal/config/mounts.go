@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Mount bind-mounts a host directory into a container as an LXC disk device
+type Mount struct {
+	Source string `yaml:"source" json:"source"` // Host path
+	Target string `yaml:"target" json:"target"` // Absolute path in the container
+}
+
+// String returns "SOURCE:TARGET"
+func (m Mount) String() string {
+	return m.Source + ":" + m.Target
+}
+
+// ParseMount parses "SOURCE:TARGET"
+func ParseMount(s string) (Mount, error) {
+	source, target, ok := strings.Cut(s, ":")
+	if !ok || source == "" || target == "" {
+		return Mount{}, fmt.Errorf("invalid mount %q: expected SOURCE:TARGET", s)
+	}
+	return Mount{Source: source, Target: target}, nil
+}
+
+// Validate checks that the mount has a source and an absolute target
+func (m Mount) Validate() error {
+	if m.Source == "" {
+		return fmt.Errorf("mount source cannot be empty")
+	}
+	if !path.IsAbs(m.Target) {
+		return fmt.Errorf("mount target %q must be an absolute path", m.Target)
+	}
+	if path.Clean(m.Target) == "/" {
+		return fmt.Errorf("mount target cannot be /")
+	}
+	return nil
+}
+
+// ValidateMounts checks each mount; targets must be unique
+func ValidateMounts(mounts []Mount) error {
+	seen := make(map[string]bool, len(mounts))
+	for _, m := range mounts {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		target := path.Clean(m.Target)
+		if seen[target] {
+			return fmt.Errorf("duplicate mount target %s", target)
+		}
+		seen[target] = true
+	}
+	return nil
+}
+
+// AddMount records a mount on a container. Returns an error if the mount is
+// invalid or the target is already mounted.
+func (c *Config) AddMount(name string, m Mount) error {
+	container, ok := c.Containers[name]
+	if !ok {
+		return fmt.Errorf("container '%s' not found in project config", name)
+	}
+	if err := ValidateMounts(append(append([]Mount(nil), container.Mounts...), m)); err != nil {
+		return err
+	}
+	container.Mounts = append(container.Mounts, m)
+	c.Containers[name] = container
+	return nil
+}
+
+// RemoveMount drops the mount at target from a container. Returns an error
+// if nothing is mounted there.
+func (c *Config) RemoveMount(name, target string) (Mount, error) {
+	container, ok := c.Containers[name]
+	if !ok {
+		return Mount{}, fmt.Errorf("container '%s' not found in project config", name)
+	}
+	for i, m := range container.Mounts {
+		if path.Clean(m.Target) == path.Clean(target) {
+			container.Mounts = append(container.Mounts[:i:i], container.Mounts[i+1:]...)
+			if len(container.Mounts) == 0 {
+				container.Mounts = nil
+			}
+			c.Containers[name] = container
+			return m, nil
+		}
+	}
+	return Mount{}, fmt.Errorf("nothing is mounted at %s in '%s'", target, name)
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseMount(t *testing.T) {
+	m, err := ParseMount("./src:/home/dev/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Source != "./src" || m.Target != "/home/dev/app" {
+		t.Errorf("unexpected mount: %+v", m)
+	}
+
+	for _, s := range []string{"./src", ":/app", "./src:", ""} {
+		if _, err := ParseMount(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestValidateMounts(t *testing.T) {
+	if err := ValidateMounts([]Mount{{Source: "/a", Target: "/a"}, {Source: "/b", Target: "/b"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mounts []Mount
+		errMsg string
+	}{
+		{"empty source", []Mount{{Target: "/app"}}, "source cannot be empty"},
+		{"relative target", []Mount{{Source: "/src", Target: "app"}}, "absolute path"},
+		{"root target", []Mount{{Source: "/src", Target: "/"}}, "cannot be /"},
+		{"duplicate target", []Mount{{Source: "/a", Target: "/app"}, {Source: "/b", Target: "/app/"}}, "duplicate mount target /app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMounts(tt.mounts)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestAddMount(t *testing.T) {
+	cfg := &Config{Containers: map[string]Container{"dev1": {Image: "ubuntu:24.04"}}}
+
+	if err := cfg.AddMount("dev1", Mount{Source: "/src", Target: "/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cfg.AddMount("dev1", Mount{Source: "/other", Target: "/app"}); err == nil {
+		t.Error("expected error for an already mounted target")
+	}
+	if err := cfg.AddMount("missing", Mount{Source: "/src", Target: "/app"}); err == nil {
+		t.Error("expected error for unknown container")
+	}
+
+	mounts := cfg.Containers["dev1"].Mounts
+	if len(mounts) != 1 || mounts[0].Source != "/src" {
+		t.Errorf("unexpected mounts: %+v", mounts)
+	}
+}
+
+func TestMounts_SaveAndLoad(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		cfg := &Config{Project: "test", Containers: map[string]Container{"dev1": {Image: "ubuntu:24.04"}}}
+		cfg.AddMount("dev1", Mount{Source: "/home/me/app", Target: "/home/dev/app"})
+		if err := cfg.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		mounts := loaded.Containers["dev1"].Mounts
+		if len(mounts) != 1 || mounts[0] != (Mount{Source: "/home/me/app", Target: "/home/dev/app"}) {
+			t.Errorf("unexpected mounts after reload: %+v", mounts)
+		}
+	})
+}
+
+func TestLoad_InvalidMount(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yaml := `project: test
+containers:
+  dev1:
+    image: ubuntu:24.04
+    mounts:
+      - source: ./src
+        target: app
+`
+		if err := os.WriteFile(ConfigFile, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "absolute path") {
+			t.Errorf("expected invalid mount error, got %v", err)
+		}
+	})
+}
+
+func TestRemoveMount(t *testing.T) {
+	cfg := &Config{Containers: map[string]Container{"dev1": {
+		Image:  "ubuntu:24.04",
+		Mounts: []Mount{{Source: "/a", Target: "/app"}, {Source: "/b", Target: "/data"}},
+	}}}
+
+	m, err := cfg.RemoveMount("dev1", "/app/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Source != "/a" {
+		t.Errorf("removed %+v, want the /app mount", m)
+	}
+	if mounts := cfg.Containers["dev1"].Mounts; len(mounts) != 1 || mounts[0].Target != "/data" {
+		t.Errorf("unexpected mounts: %+v", mounts)
+	}
+
+	if _, err := cfg.RemoveMount("dev1", "/app"); err == nil {
+		t.Error("expected error for a target that is not mounted")
+	}
+	if _, err := cfg.RemoveMount("missing", "/data"); err == nil {
+		t.Error("expected error for unknown container")
+	}
+
+	cfg.RemoveMount("dev1", "/data")
+	if cfg.Containers["dev1"].Mounts != nil {
+		t.Error("expected no mounts left")
+	}
+}
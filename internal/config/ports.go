@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PortMapping forwards a local (host) port to a port in the container
+type PortMapping struct {
+	Local  int `json:"local"`
+	Remote int `json:"remote"`
+}
+
+// String returns "8080" for identical ports, or "8080:80" for a remapping
+func (m PortMapping) String() string {
+	if m.Local == m.Remote {
+		return strconv.Itoa(m.Local)
+	}
+	return fmt.Sprintf("%d:%d", m.Local, m.Remote)
+}
+
+// ParsePortMapping parses "8080" or "8080:80" (local:remote)
+func ParsePortMapping(s string) (PortMapping, error) {
+	localStr, remoteStr, remapped := strings.Cut(strings.TrimSpace(s), ":")
+
+	local, err := strconv.Atoi(localStr)
+	if err != nil {
+		return PortMapping{}, fmt.Errorf("invalid port %q: expected PORT or LOCAL:REMOTE", s)
+	}
+	remote := local
+	if remapped {
+		remote, err = strconv.Atoi(remoteStr)
+		if err != nil {
+			return PortMapping{}, fmt.Errorf("invalid port %q: expected PORT or LOCAL:REMOTE", s)
+		}
+	}
+
+	return PortMapping{Local: local, Remote: remote}, nil
+}
+
+// PortList is a list of port mappings. In YAML, each entry is either a plain
+// port (8080) forwarded to the same port, or a "local:remote" string ("8080:80").
+type PortList []PortMapping
+
+// NewPortList builds a PortList that forwards each port to itself
+func NewPortList(ports ...int) PortList {
+	list := make(PortList, len(ports))
+	for i, p := range ports {
+		list[i] = PortMapping{Local: p, Remote: p}
+	}
+	return list
+}
+
+// LocalPorts returns the local side of each mapping
+func (p PortList) LocalPorts() []int {
+	ports := make([]int, len(p))
+	for i, m := range p {
+		ports[i] = m.Local
+	}
+	return ports
+}
+
+// UnmarshalYAML accepts both integer and "local:remote" string entries
+func (p *PortList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: ports must be a list", value.Line)
+	}
+
+	list := make(PortList, 0, len(value.Content))
+	for _, node := range value.Content {
+		if node.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: invalid port entry", node.Line)
+		}
+		m, err := ParsePortMapping(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		list = append(list, m)
+	}
+
+	*p = list
+	return nil
+}
+
+// MarshalYAML writes plain ports as integers and remappings as strings
+func (p PortList) MarshalYAML() (interface{}, error) {
+	out := make([]interface{}, len(p))
+	for i, m := range p {
+		if m.Local == m.Remote {
+			out[i] = m.Local
+		} else {
+			out[i] = m.String()
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParsePortMapping(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    PortMapping
+		wantErr bool
+	}{
+		{"8080", PortMapping{Local: 8080, Remote: 8080}, false},
+		{"8080:80", PortMapping{Local: 8080, Remote: 80}, false},
+		{" 3000 ", PortMapping{Local: 3000, Remote: 3000}, false},
+		{"", PortMapping{}, true},
+		{"http", PortMapping{}, true},
+		{"8080:", PortMapping{}, true},
+		{":80", PortMapping{}, true},
+		{"8080:80:90", PortMapping{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePortMapping(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePortMapping(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePortMapping(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortMapping_String(t *testing.T) {
+	if s := (PortMapping{Local: 8080, Remote: 8080}).String(); s != "8080" {
+		t.Errorf("expected 8080, got %s", s)
+	}
+	if s := (PortMapping{Local: 8080, Remote: 80}).String(); s != "8080:80" {
+		t.Errorf("expected 8080:80, got %s", s)
+	}
+}
+
+func TestPortList_UnmarshalYAML_MixedForms(t *testing.T) {
+	var c Container
+	err := yaml.Unmarshal([]byte(`image: ubuntu:24.04
+ports:
+  - 3000
+  - "8080:80"
+  - 5432:5433
+`), &c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := PortList{{3000, 3000}, {8080, 80}, {5432, 5433}}
+	if len(c.Ports) != len(want) {
+		t.Fatalf("expected %d ports, got %v", len(want), c.Ports)
+	}
+	for i := range want {
+		if c.Ports[i] != want[i] {
+			t.Errorf("port[%d]: expected %+v, got %+v", i, want[i], c.Ports[i])
+		}
+	}
+}
+
+func TestPortList_UnmarshalYAML_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"word", "ports: [http]"},
+		{"bad remote", `ports: ["8080:x"]`},
+		{"not a list", "ports: 8080"},
+		{"nested", "ports: [[8080]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Container
+			if err := yaml.Unmarshal([]byte(tt.yaml), &c); err == nil {
+				t.Errorf("expected error, got %+v", c.Ports)
+			}
+		})
+	}
+}
+
+func TestPortList_MarshalYAML(t *testing.T) {
+	data, err := yaml.Marshal(Container{
+		Image: "ubuntu:24.04",
+		Ports: PortList{{3000, 3000}, {8080, 80}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := string(data)
+	if !strings.Contains(out, "- 3000\n") {
+		t.Errorf("expected plain port as integer:\n%s", out)
+	}
+	if !strings.Contains(out, "- 8080:80\n") {
+		t.Errorf("expected remapped port as string:\n%s", out)
+	}
+}
+
+func TestGetPortMappings(t *testing.T) {
+	cfg := &Config{
+		Defaults: Defaults{Ports: NewPortList(5173)},
+		Containers: map[string]Container{
+			"web": {Ports: PortList{{8080, 80}}},
+			"dev": {},
+		},
+	}
+
+	if got := cfg.GetPortMappings("web"); len(got) != 1 || got[0] != (PortMapping{8080, 80}) {
+		t.Errorf("expected container mapping, got %v", got)
+	}
+	if got := cfg.GetPortMappings("dev"); len(got) != 1 || got[0] != (PortMapping{5173, 5173}) {
+		t.Errorf("expected default mapping, got %v", got)
+	}
+	if got := cfg.GetPorts("web"); len(got) != 1 || got[0] != 8080 {
+		t.Errorf("expected local port 8080, got %v", got)
+	}
+}
+
+func TestLoad_PortMappingRoundTrip(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yamlData := `project: test
+defaults:
+  ports: [5173]
+containers:
+  web:
+    image: ubuntu:24.04
+    ports:
+      - "8080:80"
+`
+		if err := os.WriteFile(ConfigFile, []byte(yamlData), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+
+		cfg, err = Load()
+		if err != nil {
+			t.Fatalf("failed to reload: %v", err)
+		}
+		if got := cfg.GetPortMappings("web"); len(got) != 1 || got[0] != (PortMapping{8080, 80}) {
+			t.Errorf("mapping lost on save: %v", got)
+		}
+	})
+}
+
+func TestLoad_InvalidRemotePort(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		yamlData := `project: test
+containers:
+  web:
+    image: ubuntu:24.04
+    ports: ["8080:70000"]
+`
+		if err := os.WriteFile(ConfigFile, []byte(yamlData), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(); err == nil {
+			t.Fatal("expected error for out-of-range remote port")
+		}
+	})
+}
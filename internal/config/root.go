@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MaxSearchDepth is how many parent directories are checked for ConfigFile
+// when the working directory has none
+var MaxSearchDepth = 5
+
+// findProjectRoot walks upward from dir and returns the first directory
+// containing ConfigFile, checking dir itself and at most maxDepth parents
+func findProjectRoot(dir string, maxDepth int) (string, bool) {
+	for depth := 0; depth <= maxDepth; depth++ {
+		if info, err := os.Stat(filepath.Join(dir, ConfigFile)); err == nil && !info.IsDir() {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", false
+}
+
+// EnterProjectRoot changes the working directory to the nearest parent
+// holding ConfigFile. It returns the new directory, or "" when the working
+// directory already holds the config or no project was found, in which case
+// nothing changes and Load reports the missing project as usual.
+func EnterProjectRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	root, ok := findProjectRoot(wd, MaxSearchDepth)
+	if !ok || root == wd {
+		return "", nil
+	}
+
+	if err := os.Chdir(root); err != nil {
+		return "", fmt.Errorf("failed to change to project root %s: %w", root, err)
+	}
+	return root, nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mkdirs(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindProjectRoot_CurrentDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte("project: test\n"), 0644)
+
+	root, ok := findProjectRoot(dir, 5)
+	if !ok || root != dir {
+		t.Errorf("expected %s, got %q (found=%v)", dir, root, ok)
+	}
+}
+
+func TestFindProjectRoot_Parent(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte("project: test\n"), 0644)
+	sub := filepath.Join(dir, "src", "app")
+	mkdirs(t, sub)
+
+	root, ok := findProjectRoot(sub, 5)
+	if !ok || root != dir {
+		t.Errorf("expected %s, got %q (found=%v)", dir, root, ok)
+	}
+}
+
+func TestFindProjectRoot_NearestWins(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte("project: outer\n"), 0644)
+	inner := filepath.Join(dir, "inner")
+	mkdirs(t, filepath.Join(inner, "src"))
+	os.WriteFile(filepath.Join(inner, ConfigFile), []byte("project: inner\n"), 0644)
+
+	root, ok := findProjectRoot(filepath.Join(inner, "src"), 5)
+	if !ok || root != inner {
+		t.Errorf("expected %s, got %q (found=%v)", inner, root, ok)
+	}
+}
+
+func TestFindProjectRoot_BeyondMaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte("project: test\n"), 0644)
+	sub := filepath.Join(dir, "a", "b", "c")
+	mkdirs(t, sub)
+
+	if _, ok := findProjectRoot(sub, 2); ok {
+		t.Error("expected no project within 2 levels")
+	}
+	if root, ok := findProjectRoot(sub, 3); !ok || root != dir {
+		t.Errorf("expected %s within 3 levels, got %q", dir, root)
+	}
+}
+
+func TestFindProjectRoot_IgnoresDirectory(t *testing.T) {
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, ConfigFile))
+
+	if _, ok := findProjectRoot(dir, 0); ok {
+		t.Error("expected a directory named containers.yaml to be ignored")
+	}
+}
+
+func TestEnterProjectRoot_FromSubdir(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte("project: test\ncontainers: {}\n"), 0644)
+		mkdirs(t, filepath.Join("src", "app"))
+		os.Chdir(filepath.Join("src", "app"))
+
+		root, err := EnterProjectRoot()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wd, _ := os.Getwd()
+		if root != wd {
+			t.Errorf("expected root %q to be the new working directory %q", root, wd)
+		}
+
+		cfg, err := Load()
+		if err != nil || cfg == nil {
+			t.Fatalf("expected config to load from project root, got %v", err)
+		}
+		if cfg.Project != "test" {
+			t.Errorf("expected project 'test', got %q", cfg.Project)
+		}
+	})
+}
+
+func TestEnterProjectRoot_AlreadyAtRoot(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		os.WriteFile(ConfigFile, []byte("project: test\n"), 0644)
+
+		root, err := EnterProjectRoot()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if root != "" {
+			t.Errorf("expected no directory change, got %q", root)
+		}
+	})
+}
+
+func TestEnterProjectRoot_NotFound(t *testing.T) {
+	withTempDir(t, func(dir string) {
+		before, _ := os.Getwd()
+
+		root, err := EnterProjectRoot()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if root != "" {
+			t.Errorf("expected no project, got %q", root)
+		}
+		if after, _ := os.Getwd(); after != before {
+			t.Errorf("expected working directory to stay %s, got %s", before, after)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package lxc
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var (
+	// ErrNotFound means the container, snapshot, image or path does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists means the container, snapshot or alias is already taken
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrNoIP means the container has no IPv4 address (yet)
+	ErrNoIP = errors.New("container has no IP address")
+	// ErrStatefulUnsupported means saving a container's running state failed
+	// because CRIU or migration.stateful is missing
+	ErrStatefulUnsupported = errors.New("stateful operations are not supported")
+)
+
+// LXCError is a failed lxc command. It unwraps to ErrNotFound,
+// ErrAlreadyExists or ErrStatefulUnsupported when lxc's output says so,
+// otherwise to the error returned by the executor.
+type LXCError struct {
+	Op     string // What was attempted, e.g. "start container"
+	Name   string // Container, snapshot or image the command acted on
+	Output string // lxc's trimmed error output
+	err    error
+}
+
+func (e *LXCError) Error() string {
+	if e.Output == "" && e.err != nil {
+		return fmt.Sprintf("failed to %s: %v", e.Op, e.err)
+	}
+	return fmt.Sprintf("failed to %s: %s", e.Op, e.Output)
+}
+
+func (e *LXCError) Unwrap() error {
+	return e.err
+}
+
+// newError classifies a failed lxc command from its output. For commands
+// run without combined output, the message is taken from the exit error's
+// stderr, or from the error itself when lxc never ran.
+func newError(op, name string, output []byte, err error) *LXCError {
+	msg := strings.TrimSpace(string(output))
+	var exitErr *exec.ExitError
+	if msg == "" && errors.As(err, &exitErr) {
+		msg = strings.TrimSpace(string(exitErr.Stderr))
+	}
+	if msg == "" && err != nil && exitErr == nil {
+		msg = err.Error()
+	}
+
+	cause := err
+	lower := strings.ToLower(msg)
+	switch {
+	// Checked first: a missing CRIU binary can be reported as "not found"
+	case strings.Contains(lower, "criu") || strings.Contains(lower, "migration.stateful"):
+		cause = ErrStatefulUnsupported
+	case strings.Contains(lower, "not found"):
+		cause = ErrNotFound
+	case strings.Contains(lower, "already exists"):
+		cause = ErrAlreadyExists
+	}
+
+	return &LXCError{Op: op, Name: name, Output: msg, err: cause}
+}
+
+// RenameStage records how far a copy-based rename got
+type RenameStage int
+
+const (
+	RenameNotStarted RenameStage = iota // Nothing was changed
+	RenameStopped                       // The old container was stopped
+	RenameCopied                        // The copy exists next to the old container
+	RenameStarted                       // The copy was started; the old container still exists
+)
+
+func (s RenameStage) String() string {
+	switch s {
+	case RenameStopped:
+		return "the old container was stopped"
+	case RenameCopied:
+		return "the copy was created, the old container still exists"
+	case RenameStarted:
+		return "the copy was started, the old container still exists"
+	default:
+		return "nothing was changed"
+	}
+}
+
+// RenameError is a copy-based rename that failed part way. Stage tells
+// which steps had already been done, so the caller can finish or undo them.
+// It unwraps to the error of the failed step.
+type RenameError struct {
+	OldName string
+	NewName string
+	Stage   RenameStage
+	err     error
+}
+
+func (e *RenameError) Error() string {
+	return fmt.Sprintf("rename %s to %s failed (%s): %v", e.OldName, e.NewName, e.Stage, e.err)
+}
+
+func (e *RenameError) Unwrap() error {
+	return e.err
+}
@@ -1,37 +1,169 @@
 package lxc
 
 import (
+	"context"
+	"io"
 	"os/exec"
+	"strings"
+	"sync/atomic"
 )
 
 // Executor interface for running LXC commands (allows mocking)
 type Executor interface {
 	Run(args ...string) ([]byte, error)
 	RunCombined(args ...string) ([]byte, error)
+	RunContext(ctx context.Context, args ...string) ([]byte, error)
+	RunCombinedContext(ctx context.Context, args ...string) ([]byte, error)
+	RunStream(ctx context.Context, stdout, stderr io.Writer, args ...string) error
 }
 
 // RealExecutor executes actual LXC commands
 type RealExecutor struct{}
 
 func (e *RealExecutor) Run(args ...string) ([]byte, error) {
-	cmd := exec.Command("lxc", args...)
-	return cmd.Output()
+	return e.RunContext(context.Background(), args...)
 }
 
 func (e *RealExecutor) RunCombined(args ...string) ([]byte, error) {
-	cmd := exec.Command("lxc", args...)
+	return e.RunCombinedContext(context.Background(), args...)
+}
+
+// RunContext runs an LXC command, killing it if ctx is cancelled
+func (e *RealExecutor) RunContext(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "lxc", args...)
+	return cmd.Output()
+}
+
+// RunCombinedContext runs an LXC command with combined output, killing it if ctx is cancelled
+func (e *RealExecutor) RunCombinedContext(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "lxc", args...)
 	return cmd.CombinedOutput()
 }
 
+// RunStream runs an LXC command with its output streamed to stdout and
+// stderr as it is produced, killing it if ctx is cancelled
+func (e *RealExecutor) RunStream(ctx context.Context, stdout, stderr io.Writer, args ...string) error {
+	cmd := exec.CommandContext(ctx, "lxc", args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
 // DefaultExecutor is the executor used by default
 var DefaultExecutor Executor = &RealExecutor{}
 
 // SetExecutor sets the executor (for testing)
 func SetExecutor(e Executor) {
 	DefaultExecutor = e
+	clearInfoCache()
 }
 
 // ResetExecutor resets to the real executor
 func ResetExecutor() {
 	DefaultExecutor = &RealExecutor{}
+	clearInfoCache()
+}
+
+// remote is the LXD remote that instance and image names are resolved against.
+// Empty means the lxc client's default remote. It is read by concurrent
+// --all operations, so it is only accessed atomically.
+var remote atomic.Value
+
+// SetRemote sets the LXD remote used by all subsequent LXC commands
+func SetRemote(name string) {
+	remote.Store(name)
+}
+
+// GetRemote returns the configured LXD remote ("" for the default remote)
+func GetRemote() string {
+	name, _ := remote.Load().(string)
+	return name
+}
+
+// InstanceRef qualifies an instance name with the configured remote (remote:name)
+func InstanceRef(name string) string {
+	remote := GetRemote()
+	if remote == "" {
+		return name
+	}
+	return remote + ":" + name
+}
+
+// remoteRef returns "remote:" for commands that target a whole remote,
+// or "" when using the default remote
+func remoteRef() string {
+	remote := GetRemote()
+	if remote == "" {
+		return ""
+	}
+	return remote + ":"
+}
+
+// imageRef qualifies an image alias with the configured remote, unless the
+// image already names a remote (e.g. "ubuntu:24.04" or "images:alpine/3.19")
+func imageRef(image string) string {
+	remote := GetRemote()
+	if remote == "" || strings.Contains(image, ":") {
+		return image
+	}
+	return remote + ":" + image
+}
+
+// publishArgs builds the args for publishing source as an image, storing
+// the image on the configured remote rather than the local image store
+func publishArgs(source, alias string) []string {
+	args := []string{"publish", source}
+	if GetRemote() != "" {
+		args = append(args, remoteRef())
+	}
+	return append(args, "--alias", alias)
+}
+
+// pullImageArgs builds the args for copying a remote image (e.g. ubuntu:24.04)
+// into the configured remote, or the local image store by default
+func pullImageArgs(source, alias string) []string {
+	target := remoteRef()
+	if target == "" {
+		target = "local:"
+	}
+	args := []string{"image", "copy", source, target, "--copy-aliases"}
+	if alias != "" {
+		args = append(args, "--alias", alias)
+	}
+	return args
+}
+
+// exportImageArgs builds the args for writing an image to a file at path
+func exportImageArgs(alias, path string) []string {
+	return []string{"image", "export", imageRef(alias), path}
+}
+
+// importImageArgs builds the args for importing an image file into the
+// configured remote, or the local image store by default
+func importImageArgs(path, alias string) []string {
+	args := []string{"image", "import", path}
+	if GetRemote() != "" {
+		args = append(args, remoteRef())
+	}
+	return append(args, "--alias", alias)
+}
+
+// imageListArgs builds the args for "image list", scoped to alias if given
+func imageListArgs(alias string, flags ...string) []string {
+	args := []string{"image", "list"}
+	if alias != "" {
+		args = append(args, imageRef(alias))
+	} else if GetRemote() != "" {
+		args = append(args, remoteRef())
+	}
+	return append(args, flags...)
+}
+
+// listAllArgs builds the args for listing every instance on the remote
+func listAllArgs() []string {
+	args := []string{"list"}
+	if GetRemote() != "" {
+		args = append(args, remoteRef())
+	}
+	return append(args, "-c", "ns4", "-f", "csv")
 }
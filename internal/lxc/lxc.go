@@ -1,28 +1,159 @@
 package lxc
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"lxc-dev-manager/internal/validation"
 )
 
 // Launch creates and starts a new container
 func Launch(name, image string) error {
-	output, err := DefaultExecutor.RunCombined("launch", image, name)
-	if err != nil {
-		return fmt.Errorf("failed to launch container: %s", string(output))
+	return LaunchWithRetry(name, image, 1, 0)
+}
+
+// LaunchWithRetry runs Launch up to attempts times while it fails with a
+// transient network error, such as a dropped connection while the image
+// downloads. The wait starts at backoff and doubles after each failed
+// attempt. Any other error is returned at once, and so is a transient one
+// once the instance exists: launch then got past creating it and failed to
+// start it, which retrying the launch cannot fix.
+func LaunchWithRetry(name, image string, attempts int, backoff time.Duration) error {
+	var err error
+	for i := 0; i < attempts || i == 0; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		var output []byte
+		output, err = DefaultExecutor.RunCombined("launch", imageRef(image), InstanceRef(name))
+		if err == nil {
+			return nil
+		}
+		err = newError("launch container", name, output, err)
+		if !isTransientLaunchError(err) || Exists(name) {
+			return err
+		}
 	}
-	return nil
+	return err
+}
+
+// transientLaunchErrors are lowercase fragments of network failures that
+// may succeed on a later attempt. They are specific on purpose: a launch
+// that fails over a misconfigured network device should not be retried.
+var transientLaunchErrors = []string{
+	"connection refused",
+	"connection reset",
+	"i/o timeout",
+	"tls handshake timeout",
+	"network is unreachable",
+	"no route to host",
+	"temporary failure in name resolution",
+}
+
+// isTransientLaunchError reports whether a failed launch is worth retrying
+func isTransientLaunchError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range transientLaunchErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
 }
 
 // ConfigSet sets a config key on a container
 func ConfigSet(name, key, value string) error {
-	output, err := DefaultExecutor.RunCombined("config", "set", name, key, value)
+	output, err := DefaultExecutor.RunCombined("config", "set", InstanceRef(name), key, value)
 	if err != nil {
-		return fmt.Errorf("failed to set config %s: %s", key, string(output))
+		return newError("set config "+key, name, output, err)
+	}
+	return nil
+}
+
+// AddProfile applies an LXD profile to a container
+func AddProfile(name, profile string) error {
+	output, err := DefaultExecutor.RunCombined("profile", "add", InstanceRef(name), profile)
+	if err != nil {
+		return newError("add profile "+profile, name, output, err)
+	}
+	return nil
+}
+
+// AddDevice bind-mounts a host path into a container as a disk device
+func AddDevice(container, deviceName, source, target string) error {
+	output, err := DefaultExecutor.RunCombined("config", "device", "add", InstanceRef(container), deviceName, "disk", "source="+source, "path="+target)
+	if err != nil {
+		return newError("add device "+deviceName, container, output, err)
+	}
+	return nil
+}
+
+// MountDeviceName returns the disk device name used for a bind-mount at
+// containerPath, so the same path always maps to the same device
+func MountDeviceName(containerPath string) string {
+	return "mount" + strings.ReplaceAll(path.Clean(containerPath), "/", "-")
+}
+
+// Mount bind-mounts hostPath into a container at containerPath. The device
+// is named after containerPath, see MountDeviceName.
+func Mount(container, hostPath, containerPath string) error {
+	return AddDevice(container, MountDeviceName(containerPath), hostPath, containerPath)
+}
+
+// Unmount removes a device (such as a bind-mount added by Mount) from a container
+func Unmount(container, deviceName string) error {
+	output, err := DefaultExecutor.RunCombined("config", "device", "remove", InstanceRef(container), deviceName)
+	if err != nil {
+		return newError("remove device "+deviceName, container, output, err)
+	}
+	return nil
+}
+
+// ConfigGet reads a single config key from a container
+func ConfigGet(name, key string) (string, error) {
+	output, err := DefaultExecutor.RunCombined("config", "get", InstanceRef(name), key)
+	if err != nil {
+		return "", newError("get config "+key, name, output, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetCPULimit sets limits.cpu (number of CPUs or a CPU range like "0-3")
+func SetCPULimit(name, cpu string) error {
+	return ConfigSet(name, "limits.cpu", cpu)
+}
+
+// SetMemoryLimit sets limits.memory (e.g. "2GiB" or "50%")
+func SetMemoryLimit(name, memory string) error {
+	return ConfigSet(name, "limits.memory", memory)
+}
+
+// SetLimits applies CPU and memory limits, skipping empty values
+func SetLimits(name, cpu, memory string) error {
+	if cpu != "" {
+		if err := SetCPULimit(name, cpu); err != nil {
+			return err
+		}
+	}
+	if memory != "" {
+		if err := SetMemoryLimit(name, memory); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -45,12 +176,64 @@ func EnableNesting(name string) error {
 
 // Exec runs a command inside a container
 func Exec(name string, args ...string) error {
-	cmdArgs := append([]string{"exec", name, "--"}, args...)
-	output, err := DefaultExecutor.RunCombined(cmdArgs...)
+	_, err := ExecOutput(name, args...)
+	return err
+}
+
+// ExecAsUser runs a command inside a container as user, through a login
+// shell (su -l) so the user's environment and groups apply
+func ExecAsUser(name, user string, args ...string) error {
+	return Exec(name, "su", "-l", user, "-c", ShellJoin(args))
+}
+
+// ShellJoin quotes each argument for safe use in a sh -c command string
+func ShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote single-quotes s unless it only contains shell-safe characters
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@,+%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ExecOutput runs a command inside a container and returns its trimmed stdout
+func ExecOutput(name string, args ...string) (string, error) {
+	cmdArgs := append([]string{"exec", InstanceRef(name), "--"}, args...)
+	output, err := DefaultExecutor.Run(cmdArgs...)
 	if err != nil {
-		return fmt.Errorf("exec failed: %s", string(output))
+		return "", execError(output, err)
 	}
-	return nil
+	return strings.TrimSpace(string(output)), nil
+}
+
+// execError builds an exec failure message, preferring the command's stderr
+func execError(output []byte, err error) error {
+	msg := strings.TrimSpace(string(output))
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		msg = strings.TrimSpace(string(exitErr.Stderr))
+	}
+	if msg == "" {
+		msg = err.Error()
+	}
+	return fmt.Errorf("exec failed: %s", msg)
 }
 
 // ExecScript runs a shell script inside a container
@@ -77,6 +260,68 @@ func SetupUser(containerName, username, password string) error {
 	return ExecScript(containerName, script)
 }
 
+// envBlockStart and envBlockEnd delimit the variables managed in /etc/environment
+const (
+	envBlockStart = "# BEGIN lxc-dev-manager"
+	envBlockEnd   = "# END lxc-dev-manager"
+)
+
+// buildSetEnvScript returns a script that replaces the managed block in
+// /etc/environment with the given variables (sorted for stable output).
+// The new file is written to a temp file and renamed into place atomically.
+func buildSetEnvScript(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	b.WriteString("tmp=$(mktemp /etc/environment.XXXXXX)\n")
+	fmt.Fprintf(&b, "sed '/^%s$/,/^%s$/d' /etc/environment > \"$tmp\" 2>/dev/null || true\n", envBlockStart, envBlockEnd)
+	if len(keys) > 0 {
+		b.WriteString("cat >> \"$tmp\" <<'LXC_DEV_MANAGER_ENV'\n")
+		b.WriteString(envBlockStart + "\n")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s=\"%s\"\n", k, env[k])
+		}
+		b.WriteString(envBlockEnd + "\n")
+		b.WriteString("LXC_DEV_MANAGER_ENV\n")
+	}
+	b.WriteString("chmod 644 \"$tmp\"\n")
+	b.WriteString("mv \"$tmp\" /etc/environment\n")
+	return b.String()
+}
+
+// SetEnv writes environment variables to /etc/environment in the container,
+// replacing any previously managed variables. An empty map clears them.
+func SetEnv(container string, env map[string]string) error {
+	return ExecScript(container, buildSetEnvScript(env))
+}
+
+// buildDeploySSHKeyScript returns a script that adds pubkey to user's
+// ~/.ssh/authorized_keys (once) with the permissions sshd requires
+func buildDeploySSHKeyScript(user, pubkey string) string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "home=$(getent passwd %s | cut -d: -f6)\n", shellQuote(user))
+	b.WriteString("[ -n \"$home\" ] || { echo \"user not found\" >&2; exit 1; }\n")
+	b.WriteString("mkdir -p \"$home/.ssh\"\n")
+	b.WriteString("touch \"$home/.ssh/authorized_keys\"\n")
+	fmt.Fprintf(&b, "grep -qxF %s \"$home/.ssh/authorized_keys\" || echo %s >> \"$home/.ssh/authorized_keys\"\n", shellQuote(pubkey), shellQuote(pubkey))
+	b.WriteString("chmod 700 \"$home/.ssh\"\n")
+	b.WriteString("chmod 600 \"$home/.ssh/authorized_keys\"\n")
+	fmt.Fprintf(&b, "chown -R %s: \"$home/.ssh\"\n", shellQuote(user))
+	return b.String()
+}
+
+// DeploySSHKey authorizes an SSH public key for user in the container.
+// Deploying the same key again is a no-op.
+func DeploySSHKey(container, user, pubkey string) error {
+	return ExecScript(container, buildDeploySSHKeyScript(user, pubkey))
+}
+
 // EnableSSH ensures SSH is installed and running
 func EnableSSH(name string) error {
 	script := `
@@ -93,118 +338,342 @@ func EnableSSH(name string) error {
 	return ExecScript(name, script)
 }
 
-// WaitForReady waits for container to be ready (cloud-init complete)
-func WaitForReady(name string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
+// ReadyStrategy selects how WaitForReady decides a container has finished booting
+type ReadyStrategy string
 
-	for time.Now().Before(deadline) {
-		// Check if cloud-init is done
-		output, err := DefaultExecutor.RunCombined("exec", name, "--", "cloud-init", "status")
-		if err == nil && strings.Contains(string(output), "done") {
-			return nil
+const (
+	// ReadyCloudInit waits for 'cloud-init status' to report done (the default)
+	ReadyCloudInit ReadyStrategy = "cloud-init"
+	// ReadySystemd waits for 'systemctl is-system-running' to report running or degraded
+	ReadySystemd ReadyStrategy = "systemd"
+	// ReadyCustom runs a user-supplied command until it exits 0
+	ReadyCustom ReadyStrategy = "custom"
+)
+
+// WaitForReady waits for container to be ready according to strategy.
+// An empty strategy means ReadyCloudInit; command is only used by ReadyCustom.
+// Returns early with ctx.Err() if ctx is cancelled.
+func WaitForReady(ctx context.Context, name string, strategy ReadyStrategy, command string, timeout time.Duration) error {
+	switch strategy {
+	case "", ReadyCloudInit, ReadySystemd:
+	case ReadyCustom:
+		if command == "" {
+			return fmt.Errorf("ready strategy %q requires a command", ReadyCustom)
 		}
+	default:
+		return fmt.Errorf("unknown ready strategy %q", strategy)
+	}
 
-		// Also check if it's just running (no cloud-init)
-		if strings.Contains(string(output), "not found") {
-			// No cloud-init, assume ready
-			time.Sleep(2 * time.Second)
-			return nil
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		switch strategy {
+		case ReadySystemd:
+			// Exits non-zero when degraded, so only the reported state matters
+			output, _ := DefaultExecutor.RunCombinedContext(ctx, "exec", InstanceRef(name), "--", "systemctl", "is-system-running")
+			state := strings.TrimSpace(string(output))
+			if state == "running" || state == "degraded" {
+				return nil
+			}
+
+		case ReadyCustom:
+			if _, err := DefaultExecutor.RunCombinedContext(ctx, "exec", InstanceRef(name), "--", "sh", "-c", command); err == nil {
+				return nil
+			}
+
+		default:
+			// Check if cloud-init is done
+			output, err := DefaultExecutor.RunCombinedContext(ctx, "exec", InstanceRef(name), "--", "cloud-init", "status")
+			if err == nil && strings.Contains(string(output), "done") {
+				return nil
+			}
+
+			// Also check if it's just running (no cloud-init)
+			if strings.Contains(string(output), "not found") {
+				// No cloud-init, assume ready
+				return sleepContext(ctx, 2*time.Second)
+			}
 		}
 
-		time.Sleep(1 * time.Second)
+		if err := sleepContext(ctx, 1*time.Second); err != nil {
+			if err == context.DeadlineExceeded {
+				return fmt.Errorf("timeout waiting for container to be ready")
+			}
+			return err
+		}
 	}
+}
+
+// sleepContext sleeps for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
 
-	return fmt.Errorf("timeout waiting for container to be ready")
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // Start starts a stopped container
 func Start(name string) error {
-	output, err := DefaultExecutor.RunCombined("start", name)
+	defer invalidateInfo(name)
+	output, err := DefaultExecutor.RunCombined("start", InstanceRef(name))
+	if err != nil {
+		return newError("start container", name, output, err)
+	}
+	return nil
+}
+
+// Freeze pauses all processes in a running container
+func Freeze(name string) error {
+	defer invalidateInfo(name)
+	output, err := DefaultExecutor.RunCombined("pause", InstanceRef(name))
+	if err != nil {
+		return newError("freeze container", name, output, err)
+	}
+	return nil
+}
+
+// Unfreeze resumes a frozen container
+func Unfreeze(name string) error {
+	defer invalidateInfo(name)
+	output, err := DefaultExecutor.RunCombined("start", InstanceRef(name))
 	if err != nil {
-		return fmt.Errorf("failed to start container: %s", string(output))
+		return newError("unfreeze container", name, output, err)
 	}
 	return nil
 }
 
-// Stop stops a running container
-func Stop(name string) error {
-	output, err := DefaultExecutor.RunCombined("stop", name)
+// Stop stops a running container, waiting up to timeout for a clean shutdown
+// before LXC gives up. A zero timeout uses LXC's default; a partial second
+// is rounded up.
+func Stop(name string, timeout time.Duration) error {
+	defer invalidateInfo(name)
+	args := []string{"stop", InstanceRef(name)}
+	if timeout > 0 {
+		args = append(args, "--timeout", strconv.Itoa(int(math.Ceil(timeout.Seconds()))))
+	}
+	output, err := DefaultExecutor.RunCombined(args...)
 	if err != nil {
-		return fmt.Errorf("failed to stop container: %s", string(output))
+		return newError("stop container", name, output, err)
 	}
 	return nil
 }
 
+// StopStateful stops a running container after saving its memory state to
+// disk; the next Start resumes it from that state. Needs CRIU on the host
+// and migration.stateful on the container, otherwise the error unwraps to
+// ErrStatefulUnsupported.
+func StopStateful(name string) error {
+	defer invalidateInfo(name)
+	output, err := DefaultExecutor.RunCombined("stop", InstanceRef(name), "--stateful")
+	if err != nil {
+		return newError("stop container statefully", name, output, err)
+	}
+	return nil
+}
+
+// HasSavedState reports whether a stopped container has memory state saved
+// by StopStateful, so that starting it resumes rather than boots
+func HasSavedState(name string) (bool, error) {
+	raw, err := QueryInstance(name)
+	if err != nil {
+		return false, err
+	}
+	info, err := ParseInstanceInfo(raw)
+	if err != nil {
+		return false, err
+	}
+	return info.Stateful, nil
+}
+
+// Restart stops a running container and starts it again
+func Restart(name string, timeout time.Duration) error {
+	if err := Stop(name, timeout); err != nil {
+		return err
+	}
+	return Start(name)
+}
+
 // Delete removes a container
 func Delete(name string) error {
-	output, err := DefaultExecutor.RunCombined("delete", name, "--force")
+	defer invalidateInfo(name)
+	output, err := DefaultExecutor.RunCombined("delete", InstanceRef(name), "--force")
 	if err != nil {
-		return fmt.Errorf("failed to delete container: %s", string(output))
+		return newError("delete container", name, output, err)
 	}
 	return nil
 }
 
 // Publish creates an image from a container
 func Publish(name, alias string) error {
-	output, err := DefaultExecutor.RunCombined("publish", name, "--alias", alias)
+	if err := validation.ValidateImageAlias(alias); err != nil {
+		return err
+	}
+	output, err := DefaultExecutor.RunCombined(publishArgs(InstanceRef(name), alias)...)
 	if err != nil {
-		return fmt.Errorf("failed to publish container: %s", string(output))
+		return newError("publish container", name, output, err)
 	}
 	return nil
 }
 
 // Snapshot creates a named snapshot of a container
 func Snapshot(container, snapshotName string) error {
-	output, err := DefaultExecutor.RunCombined("snapshot", container, snapshotName)
+	defer invalidateInfo(container)
+	output, err := DefaultExecutor.RunCombined("snapshot", InstanceRef(container), snapshotName)
 	if err != nil {
-		return fmt.Errorf("failed to create snapshot: %s", string(output))
+		return newError("create snapshot", container+"/"+snapshotName, output, err)
 	}
 	return nil
 }
 
 // DeleteSnapshot deletes a named snapshot
 func DeleteSnapshot(container, snapshotName string) error {
-	output, err := DefaultExecutor.RunCombined("delete", container+"/"+snapshotName)
+	defer invalidateInfo(container)
+	output, err := DefaultExecutor.RunCombined("delete", InstanceRef(container)+"/"+snapshotName)
 	if err != nil {
-		return fmt.Errorf("failed to delete snapshot: %s", string(output))
+		return newError("delete snapshot", container+"/"+snapshotName, output, err)
 	}
 	return nil
 }
 
 // Restore restores a container from a snapshot
 func Restore(container, snapshotName string) error {
-	output, err := DefaultExecutor.RunCombined("restore", container, snapshotName)
+	defer invalidateInfo(container)
+	output, err := DefaultExecutor.RunCombined("restore", InstanceRef(container), snapshotName)
 	if err != nil {
-		return fmt.Errorf("failed to restore snapshot: %s", string(output))
+		return newError("restore snapshot", container+"/"+snapshotName, output, err)
 	}
 	return nil
 }
 
 // SnapshotExists checks if a snapshot exists
 func SnapshotExists(container, snapshotName string) bool {
-	_, err := DefaultExecutor.Run("info", container+"/"+snapshotName)
+	_, err := DefaultExecutor.Run("info", InstanceRef(container)+"/"+snapshotName)
 	return err == nil
 }
 
 // Copy creates a clone of an existing container
 func Copy(source, dest string) error {
-	output, err := DefaultExecutor.RunCombined("copy", source, dest)
+	return CopyWithOptions(source, dest, CopyOptions{})
+}
+
+// CopyOptions controls how CopyWithOptions copies a container
+type CopyOptions struct {
+	Snapshot     string // Copy from this snapshot instead of the current state
+	Stateless    bool   // Leave out the running state of a stateful container
+	Refresh      bool   // Only transfer the differences to an existing copy
+	InstanceOnly bool   // Leave out the source's snapshots
+}
+
+// args returns the lxc copy flags for the options
+func (o CopyOptions) args() []string {
+	var args []string
+	if o.Stateless {
+		args = append(args, "--stateless")
+	}
+	if o.Refresh {
+		args = append(args, "--refresh")
+	}
+	if o.InstanceOnly {
+		args = append(args, "--instance-only")
+	}
+	return args
+}
+
+// CopyWithOptions creates a clone of an existing container, passing the
+// options through as lxc copy flags
+func CopyWithOptions(source, dest string, opts CopyOptions) error {
+	from := InstanceRef(source)
+	op, subject := "copy container", source
+	if opts.Snapshot != "" {
+		from += "/" + opts.Snapshot
+		op, subject = "copy from snapshot", source+"/"+opts.Snapshot
+	}
+
+	args := append([]string{"copy", from, InstanceRef(dest)}, opts.args()...)
+	output, err := DefaultExecutor.RunCombined(args...)
 	if err != nil {
-		return fmt.Errorf("failed to copy container: %s", string(output))
+		return newError(op, subject, output, err)
 	}
 	return nil
 }
 
-// CopySnapshot creates a container from a snapshot of another container
-func CopySnapshot(source, snapshotName, dest string) error {
-	snapshotPath := source + "/" + snapshotName
-	output, err := DefaultExecutor.RunCombined("copy", snapshotPath, dest)
+// Rename renames a stopped container; its snapshots move with it
+func Rename(oldName, newName string) error {
+	defer invalidateInfo(oldName)
+	defer invalidateInfo(newName)
+	output, err := DefaultExecutor.RunCombined("rename", InstanceRef(oldName), InstanceRef(newName))
+	if err != nil {
+		return newError("rename container", oldName, output, err)
+	}
+	return nil
+}
+
+// RenameOptions controls how RenameWithOptions moves a container
+type RenameOptions struct {
+	Copy     bool // Copy to the new name and delete the old container, instead of lxc rename
+	Stateful bool // With Copy: copy a running container with its memory state instead of stopping it first
+}
+
+// RenameWithOptions renames a container. Without Copy it is Rename. With
+// Copy, a running container is stopped, copied (snapshots included) with
+// lxc copy --stateless, the copy is started, and the old container is
+// deleted; Stateful copies it while running, keeping its memory state
+// (needs CRIU). A failure after the first step is a *RenameError.
+func RenameWithOptions(oldName, newName string, opts RenameOptions) error {
+	if !opts.Copy {
+		if opts.Stateful {
+			return fmt.Errorf("a stateful rename copies the container: set Copy")
+		}
+		return Rename(oldName, newName)
+	}
+
+	status, err := GetStatus(oldName)
 	if err != nil {
-		return fmt.Errorf("failed to copy from snapshot: %s", string(output))
+		return err
+	}
+	running := status == "RUNNING"
+
+	stage := RenameNotStarted
+	fail := func(err error) error {
+		return &RenameError{OldName: oldName, NewName: newName, Stage: stage, err: err}
+	}
+
+	if running && !opts.Stateful {
+		if err := Stop(oldName, 0); err != nil {
+			return fail(err)
+		}
+		stage = RenameStopped
+	}
+
+	if err := CopyWithOptions(oldName, newName, CopyOptions{Stateless: !opts.Stateful}); err != nil {
+		return fail(err)
+	}
+	stage = RenameCopied
+
+	if running {
+		if err := Start(newName); err != nil {
+			return fail(err)
+		}
+		stage = RenameStarted
+	}
+
+	if err := Delete(oldName); err != nil {
+		return fail(err)
 	}
 	return nil
 }
 
+// CopySnapshot creates a container from a snapshot of another container
+func CopySnapshot(source, snapshotName, dest string) error {
+	return CopyWithOptions(source, dest, CopyOptions{Snapshot: snapshotName})
+}
+
 // DirExists checks if a directory exists in a container
 func DirExists(container, path string) bool {
 	err := Exec(container, "test", "-d", path)
@@ -213,20 +682,40 @@ func DirExists(container, path string) bool {
 
 // FilePush copies a file or directory from host to container
 func FilePush(container, localPath, remotePath string, recursive bool) error {
+	output, err := DefaultExecutor.RunCombined(filePushArgs(container, localPath, remotePath, recursive)...)
+	if err != nil {
+		return pushError(container, remotePath, output, err)
+	}
+	return nil
+}
+
+// FilePushWithProgress is FilePush for large copies: lxc's progress output
+// is streamed to the provided writers as the copy runs.
+// The lxc process is killed if ctx is cancelled.
+func FilePushWithProgress(ctx context.Context, container, localPath, remotePath string, recursive bool, stdout, stderr io.Writer) error {
+	var errOutput bytes.Buffer
+	if err := DefaultExecutor.RunStream(ctx, stdout, io.MultiWriter(stderr, &errOutput), filePushArgs(container, localPath, remotePath, recursive)...); err != nil {
+		return pushError(container, remotePath, errOutput.Bytes(), err)
+	}
+	return nil
+}
+
+func filePushArgs(container, localPath, remotePath string, recursive bool) []string {
 	args := []string{"file", "push"}
 	if recursive {
 		args = append(args, "-r")
 	}
-	args = append(args, localPath, container+"/"+remotePath)
-	output, err := DefaultExecutor.RunCombined(args...)
-	if err != nil {
-		errMsg := strings.TrimSpace(string(output))
-		if strings.Contains(errMsg, "Not Found") || strings.Contains(errMsg, "not found") {
-			return fmt.Errorf("destination path '%s' not found in container (does the directory exist?)", remotePath)
-		}
-		return fmt.Errorf("failed to copy to container: %s", errMsg)
+	return append(args, localPath, InstanceRef(container)+"/"+remotePath)
+}
+
+// pushError explains a failed push, pointing at the usual cause when the
+// destination is missing
+func pushError(container, remotePath string, output []byte, err error) error {
+	lerr := newError("copy to container", container, output, err)
+	if errors.Is(lerr, ErrNotFound) {
+		return fmt.Errorf("destination path '%s' %w in container (does the directory exist?)", remotePath, ErrNotFound)
 	}
-	return nil
+	return lerr
 }
 
 // FilePull copies a file or directory from container to host
@@ -235,18 +724,36 @@ func FilePull(container, remotePath, localPath string, recursive bool) error {
 	if recursive {
 		args = append(args, "-r")
 	}
-	args = append(args, container+"/"+remotePath, localPath)
+	args = append(args, InstanceRef(container)+"/"+remotePath, localPath)
 	output, err := DefaultExecutor.RunCombined(args...)
 	if err != nil {
-		errMsg := strings.TrimSpace(string(output))
-		if strings.Contains(errMsg, "Not Found") || strings.Contains(errMsg, "not found") {
-			return fmt.Errorf("source path '%s' not found in container", remotePath)
+		lerr := newError("copy from container", container, output, err)
+		if errors.Is(lerr, ErrNotFound) {
+			return fmt.Errorf("source path '%s' %w in container", remotePath, ErrNotFound)
 		}
-		return fmt.Errorf("failed to copy from container: %s", errMsg)
+		return lerr
 	}
 	return nil
 }
 
+// CopyToTemp pulls a file or directory from a container into a new temp
+// directory on the host. It returns the local path, named after the remote
+// one, and a cleanup function that removes the temp directory.
+func CopyToTemp(container, remotePath string) (string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "lxc-pull-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	localPath := filepath.Join(tempDir, path.Base(remotePath))
+	if err := FilePull(container, remotePath, localPath, IsDir(container, remotePath)); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return localPath, cleanup, nil
+}
+
 // FileExists checks if a file exists in a container
 func FileExists(container, path string) bool {
 	err := Exec(container, "test", "-e", path)
@@ -261,9 +768,9 @@ func IsDir(container, path string) bool {
 
 // ListSnapshots returns all snapshot names for a container
 func ListSnapshots(container string) ([]string, error) {
-	output, err := DefaultExecutor.Run("query", "/1.0/instances/"+container+"/snapshots")
+	output, err := DefaultExecutor.Run("query", remoteRef()+"/1.0/instances/"+container+"/snapshots")
 	if err != nil {
-		return nil, fmt.Errorf("failed to list snapshots: %v", err)
+		return nil, newError("list snapshots", container, output, err)
 	}
 
 	// Parse JSON array of snapshot paths like ["/1.0/instances/foo/snapshots/snap1"]
@@ -283,39 +790,233 @@ func ListSnapshots(container string) ([]string, error) {
 	return names, nil
 }
 
+// SnapshotInfo is the subset of LXD's snapshot API response we use
+type SnapshotInfo struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	Stateful  bool   `json:"stateful"`
+	Size      int64  `json:"size"` // Bytes; -1 if the storage driver cannot tell
+}
+
+// GetSnapshotInfo queries LXD for a single snapshot's details
+func GetSnapshotInfo(container, snapshotName string) (SnapshotInfo, error) {
+	output, err := DefaultExecutor.Run("query", remoteRef()+"/1.0/instances/"+container+"/snapshots/"+snapshotName)
+	if err != nil {
+		return SnapshotInfo{}, newError("get snapshot info", container+"/"+snapshotName, output, err)
+	}
+
+	var info SnapshotInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return SnapshotInfo{}, fmt.Errorf("failed to parse snapshot info: %v", err)
+	}
+	return info, nil
+}
+
+// QueryInstance returns LXD's raw JSON description of an instance. With
+// recursion=1 the response includes its state (status, network addresses).
+func QueryInstance(container string) (json.RawMessage, error) {
+	output, err := DefaultExecutor.Run("query", remoteRef()+"/1.0/instances/"+container+"?recursion=1")
+	if err != nil {
+		return nil, newError("query instance", container, output, err)
+	}
+	if !json.Valid(output) {
+		return nil, fmt.Errorf("failed to parse instance info: invalid JSON")
+	}
+	return json.RawMessage(output), nil
+}
+
+// InstanceInfo is the subset of LXD's instance API response shown by
+// 'container info'
+type InstanceInfo struct {
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Status    string            `json:"status"`
+	CreatedAt string            `json:"created_at"`
+	Stateful  bool              `json:"stateful"` // Memory state saved by a stateful stop
+	Config    map[string]string `json:"config"`
+	State     *InstanceState    `json:"state"` // Nil if LXD did not include it
+}
+
+// InstanceState is the runtime part of an instance response
+type InstanceState struct {
+	Status  string                     `json:"status"`
+	Network map[string]InstanceNetwork `json:"network"`
+}
+
+// InstanceNetwork is one network interface of a running instance
+type InstanceNetwork struct {
+	Addresses []InstanceAddress `json:"addresses"`
+}
+
+// InstanceAddress is an address assigned to a network interface
+type InstanceAddress struct {
+	Family  string `json:"family"` // "inet" or "inet6"
+	Address string `json:"address"`
+	Scope   string `json:"scope"` // "global", "link" or "local"
+}
+
+// ParseInstanceInfo extracts InstanceInfo from a QueryInstance response
+func ParseInstanceInfo(raw json.RawMessage) (InstanceInfo, error) {
+	var info InstanceInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return InstanceInfo{}, fmt.Errorf("failed to parse instance info: %v", err)
+	}
+	return info, nil
+}
+
+// SnapshotDetail is a snapshot listed by ListSnapshotsWithMetadata
+type SnapshotDetail struct {
+	Name string
+	Info *SnapshotInfo // Nil if LXD could not be queried for this snapshot
+}
+
+// SnapshotCacheTTL is how long ListSnapshotsWithMetadata reuses a container's results
+var SnapshotCacheTTL = 2 * time.Second
+
+// snapshotCache maps an instance reference to its cachedSnapshots
+var snapshotCache sync.Map
+
+type cachedSnapshots struct {
+	snapshots []SnapshotDetail
+	fetched   time.Time
+}
+
+// ListSnapshotsWithMetadata returns a container's snapshots, sorted by name,
+// with the details LXD reports for each. A snapshot that cannot be queried
+// is still listed, without Info. Results are cached for SnapshotCacheTTL.
+func ListSnapshotsWithMetadata(container string) ([]SnapshotDetail, error) {
+	ref := InstanceRef(container)
+	if v, ok := snapshotCache.Load(ref); ok {
+		entry := v.(cachedSnapshots)
+		if time.Since(entry.fetched) < SnapshotCacheTTL {
+			return copySnapshotDetails(entry.snapshots), nil
+		}
+	}
+
+	names, err := ListSnapshots(container)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	snapshots := make([]SnapshotDetail, 0, len(names))
+	for _, name := range names {
+		detail := SnapshotDetail{Name: name}
+		if info, err := GetSnapshotInfo(container, name); err == nil {
+			detail.Info = &info
+		}
+		snapshots = append(snapshots, detail)
+	}
+
+	snapshotCache.Store(ref, cachedSnapshots{snapshots: snapshots, fetched: time.Now()})
+	return copySnapshotDetails(snapshots), nil
+}
+
+// copySnapshotDetails copies details so callers cannot modify cached values
+func copySnapshotDetails(snapshots []SnapshotDetail) []SnapshotDetail {
+	out := make([]SnapshotDetail, len(snapshots))
+	for i, detail := range snapshots {
+		out[i] = detail
+		if detail.Info != nil {
+			info := *detail.Info
+			out[i].Info = &info
+		}
+	}
+	return out
+}
+
 // PublishSnapshotWithProgress publishes a container snapshot as an image,
-// streaming progress output to the provided writers
-func PublishSnapshotWithProgress(container, snapshotName, alias string, stdout, stderr io.Writer) error {
-	source := container
-	if snapshotName != "" {
-		source = container + "/" + snapshotName
+// streaming progress output to the provided writers.
+// The lxc process is killed if ctx is cancelled.
+func PublishSnapshotWithProgress(ctx context.Context, container, snapshotName, alias string, stdout, stderr io.Writer) error {
+	if err := validation.ValidateImageAlias(alias); err != nil {
+		return err
 	}
 
-	cmd := exec.Command("lxc", "publish", source, "--alias", alias)
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
+	source := InstanceRef(container)
+	if snapshotName != "" {
+		source = source + "/" + snapshotName
+	}
 
-	if err := cmd.Run(); err != nil {
+	if err := DefaultExecutor.RunStream(ctx, stdout, stderr, publishArgs(source, alias)...); err != nil {
 		return fmt.Errorf("failed to publish image: %w", err)
 	}
 	return nil
 }
 
+// PullImage copies a remote image such as "ubuntu:24.04" into the image
+// store, with its aliases, refreshing any copy already there
+func PullImage(source string) error {
+	output, err := DefaultExecutor.RunCombined(pullImageArgs(source, "")...)
+	if err != nil {
+		return newError("pull image", source, output, err)
+	}
+	return nil
+}
+
+// PullImageWithProgress downloads a remote image such as "ubuntu:24.04",
+// streaming progress output to the provided writers.
+// The lxc process is killed if ctx is cancelled.
+func PullImageWithProgress(ctx context.Context, source, alias string, stdout, stderr io.Writer) error {
+	if err := DefaultExecutor.RunStream(ctx, stdout, stderr, pullImageArgs(source, alias)...); err != nil {
+		return fmt.Errorf("failed to pull image: %w", err)
+	}
+	return nil
+}
+
+// ExportImageWithProgress writes an image to a file at path, streaming
+// progress output to the provided writers.
+// The lxc process is killed if ctx is cancelled.
+func ExportImageWithProgress(ctx context.Context, alias, path string, stdout, stderr io.Writer) error {
+	if err := validation.ValidateImageName(alias); err != nil {
+		return err
+	}
+
+	if err := DefaultExecutor.RunStream(ctx, stdout, stderr, exportImageArgs(alias, path)...); err != nil {
+		return fmt.Errorf("failed to export image: %w", err)
+	}
+	return nil
+}
+
+// ImportImageWithProgress imports an image file under alias, streaming
+// progress output to the provided writers.
+// The lxc process is killed if ctx is cancelled.
+func ImportImageWithProgress(ctx context.Context, path, alias string, stdout, stderr io.Writer) error {
+	if err := validation.ValidateImageAlias(alias); err != nil {
+		return err
+	}
+
+	if err := DefaultExecutor.RunStream(ctx, stdout, stderr, importImageArgs(path, alias)...); err != nil {
+		return fmt.Errorf("failed to import image: %w", err)
+	}
+	return nil
+}
+
+// ExecStream runs a command inside a container, streaming its output to the
+// provided writers. The lxc process is killed if ctx is cancelled.
+func ExecStream(ctx context.Context, name string, stdout, stderr io.Writer, args ...string) error {
+	cmdArgs := append([]string{"exec", InstanceRef(name), "--"}, args...)
+	if err := DefaultExecutor.RunStream(ctx, stdout, stderr, cmdArgs...); err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+	return nil
+}
+
 // ImageInfo holds information about an image
 type ImageInfo struct {
-	Alias       string
-	Fingerprint string
-	Size        string
-	Description string
-	CreatedAt   string
+	Alias       string `json:"alias"`
+	Fingerprint string `json:"fingerprint"`
+	Size        string `json:"size"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at,omitempty"`
 }
 
 // ListImages returns all local images
 func ListImages(all bool) ([]ImageInfo, error) {
 	// Format: l=alias, f=fingerprint, s=size, d=description
-	output, err := DefaultExecutor.Run("image", "list", "--format=csv", "-c", "lfsd")
+	output, err := DefaultExecutor.Run(imageListArgs("", "--format=csv", "-c", "lfsd")...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list images: %v", err)
+		return nil, newError("list images", "", output, err)
 	}
 
 	var images []ImageInfo
@@ -350,23 +1051,23 @@ func ListImages(all bool) ([]ImageInfo, error) {
 
 // DeleteImage deletes an image by alias or fingerprint
 func DeleteImage(alias string) error {
-	output, err := DefaultExecutor.RunCombined("image", "delete", alias)
+	output, err := DefaultExecutor.RunCombined("image", "delete", imageRef(alias))
 	if err != nil {
-		return fmt.Errorf("failed to delete image: %s", string(output))
+		return newError("delete image", alias, output, err)
 	}
 	return nil
 }
 
 // GetImageFingerprint returns the fingerprint for an image alias
 func GetImageFingerprint(alias string) (string, error) {
-	output, err := DefaultExecutor.Run("image", "list", alias, "--format=csv", "-c", "f")
+	output, err := DefaultExecutor.Run(imageListArgs(alias, "--format=csv", "-c", "f")...)
 	if err != nil {
-		return "", fmt.Errorf("failed to get image fingerprint: %v", err)
+		return "", newError("get image fingerprint", alias, nil, err)
 	}
 
 	fp := strings.TrimSpace(string(output))
 	if fp == "" {
-		return "", fmt.Errorf("image '%s' not found", alias)
+		return "", fmt.Errorf("image '%s' %w", alias, ErrNotFound)
 	}
 
 	// May have multiple lines, take first
@@ -386,17 +1087,17 @@ func RenameImage(oldAlias, newAlias string) error {
 	}
 
 	// Create new alias
-	output, err := DefaultExecutor.RunCombined("image", "alias", "create", newAlias, fp)
+	output, err := DefaultExecutor.RunCombined("image", "alias", "create", imageRef(newAlias), fp)
 	if err != nil {
-		return fmt.Errorf("failed to create new alias: %s", string(output))
+		return newError("create new alias", newAlias, output, err)
 	}
 
 	// Delete old alias
-	output, err = DefaultExecutor.RunCombined("image", "alias", "delete", oldAlias)
+	output, err = DefaultExecutor.RunCombined("image", "alias", "delete", imageRef(oldAlias))
 	if err != nil {
 		// Try to clean up new alias
-		DefaultExecutor.RunCombined("image", "alias", "delete", newAlias)
-		return fmt.Errorf("failed to delete old alias: %s", string(output))
+		DefaultExecutor.RunCombined("image", "alias", "delete", imageRef(newAlias))
+		return newError("delete old alias", oldAlias, output, err)
 	}
 
 	return nil
@@ -408,11 +1109,38 @@ func ImageExists(alias string) bool {
 	return err == nil
 }
 
-// GetIP returns the container's IP address (prefers eth0)
+// GetIP returns the container's IPv4 address (prefers eth0)
 func GetIP(name string) (string, error) {
-	output, err := DefaultExecutor.Run("list", name, "-c4", "-f", "csv")
+	return getAddress(name, "-c4", "get IP")
+}
+
+// GetIPv6 returns the container's IPv6 address (prefers eth0)
+func GetIPv6(name string) (string, error) {
+	return getAddress(name, "-c6", "get IPv6")
+}
+
+// GetAddresses returns the container's IPv4 and IPv6 addresses. Either may
+// be empty; ErrNoIP is returned only if the container has neither.
+func GetAddresses(name string) (v4, v6 string, err error) {
+	v4, err = GetIP(name)
+	if err != nil && !errors.Is(err, ErrNoIP) {
+		return "", "", err
+	}
+	v6, err = GetIPv6(name)
+	if err != nil && !errors.Is(err, ErrNoIP) {
+		return "", "", err
+	}
+	if v4 == "" && v6 == "" {
+		return "", "", ErrNoIP
+	}
+	return v4, v6, nil
+}
+
+// getAddress reads one address column of `lxc list` (-c4 or -c6)
+func getAddress(name, column, op string) (string, error) {
+	output, err := DefaultExecutor.Run("list", InstanceRef(name), column, "-f", "csv")
 	if err != nil {
-		return "", fmt.Errorf("failed to get IP: %v", err)
+		return "", newError(op, name, nil, err)
 	}
 
 	// Output format: "IP1 (iface1)\nIP2 (iface2)\n..." with surrounding quotes
@@ -447,39 +1175,258 @@ func GetIP(name string) (string, error) {
 	}
 
 	if firstIP == "" {
-		return "", fmt.Errorf("container has no IP address")
+		return "", ErrNoIP
 	}
 
 	return firstIP, nil
 }
 
+// IPPollInterval is how often WaitForIP checks for an address
+var IPPollInterval = 500 * time.Millisecond
+
+// WaitForIP polls GetIP every IPPollInterval until the container has an
+// address or timeout elapses. Use it right after Start, while the container's
+// networking comes up. GetIP is always tried at least once, and only
+// ErrNoIP is retried; a failing lxc command is returned at once.
+func WaitForIP(name string, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		ip, err := GetIP(name)
+		if !errors.Is(err, ErrNoIP) {
+			return ip, err
+		}
+		if time.Now().Add(IPPollInterval).After(deadline) {
+			return "", fmt.Errorf("no IP address after %s: %w", timeout, err)
+		}
+		time.Sleep(IPPollInterval)
+	}
+}
+
 // GetStatus returns the container status
 func GetStatus(name string) (string, error) {
-	output, err := DefaultExecutor.Run("list", name, "-cs", "-f", "csv")
+	output, err := DefaultExecutor.Run("list", InstanceRef(name), "-cs", "-f", "csv")
 	if err != nil {
-		return "", fmt.Errorf("failed to get status: %v", err)
+		return "", newError("get status", name, nil, err)
 	}
 	return strings.TrimSpace(string(output)), nil
 }
 
+// Info returns the raw output of `lxc info` for a container
+func Info(name string) (string, error) {
+	output, err := DefaultExecutor.RunCombined("info", InstanceRef(name))
+	if err != nil {
+		return "", newError("get container info", name, output, err)
+	}
+	return string(output), nil
+}
+
+// InfoParseError is returned when `lxc info` output cannot be parsed
+type InfoParseError struct {
+	Container string
+	Reason    string
+}
+
+func (e *InfoParseError) Error() string {
+	return fmt.Sprintf("failed to parse info for container '%s': %s", e.Container, e.Reason)
+}
+
+// ContainerDetails is the structured form of `lxc info` for one container
+type ContainerDetails struct {
+	ContainerInfo
+	Architecture string            `json:"architecture,omitempty"`
+	Created      time.Time         `json:"created"` // Zero if LXD did not report it
+	Snapshots    int               `json:"snapshots"`
+	Fields       map[string]string `json:"-"` // Top-level "Key: value" lines, as printed
+}
+
+// InfoCacheTTL is how long GetInfo reuses a container's parsed `lxc info`
+var InfoCacheTTL = 500 * time.Millisecond
+
+// infoCache maps an instance reference to its cachedInfo
+var infoCache sync.Map
+
+type cachedInfo struct {
+	details ContainerDetails
+	fetched time.Time
+}
+
+// invalidateInfo drops a container's cached details and snapshot list after
+// it changes state
+func invalidateInfo(name string) {
+	infoCache.Delete(InstanceRef(name))
+	snapshotCache.Delete(InstanceRef(name))
+}
+
+// clearInfoCache drops all cached details, e.g. when the executor is replaced
+func clearInfoCache() {
+	for _, cache := range []*sync.Map{&infoCache, &snapshotCache} {
+		cache.Range(func(key, _ any) bool {
+			cache.Delete(key)
+			return true
+		})
+	}
+}
+
+// GetInfo returns status, IP, PID, memory usage, architecture, creation time
+// and snapshot count for a single container. Results are cached for
+// InfoCacheTTL so callers checking the same container in quick succession
+// share one `lxc info` call.
+func GetInfo(name string) (*ContainerDetails, error) {
+	ref := InstanceRef(name)
+	if v, ok := infoCache.Load(ref); ok {
+		entry := v.(cachedInfo)
+		if time.Since(entry.fetched) < InfoCacheTTL {
+			details := entry.details
+			return &details, nil
+		}
+	}
+
+	output, err := Info(name)
+	if err != nil {
+		return nil, err
+	}
+	details, err := ParseInfo(name, output)
+	if err != nil {
+		return nil, err
+	}
+
+	infoCache.Store(ref, cachedInfo{details: details, fetched: time.Now()})
+	return &details, nil
+}
+
+// infoTimeLayouts are the formats LXD uses for the Created field
+var infoTimeLayouts = []string{
+	"2006/01/02 15:04 MST",
+	"2006/01/02 15:04 -0700",
+	time.RFC3339,
+}
+
+// ParseInfo parses `lxc info` output. The top-level "Key: value" lines
+// (Name, Status, Type, Architecture, Created, ...) are kept in Fields; the
+// memory usage ("Memory (current)") and IP (the first global inet address)
+// come from the indented Resources section.
+func ParseInfo(container, output string) (ContainerDetails, error) {
+	fields := make(map[string]string)
+	var details ContainerDetails
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			line = strings.TrimSpace(line)
+			if value, ok := strings.CutPrefix(line, "Memory (current):"); ok && details.Memory == "" {
+				details.Memory = strings.TrimSpace(value)
+			}
+			if value, ok := strings.CutPrefix(line, "inet:"); ok && details.IP == "" && strings.Contains(value, "(global)") {
+				addr := strings.Fields(value)[0]
+				if idx := strings.Index(addr, "/"); idx > 0 {
+					addr = addr[:idx]
+				}
+				details.IP = addr
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			// Section header like "Resources:"
+			continue
+		}
+		fields[strings.TrimSpace(key)] = value
+	}
+
+	if _, ok := fields["Name"]; !ok {
+		return ContainerDetails{}, &InfoParseError{Container: container, Reason: "missing Name field"}
+	}
+
+	details.Name = fields["Name"]
+	details.Status = strings.ToUpper(fields["Status"])
+	details.Architecture = fields["Architecture"]
+	details.Snapshots = countInfoSnapshots(output)
+	details.Fields = fields
+
+	pid := fields["PID"]
+	if pid == "" {
+		pid = fields["Pid"] // older LXD releases
+	}
+	if pid != "" {
+		n, err := strconv.Atoi(pid)
+		if err != nil {
+			return ContainerDetails{}, &InfoParseError{Container: container, Reason: fmt.Sprintf("invalid PID %q", pid)}
+		}
+		details.PID = n
+	}
+
+	if created := fields["Created"]; created != "" {
+		for _, layout := range infoTimeLayouts {
+			if t, err := time.Parse(layout, created); err == nil {
+				details.Created = t
+				break
+			}
+		}
+	}
+	return details, nil
+}
+
+// countInfoSnapshots counts entries in the Snapshots section of `lxc info`.
+// Newer LXD prints a table (one "| name | ..." row per snapshot after the
+// header row); older releases print one indented line per snapshot.
+func countInfoSnapshots(output string) int {
+	count := 0
+	inSection := false
+	seenHeader := false
+
+	for _, line := range strings.Split(output, "\n") {
+		if !inSection {
+			inSection = strings.TrimSpace(line) == "Snapshots:" && line[0] != ' '
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			return count
+		case strings.HasPrefix(line, "+"):
+			// Table border
+		case strings.HasPrefix(line, "|"):
+			if seenHeader {
+				count++
+			}
+			seenHeader = true
+		case line[0] == ' ' || line[0] == '\t':
+			count++
+		default:
+			// Next top-level field
+			return count
+		}
+	}
+	return count
+}
+
 // Exists checks if a container exists
 func Exists(name string) bool {
-	_, err := DefaultExecutor.Run("info", name)
+	_, err := DefaultExecutor.Run("info", InstanceRef(name))
 	return err == nil
 }
 
-// ContainerInfo holds container information
+// ContainerInfo holds container information.
+// PID and Memory are only filled in by GetInfo, for running containers.
 type ContainerInfo struct {
-	Name   string
-	Status string
-	IP     string
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	IP     string `json:"ip"`
+	PID    int    `json:"pid,omitempty"`
+	Memory string `json:"memory,omitempty"`
 }
 
 // ListAll returns all containers with their status and IP
 func ListAll() ([]ContainerInfo, error) {
-	output, err := DefaultExecutor.Run("list", "-c", "ns4", "-f", "csv")
+	output, err := DefaultExecutor.Run(listAllArgs()...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list containers: %v", err)
+		return nil, newError("list containers", "", nil, err)
 	}
 
 	var containers []ContainerInfo
@@ -1,21 +1,173 @@
 package lxc
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func setupMock(t *testing.T) *MockExecutor {
 	t.Helper()
 	mock := NewMockExecutor()
 	SetExecutor(mock)
+	oldIPPollInterval := IPPollInterval
+	IPPollInterval = 0
 	t.Cleanup(func() {
 		ResetExecutor()
+		SetRemote("")
+		IPPollInterval = oldIPPollInterval
 	})
 	return mock
 }
 
+func TestGetIP_FailsFastOnError(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("list dev1 -c4 -f csv", "instance not found")
+
+	if _, err := GetIP("dev1"); err == nil || errors.Is(err, ErrNoIP) {
+		t.Fatalf("expected command error, got %v", err)
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("expected 1 call, got %d", mock.CallCount())
+	}
+}
+
+func TestWaitForIP_StopsOnCommandError(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponses("list dev1 -c4 -f csv",
+		MockResponse{Output: []byte("")},
+		MockResponse{Err: errors.New("connection refused")},
+		MockResponse{Output: []byte("10.0.0.5 (eth0)")},
+	)
+
+	if _, err := WaitForIP("dev1", time.Second); err == nil || errors.Is(err, ErrNoIP) {
+		t.Fatalf("expected command error, got %v", err)
+	}
+	if mock.CallCount() != 2 {
+		t.Errorf("expected 2 calls, got %d", mock.CallCount())
+	}
+}
+
+func TestWaitForIP_PollsUntilAddress(t *testing.T) {
+	mock := setupMock(t)
+	oldInterval := IPPollInterval
+	IPPollInterval = time.Millisecond
+	t.Cleanup(func() { IPPollInterval = oldInterval })
+	mock.SetOutputs("list dev1 -c4 -f csv", "", "", "10.0.0.5 (eth0)")
+
+	ip, err := WaitForIP("dev1", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ip != "10.0.0.5" {
+		t.Errorf("ip = %q, want 10.0.0.5", ip)
+	}
+	if mock.CallCount() != 3 {
+		t.Errorf("expected 3 calls, got %d", mock.CallCount())
+	}
+}
+
+func TestWaitForIP_Elapses(t *testing.T) {
+	mock := setupMock(t)
+	oldInterval := IPPollInterval
+	IPPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { IPPollInterval = oldInterval })
+	mock.SetOutput("list dev1 -c4 -f csv", "")
+
+	start := time.Now()
+	_, err := WaitForIP("dev1", 30*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if !strings.Contains(err.Error(), "no IP address after 30ms") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, expected to stop near the timeout", elapsed)
+	}
+	if mock.CallCount() < 2 {
+		t.Errorf("expected several polls, got %d", mock.CallCount())
+	}
+}
+
+func TestWaitForIP_ZeroTriesOnce(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -c4 -f csv", "")
+
+	if _, err := WaitForIP("dev1", 0); err == nil {
+		t.Fatal("expected error")
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("expected 1 call, got %d", mock.CallCount())
+	}
+}
+
+func TestMockExecutor_SetOutputsInOrder(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutputs("list dev1 -cs", "STOPPED", "RUNNING")
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		out, err := mock.Run("list", "dev1", "-cs")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, string(out))
+	}
+
+	// The last output repeats once the queue is exhausted
+	if strings.Join(got, ",") != "STOPPED,RUNNING,RUNNING" {
+		t.Errorf("got %v, want STOPPED,RUNNING,RUNNING", got)
+	}
+}
+
+func TestMockExecutor_SetResponsesMixesErrors(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetResponses("start",
+		MockResponse{Err: errors.New("busy")},
+		MockResponse{Output: []byte("ok")},
+	)
+
+	if _, err := mock.Run("start", "dev1"); err == nil || err.Error() != "busy" {
+		t.Errorf("first call: expected busy error, got %v", err)
+	}
+	out, err := mock.Run("start", "dev1")
+	if err != nil || string(out) != "ok" {
+		t.Errorf("second call: got %q, %v", out, err)
+	}
+}
+
+func TestMockExecutor_SetOutputReplacesQueue(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutputs("status", "a", "b")
+	mock.SetOutput("status", "fixed")
+
+	for i := 0; i < 2; i++ {
+		out, _ := mock.Run("status")
+		if string(out) != "fixed" {
+			t.Errorf("call %d: got %q, want fixed", i, out)
+		}
+	}
+}
+
+func TestMockExecutor_SequenceExactBeforePrefix(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutputs("list", "prefix")
+	mock.SetOutputs("list dev1", "exact")
+
+	out, _ := mock.Run("list", "dev1")
+	if string(out) != "exact" {
+		t.Errorf("got %q, want exact", out)
+	}
+}
+
 func TestGetIP_ParsesOutput(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetOutput("list dev1 -c4 -f csv", "10.10.10.45 (eth0)")
@@ -54,6 +206,73 @@ func TestGetIP_MultipleIPs(t *testing.T) {
 	}
 }
 
+func TestGetIPv6_ParsesOutput(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -c6 -f csv", "\"fd42:1::5 (eth1)\nfd42:abcd::216:3eff:fe4b:7c1d (eth0)\"")
+
+	ip, err := GetIPv6("dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Should prefer eth0
+	if ip != "fd42:abcd::216:3eff:fe4b:7c1d" {
+		t.Errorf("expected eth0 address, got %s", ip)
+	}
+}
+
+func TestGetIPv6_NoIP(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -c6 -f csv", "")
+
+	if _, err := GetIPv6("dev1"); !errors.Is(err, ErrNoIP) {
+		t.Errorf("expected ErrNoIP, got %v", err)
+	}
+}
+
+func TestGetAddresses(t *testing.T) {
+	tests := []struct {
+		name   string
+		v4Out  string
+		v6Out  string
+		wantV4 string
+		wantV6 string
+	}{
+		{"dual stack", "10.0.0.5 (eth0)", "fd42::5 (eth0)", "10.0.0.5", "fd42::5"},
+		{"IPv4 only", "10.0.0.5 (eth0)", "", "10.0.0.5", ""},
+		{"IPv6 only", "", "fd42::5 (eth0)", "", "fd42::5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := setupMock(t)
+			mock.SetOutput("list dev1 -c4 -f csv", tt.v4Out)
+			mock.SetOutput("list dev1 -c6 -f csv", tt.v6Out)
+
+			v4, v6, err := GetAddresses("dev1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v4 != tt.wantV4 || v6 != tt.wantV6 {
+				t.Errorf("got (%q, %q), want (%q, %q)", v4, v6, tt.wantV4, tt.wantV6)
+			}
+		})
+	}
+}
+
+func TestGetAddresses_Errors(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -c4 -f csv", "")
+	mock.SetOutput("list dev1 -c6 -f csv", "")
+	mock.SetError("list dev2 -c4 -f csv", "container not found")
+
+	if _, _, err := GetAddresses("dev1"); !errors.Is(err, ErrNoIP) {
+		t.Errorf("expected ErrNoIP without any address, got %v", err)
+	}
+	if _, _, err := GetAddresses("dev2"); err == nil || errors.Is(err, ErrNoIP) {
+		t.Errorf("expected command error, got %v", err)
+	}
+}
+
 func TestGetIP_CommandError(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetError("list dev1 -c4 -f csv", "container not found")
@@ -189,6 +408,109 @@ func TestLaunch_Error(t *testing.T) {
 	}
 }
 
+// launchCalls counts the launch attempts recorded by the mock
+func launchCalls(mock *MockExecutor) int {
+	n := 0
+	for _, call := range mock.Calls {
+		if len(call.Args) > 0 && call.Args[0] == "launch" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLaunchWithRetry_TransientThenSuccess(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info dev1", "Error: Instance not found")
+	mock.SetResponses("launch ubuntu:24.04 dev1",
+		MockResponse{Output: []byte("Error: Failed getting remote image info: Get \"https://cloud-images.ubuntu.com\": net/http: TLS handshake timeout"), Err: errors.New("exit status 1")},
+		MockResponse{Output: []byte("Error: dial tcp 10.0.0.1:8443: connect: connection refused"), Err: errors.New("exit status 1")},
+		MockResponse{Output: []byte("Creating dev1")},
+	)
+
+	if err := LaunchWithRetry("dev1", "ubuntu:24.04", 3, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := launchCalls(mock); n != 3 {
+		t.Errorf("expected 3 launch attempts, got %d", n)
+	}
+}
+
+func TestLaunchWithRetry_PermanentErrorNotRetried(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info dev1", "Error: Instance not found")
+	mock.SetResponse("launch ubuntu:24.04 dev1", []byte("Error: Image not found"), errors.New("exit status 1"))
+
+	err := LaunchWithRetry("dev1", "ubuntu:24.04", 3, 0)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if n := launchCalls(mock); n != 1 {
+		t.Errorf("expected 1 launch attempt, got %d", n)
+	}
+}
+
+func TestLaunchWithRetry_NetworkConfigErrorNotRetried(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info dev1", "Error: Instance not found")
+	mock.SetResponse("launch ubuntu:24.04 dev1", []byte("Error: Failed loading network \"lxdbr1\": Network not found"), errors.New("exit status 1"))
+
+	if err := LaunchWithRetry("dev1", "ubuntu:24.04", 3, 0); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := launchCalls(mock); n != 1 {
+		t.Errorf("expected 1 launch attempt, got %d", n)
+	}
+}
+
+func TestLaunchWithRetry_FailsAfterCreate(t *testing.T) {
+	mock := setupMock(t)
+	// The instance was created before the start failed
+	mock.SetOutput("info dev1", "Name: dev1\nStatus: STOPPED")
+	mock.SetResponses("launch ubuntu:24.04 dev1",
+		MockResponse{Output: []byte("Error: Failed to start device \"eth0\": dial unix /run/dnsmasq.sock: connect: connection refused"), Err: errors.New("exit status 1")},
+		MockResponse{Output: []byte("Error: Instance already exists"), Err: errors.New("exit status 1")},
+	)
+
+	err := LaunchWithRetry("dev1", "ubuntu:24.04", 3, 0)
+	if err == nil || !strings.Contains(err.Error(), "Failed to start device") {
+		t.Errorf("expected the original start error, got %v", err)
+	}
+	if errors.Is(err, ErrAlreadyExists) {
+		t.Error("the retry's already-exists error should not replace the cause")
+	}
+	if n := launchCalls(mock); n != 1 {
+		t.Errorf("expected 1 launch attempt, got %d", n)
+	}
+}
+
+func TestLaunchWithRetry_GivesUp(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info dev1", "Error: Instance not found")
+	mock.SetResponse("launch ubuntu:24.04 dev1", []byte("Error: network is unreachable"), errors.New("exit status 1"))
+
+	err := LaunchWithRetry("dev1", "ubuntu:24.04", 3, 0)
+	if err == nil || !strings.Contains(err.Error(), "network is unreachable") {
+		t.Errorf("expected the last error, got %v", err)
+	}
+	if n := launchCalls(mock); n != 3 {
+		t.Errorf("expected 3 launch attempts, got %d", n)
+	}
+}
+
+func TestLaunch_NoRetry(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info dev1", "Error: Instance not found")
+	mock.SetResponse("launch ubuntu:24.04 dev1", []byte("Error: connection refused"), errors.New("exit status 1"))
+
+	if err := Launch("dev1", "ubuntu:24.04"); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := launchCalls(mock); n != 1 {
+		t.Errorf("expected 1 launch attempt, got %d", n)
+	}
+}
+
 func TestStart_Success(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetOutput("start dev1", "")
@@ -217,7 +539,7 @@ func TestStop_Success(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetOutput("stop dev1", "")
 
-	err := Stop("dev1")
+	err := Stop("dev1", 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -231,12 +553,72 @@ func TestStop_Error(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetError("stop dev1", "container not found")
 
-	err := Stop("dev1")
+	err := Stop("dev1", 0)
 	if err == nil {
 		t.Fatal("expected error")
 	}
 }
 
+func TestStop_Timeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    string
+	}{
+		{"zero uses the lxc default", 0, "stop dev1"},
+		{"whole seconds", 45 * time.Second, "stop dev1 --timeout 45"},
+		{"partial second rounds up", 1500 * time.Millisecond, "stop dev1 --timeout 2"},
+		{"sub-second rounds up", 200 * time.Millisecond, "stop dev1 --timeout 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := setupMock(t)
+			mock.SetOutput("stop dev1", "")
+
+			if err := Stop("dev1", tt.timeout); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(mock.LastCall().Args, " "); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRestart_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("stop dev1", "")
+	mock.SetOutput("start dev1", "")
+
+	if err := Restart("dev1", 30*time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := mock.Calls
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if strings.Join(calls[0].Args, " ") != "stop dev1 --timeout 30" {
+		t.Errorf("first call = %v, want stop", calls[0])
+	}
+	if strings.Join(calls[1].Args, " ") != "start dev1" {
+		t.Errorf("second call = %v, want start", calls[1])
+	}
+}
+
+func TestRestart_StopError(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("stop dev1", "timed out")
+
+	if err := Restart("dev1", 30*time.Second); err == nil {
+		t.Fatal("expected error")
+	}
+	if mock.HasCall("start", "dev1") {
+		t.Error("should not start after failed stop")
+	}
+}
+
 func TestDelete_Success(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetOutput("delete dev1 --force", "")
@@ -285,6 +667,17 @@ func TestPublish_Error(t *testing.T) {
 	}
 }
 
+func TestPublish_InvalidAlias(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := Publish("dev1", "my image"); err == nil {
+		t.Fatal("expected error for invalid alias")
+	}
+	if mock.CallCount() != 0 {
+		t.Errorf("publish should not run for an invalid alias, got %v", mock.Calls)
+	}
+}
+
 func TestConfigSet_Success(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetOutput("config set dev1 security.nesting true", "")
@@ -299,6 +692,96 @@ func TestConfigSet_Success(t *testing.T) {
 	}
 }
 
+func TestAddProfile_Success(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := AddProfile("dev1", "docker"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("profile", "add", "dev1", "docker") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestAddProfile_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("profile add dev1 gpu", "Profile not found")
+
+	err := AddProfile("dev1", "gpu")
+	if err == nil || !strings.Contains(err.Error(), "failed to add profile gpu") {
+		t.Errorf("expected add profile error, got %v", err)
+	}
+}
+
+func TestAddDevice_Success(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := AddDevice("dev1", "mount-home-dev-app", "/home/me/app", "/home/dev/app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("config", "device", "add", "dev1", "mount-home-dev-app", "disk", "source=/home/me/app", "path=/home/dev/app") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestAddDevice_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("config device add dev1", "The device already exists")
+
+	err := AddDevice("dev1", "mount-app", "/src", "/app")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "failed to add device mount-app") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMountDeviceName(t *testing.T) {
+	tests := map[string]string{
+		"/home/dev/app":  "mount-home-dev-app",
+		"/home/dev/app/": "mount-home-dev-app",
+		"/data":          "mount-data",
+	}
+	for target, want := range tests {
+		if got := MountDeviceName(target); got != want {
+			t.Errorf("MountDeviceName(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestMount_Success(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := Mount("dev1", "/home/me/app", "/home/dev/app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("config", "device", "add", "dev1", "mount-home-dev-app", "disk", "source=/home/me/app", "path=/home/dev/app") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestUnmount_Success(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := Unmount("dev1", "mount-home-dev-app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("config", "device", "remove", "dev1", "mount-home-dev-app") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestUnmount_NotFound(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("config device remove dev1", []byte("Error: Device doesn't exist"), errors.New("exit status 1"))
+
+	err := Unmount("dev1", "mount-app")
+	if err == nil || !strings.Contains(err.Error(), "failed to remove device mount-app") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestEnableNesting_Success(t *testing.T) {
 	mock := setupMock(t)
 	// All config commands succeed
@@ -349,64 +832,195 @@ func TestExec_Error(t *testing.T) {
 	}
 }
 
-func TestMockExecutor_CallTracking(t *testing.T) {
-	mock := NewMockExecutor()
-
-	mock.Run("info", "dev1")
-	mock.Run("list", "-c", "ns4")
-	mock.RunCombined("start", "dev1")
+func TestExecAsUser_Success(t *testing.T) {
+	mock := setupMock(t)
 
-	if mock.CallCount() != 3 {
-		t.Errorf("expected 3 calls, got %d", mock.CallCount())
+	err := ExecAsUser("dev1", "dev", "npm", "install")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	last := mock.LastCall()
-	if len(last.Args) != 2 || last.Args[0] != "start" {
-		t.Errorf("unexpected last call: %v", last)
+	if !mock.HasCall("exec", "dev1", "--", "su", "-l", "dev", "-c", "npm install") {
+		t.Errorf("expected su -l command, got %v", mock.Calls)
 	}
+}
 
-	if !mock.HasCall("info", "dev1") {
-		t.Error("expected HasCall to find 'info dev1'")
+func TestExecAsUser_QuotesArguments(t *testing.T) {
+	mock := setupMock(t)
+
+	err := ExecAsUser("dev1", "dev", "echo", "a b", "it's")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if mock.HasCall("nonexistent", "command") {
-		t.Error("HasCall should return false for non-existent call")
+	if !mock.HasCall("exec", "dev1", "--", "su", "-l", "dev", "-c", `echo 'a b' 'it'\''s'`) {
+		t.Errorf("expected quoted arguments, got %v", mock.Calls)
 	}
 }
 
-func TestMockExecutor_Reset(t *testing.T) {
-	mock := NewMockExecutor()
-	mock.SetOutput("test", "output")
-	mock.Run("test")
-
-	mock.Reset()
+func TestExecAsUser_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.DefaultResponse = MockResponse{Err: errors.New("command failed")}
 
-	if mock.CallCount() != 0 {
-		t.Error("calls should be cleared after reset")
-	}
-	if len(mock.Responses) != 0 {
-		t.Error("responses should be cleared after reset")
+	err := ExecAsUser("dev1", "dev", "false")
+	if err == nil {
+		t.Fatal("expected error")
 	}
 }
 
-// Tests for Snapshot function
-func TestSnapshot_Success(t *testing.T) {
+func TestExecScript_RunsCommandsAsOneScript(t *testing.T) {
 	mock := setupMock(t)
-	mock.SetOutput("snapshot dev1 snap1", "")
+	script := strings.Join([]string{"apt-get update", "apt-get install -y git"}, "\n")
 
-	err := Snapshot("dev1", "snap1")
-	if err != nil {
+	if err := ExecScript("dev1", script); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if !mock.HasCall("snapshot", "dev1", "snap1") {
-		t.Error("expected snapshot command to be called")
+	if !mock.HasCall("exec", "dev1", "--", "bash", "-c", "apt-get update\napt-get install -y git") {
+		t.Errorf("expected commands joined into one bash -c script, got %v", mock.Calls)
 	}
 }
 
-func TestSnapshot_Error(t *testing.T) {
+func TestCopyToTemp_File(t *testing.T) {
 	mock := setupMock(t)
-	mock.SetError("snapshot dev1 snap1", "container not found")
+	mock.SetError("exec dev1 -- test -d /etc/hosts", "not a directory")
+	mock.SetCallback("file pull", func(args []string) {
+		os.WriteFile(args[len(args)-1], []byte("127.0.0.1 localhost"), 0644)
+	})
+
+	localPath, cleanup, err := CopyToTemp("dev1", "/etc/hosts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if filepath.Base(localPath) != "hosts" {
+		t.Errorf("expected local file named after the remote one, got %s", localPath)
+	}
+	if !mock.HasCall("file", "pull", "dev1//etc/hosts", localPath) {
+		t.Errorf("expected file pull into the temp dir, got %v", mock.Calls)
+	}
+	if data, err := os.ReadFile(localPath); err != nil || string(data) != "127.0.0.1 localhost" {
+		t.Errorf("expected pulled content, got %q (%v)", data, err)
+	}
+
+	cleanup()
+	if _, err := os.Stat(filepath.Dir(localPath)); !os.IsNotExist(err) {
+		t.Errorf("cleanup should remove the temp directory, got %v", err)
+	}
+}
+
+func TestCopyToTemp_Directory(t *testing.T) {
+	mock := setupMock(t)
+
+	localPath, cleanup, err := CopyToTemp("dev1", "/home/dev/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cleanup()
+
+	if !mock.HasCall("file", "pull", "-r", "dev1//home/dev/app", localPath) {
+		t.Errorf("expected recursive pull for a directory, got %v", mock.Calls)
+	}
+}
+
+func TestCopyToTemp_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("exec dev1 -- test -d /missing", "not a directory")
+	var pulledTo string
+	mock.SetCallback("file pull", func(args []string) {
+		pulledTo = args[len(args)-1]
+	})
+	mock.SetError("file pull", "Error: not found")
+
+	localPath, cleanup, err := CopyToTemp("dev1", "/missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if localPath != "" || cleanup != nil {
+		t.Error("expected no path or cleanup on error")
+	}
+	if _, err := os.Stat(filepath.Dir(pulledTo)); !os.IsNotExist(err) {
+		t.Errorf("temp directory should be removed on error, got %v", err)
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"ls", "-la"}, "ls -la"},
+		{[]string{"echo", "a b"}, "echo 'a b'"},
+		{[]string{"echo", ""}, "echo ''"},
+		{[]string{"echo", "it's"}, `echo 'it'\''s'`},
+		{[]string{"cd", "/tmp/x-1"}, "cd /tmp/x-1"},
+	}
+
+	for _, tt := range tests {
+		if got := ShellJoin(tt.args); got != tt.want {
+			t.Errorf("ShellJoin(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestMockExecutor_CallTracking(t *testing.T) {
+	mock := NewMockExecutor()
+
+	mock.Run("info", "dev1")
+	mock.Run("list", "-c", "ns4")
+	mock.RunCombined("start", "dev1")
+
+	if mock.CallCount() != 3 {
+		t.Errorf("expected 3 calls, got %d", mock.CallCount())
+	}
+
+	last := mock.LastCall()
+	if len(last.Args) != 2 || last.Args[0] != "start" {
+		t.Errorf("unexpected last call: %v", last)
+	}
+
+	if !mock.HasCall("info", "dev1") {
+		t.Error("expected HasCall to find 'info dev1'")
+	}
+
+	if mock.HasCall("nonexistent", "command") {
+		t.Error("HasCall should return false for non-existent call")
+	}
+}
+
+func TestMockExecutor_Reset(t *testing.T) {
+	mock := NewMockExecutor()
+	mock.SetOutput("test", "output")
+	mock.Run("test")
+
+	mock.Reset()
+
+	if mock.CallCount() != 0 {
+		t.Error("calls should be cleared after reset")
+	}
+	if len(mock.Responses) != 0 {
+		t.Error("responses should be cleared after reset")
+	}
+}
+
+// Tests for Snapshot function
+func TestSnapshot_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("snapshot dev1 snap1", "")
+
+	err := Snapshot("dev1", "snap1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !mock.HasCall("snapshot", "dev1", "snap1") {
+		t.Error("expected snapshot command to be called")
+	}
+}
+
+func TestSnapshot_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("snapshot dev1 snap1", "container not found")
 
 	err := Snapshot("dev1", "snap1")
 	if err == nil {
@@ -635,6 +1249,137 @@ func TestGetImageFingerprint_Error(t *testing.T) {
 }
 
 // Tests for RenameImage function
+func TestRename_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("rename dev1 api", "")
+
+	if err := Rename("dev1", "api"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !mock.HasCall("rename", "dev1", "api") {
+		t.Error("expected rename command")
+	}
+}
+
+func TestRename_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("rename dev1 api", "Instance is running")
+
+	err := Rename("dev1", "api")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "failed to rename container") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRenameWithOptions_Copy(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -cs -f csv", "RUNNING")
+
+	if err := RenameWithOptions("dev1", "api", RenameOptions{Copy: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"stop dev1", "copy dev1 api --stateless", "start api", "delete dev1 --force"}
+	var got []string
+	for _, c := range mock.Calls {
+		if c.Args[0] != "list" {
+			got = append(got, strings.Join(c.Args, " "))
+		}
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRenameWithOptions_CopyStopped(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -cs -f csv", "STOPPED")
+
+	if err := RenameWithOptions("dev1", "api", RenameOptions{Copy: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.HasCallPrefix("stop") || mock.HasCallPrefix("start") {
+		t.Errorf("a stopped container should be copied as is, got calls: %v", mock.Calls)
+	}
+	if !mock.HasCall("delete", "dev1", "--force") {
+		t.Error("expected the old container to be deleted")
+	}
+}
+
+func TestRenameWithOptions_Stateful(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -cs -f csv", "RUNNING")
+
+	if err := RenameWithOptions("dev1", "api", RenameOptions{Copy: true, Stateful: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.HasCallPrefix("stop") {
+		t.Error("a stateful rename should not stop the container first")
+	}
+	if !mock.HasCall("copy", "dev1", "api") {
+		t.Errorf("expected copy without --stateless, got calls: %v", mock.Calls)
+	}
+}
+
+func TestRenameWithOptions_StatefulNeedsCopy(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := RenameWithOptions("dev1", "api", RenameOptions{Stateful: true}); err == nil {
+		t.Fatal("expected error")
+	}
+	if mock.CallCount() != 0 {
+		t.Errorf("expected no lxc calls, got %v", mock.Calls)
+	}
+}
+
+func TestRenameWithOptions_PartialFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		failing string
+		stage   RenameStage
+	}{
+		{"stop fails", "stop dev1", RenameNotStarted},
+		{"copy fails", "copy dev1 api", RenameStopped},
+		{"start fails", "start api", RenameCopied},
+		{"delete fails", "delete dev1", RenameStarted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := setupMock(t)
+			mock.SetOutput("list dev1 -cs -f csv", "RUNNING")
+			mock.SetError(tt.failing, "boom")
+
+			err := RenameWithOptions("dev1", "api", RenameOptions{Copy: true})
+			var rerr *RenameError
+			if !errors.As(err, &rerr) {
+				t.Fatalf("expected *RenameError, got %T: %v", err, err)
+			}
+			if rerr.Stage != tt.stage {
+				t.Errorf("stage = %v, want %v", rerr.Stage, tt.stage)
+			}
+			if !strings.Contains(err.Error(), tt.stage.String()) {
+				t.Errorf("error should describe the stage: %v", err)
+			}
+		})
+	}
+}
+
+func TestRenameWithOptions_StatefulUnsupported(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -cs -f csv", "RUNNING")
+	mock.SetResponse("copy dev1 api", []byte("Error: Unable to perform container live migration. CRIU isn't installed"), errors.New("exit status 1"))
+
+	err := RenameWithOptions("dev1", "api", RenameOptions{Copy: true, Stateful: true})
+	if !errors.Is(err, ErrStatefulUnsupported) {
+		t.Errorf("expected ErrStatefulUnsupported, got %v", err)
+	}
+}
+
 func TestRenameImage_Success(t *testing.T) {
 	mock := setupMock(t)
 	mock.SetOutput("image list old-name --format=csv -c f", "abc123def456")
@@ -826,3 +1571,1394 @@ func TestListSnapshots_InvalidJSON(t *testing.T) {
 		t.Fatal("expected error for invalid JSON")
 	}
 }
+
+func TestWaitForReady_Done(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- cloud-init status", "status: done")
+
+	if err := WaitForReady(context.Background(), "dev1", "", "", 5*time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWaitForReady_Cancelled(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- cloud-init status", "status: running")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := WaitForReady(ctx, "dev1", "", "", 5*time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWaitForReady_Timeout(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- cloud-init status", "status: running")
+
+	err := WaitForReady(context.Background(), "dev1", "", "", 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestWaitForReady_Systemd(t *testing.T) {
+	for _, state := range []string{"running", "degraded"} {
+		t.Run(state, func(t *testing.T) {
+			mock := setupMock(t)
+			mock.SetResponse("exec dev1 -- systemctl is-system-running", []byte(state+"\n"), nil)
+
+			if err := WaitForReady(context.Background(), "dev1", ReadySystemd, "", 5*time.Second); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWaitForReady_SystemdStarting(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- systemctl is-system-running", "starting")
+
+	err := WaitForReady(context.Background(), "dev1", ReadySystemd, "", 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestWaitForReady_Custom(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- sh -c curl -sf localhost:8000/health", "")
+
+	if err := WaitForReady(context.Background(), "dev1", ReadyCustom, "curl -sf localhost:8000/health", 5*time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("exec", "dev1", "--", "sh", "-c", "curl -sf localhost:8000/health") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestWaitForReady_CustomFailing(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("exec dev1 -- sh -c", "exit status 7")
+
+	err := WaitForReady(context.Background(), "dev1", ReadyCustom, "false", 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestWaitForReady_InvalidStrategy(t *testing.T) {
+	setupMock(t)
+
+	if err := WaitForReady(context.Background(), "dev1", ReadyCustom, "", time.Second); err == nil {
+		t.Error("expected error for custom strategy without a command")
+	}
+	if err := WaitForReady(context.Background(), "dev1", "upstart", "", time.Second); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestMockExecutor_RunContextCancelled(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list", "ok")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := mock.RunContext(ctx, "list")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !mock.HasCall("list") {
+		t.Error("expected call to be recorded")
+	}
+}
+
+func TestMockExecutor_WithDelay(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list", "ok")
+	mock.WithDelay("list", 20*time.Millisecond)
+
+	start := time.Now()
+	out, err := mock.Run("list", "--format", "csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "ok" {
+		t.Errorf("expected 'ok', got %q", out)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected call to take at least 20ms, took %s", elapsed)
+	}
+
+	start = time.Now()
+	mock.Run("info", "dev1")
+	if elapsed := time.Since(start); elapsed >= 20*time.Millisecond {
+		t.Errorf("expected non-matching call to return immediately, took %s", elapsed)
+	}
+}
+
+func TestMockExecutor_WithDelayCancelled(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list", "ok")
+	mock.WithDelay("list", time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := mock.RunContext(ctx, "list")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if !mock.HasCall("list") {
+		t.Error("expected call to be recorded")
+	}
+}
+
+func TestWaitForReady_SlowCloudInitTimesOut(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- cloud-init status", "status: done")
+	mock.WithDelay("exec dev1 -- cloud-init status", time.Minute)
+
+	start := time.Now()
+	err := WaitForReady(context.Background(), "dev1", "", "", 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected WaitForReady to stop at its deadline, took %s", elapsed)
+	}
+}
+
+func TestWaitForReady_NeverDoneTimesOut(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- cloud-init status", "status: running")
+	mock.WithDelay("exec dev1 -- cloud-init status", 5*time.Millisecond)
+
+	err := WaitForReady(context.Background(), "dev1", "", "", 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+	if mock.CallCount() == 0 {
+		t.Error("expected cloud-init to be polled")
+	}
+}
+
+func TestWaitForReady_SlowCloudInitWithinDeadline(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- cloud-init status", "status: done")
+	mock.WithDelay("exec dev1 -- cloud-init status", 10*time.Millisecond)
+
+	if err := WaitForReady(context.Background(), "dev1", "", "", 5*time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRemote_QualifiesInstanceNames(t *testing.T) {
+	mock := setupMock(t)
+	SetRemote("lab")
+	mock.SetOutput("list lab:proj-dev1 -cs -f csv", "RUNNING")
+	mock.SetOutput("info lab:proj-dev1", "Name: proj-dev1")
+
+	status, err := GetStatus("proj-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != "RUNNING" {
+		t.Errorf("expected RUNNING, got %s", status)
+	}
+	if !Exists("proj-dev1") {
+		t.Error("expected container to exist on remote")
+	}
+
+	Start("proj-dev1")
+	if !mock.HasCall("start", "lab:proj-dev1") {
+		t.Error("expected start to target the remote")
+	}
+}
+
+func TestRemote_Launch(t *testing.T) {
+	mock := setupMock(t)
+	SetRemote("lab")
+
+	// Image with an explicit remote is left alone
+	Launch("dev1", "ubuntu:24.04")
+	if !mock.HasCall("launch", "ubuntu:24.04", "lab:dev1") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+
+	// Local image alias is resolved on the configured remote
+	Launch("dev2", "my-base")
+	if !mock.HasCall("launch", "lab:my-base", "lab:dev2") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestRemote_ListAllAndImages(t *testing.T) {
+	mock := setupMock(t)
+	SetRemote("lab")
+
+	ListAll()
+	if !mock.HasCall("list", "lab:", "-c", "ns4", "-f", "csv") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+
+	ListImages(false)
+	if !mock.HasCall("image", "list", "lab:", "--format=csv", "-c", "lfsd") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+
+	Publish("dev1", "my-image")
+	if !mock.HasCall("publish", "lab:dev1", "lab:", "--alias", "my-image") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestPullImageArgs(t *testing.T) {
+	setupMock(t)
+
+	tests := []struct {
+		name   string
+		remote string
+		alias  string
+		want   string
+	}{
+		{"local store", "", "", "image copy ubuntu:24.04 local: --copy-aliases"},
+		{"with alias", "", "noble", "image copy ubuntu:24.04 local: --copy-aliases --alias noble"},
+		{"project remote", "lab", "", "image copy ubuntu:24.04 lab: --copy-aliases"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRemote(tt.remote)
+			t.Cleanup(func() { SetRemote("") })
+
+			got := strings.Join(pullImageArgs("ubuntu:24.04", tt.alias), " ")
+			if got != tt.want {
+				t.Errorf("pullImageArgs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullImage_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("image copy ubuntu:24.04 local: --copy-aliases", "")
+
+	if err := PullImage("ubuntu:24.04"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("image", "copy", "ubuntu:24.04", "local:", "--copy-aliases") {
+		t.Error("expected image copy to the local store")
+	}
+}
+
+func TestPullImage_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("image copy ubuntu:24.04 local: --copy-aliases", "remote unreachable")
+
+	err := PullImage("ubuntu:24.04")
+	if err == nil || !strings.Contains(err.Error(), "pull image") {
+		t.Errorf("expected pull image error, got %v", err)
+	}
+}
+
+func TestImageTransferArgs(t *testing.T) {
+	setupMock(t)
+
+	tests := []struct {
+		name   string
+		remote string
+		got    func() []string
+		want   string
+	}{
+		{"export", "", func() []string { return exportImageArgs("my-base", "/tmp/my-base") }, "image export my-base /tmp/my-base"},
+		{"export from remote", "lab", func() []string { return exportImageArgs("my-base", "/tmp/my-base") }, "image export lab:my-base /tmp/my-base"},
+		{"import", "", func() []string { return importImageArgs("/tmp/my-base.tar.gz", "my-base") }, "image import /tmp/my-base.tar.gz --alias my-base"},
+		{"import to remote", "lab", func() []string { return importImageArgs("/tmp/my-base.tar.gz", "my-base") }, "image import /tmp/my-base.tar.gz lab: --alias my-base"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRemote(tt.remote)
+			t.Cleanup(func() { SetRemote("") })
+
+			if got := strings.Join(tt.got(), " "); got != tt.want {
+				t.Errorf("args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportImageWithProgress_InvalidAlias(t *testing.T) {
+	mock := setupMock(t)
+
+	err := ExportImageWithProgress(context.Background(), "bad alias", "/tmp/x", io.Discard, io.Discard)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if mock.CallCount() != 0 {
+		t.Errorf("expected no lxc calls, got %v", mock.Calls)
+	}
+}
+
+func TestImportImageWithProgress_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("image import /tmp/x.tar.gz --alias my-base", "not an image")
+
+	err := ImportImageWithProgress(context.Background(), "/tmp/x.tar.gz", "my-base", io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "failed to import image") {
+		t.Errorf("expected import error, got %v", err)
+	}
+}
+
+func TestPullImageWithProgress(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("image copy ubuntu:24.04 local: --copy-aliases --alias noble", "Copying the image: 100%")
+
+	var stdout bytes.Buffer
+	if err := PullImageWithProgress(context.Background(), "ubuntu:24.04", "noble", &stdout, io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "100%") {
+		t.Errorf("expected progress to be streamed, got %q", stdout.String())
+	}
+}
+
+func TestPullImageWithProgress_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("image copy ubuntu:24.04 local: --copy-aliases", "remote unreachable")
+
+	err := PullImageWithProgress(context.Background(), "ubuntu:24.04", "", io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "failed to pull image") {
+		t.Errorf("expected pull error, got %v", err)
+	}
+}
+
+func TestExecStream(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- journalctl -n 50", "log line")
+
+	var stdout bytes.Buffer
+	if err := ExecStream(context.Background(), "dev1", &stdout, io.Discard, "journalctl", "-n", "50"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "log line" {
+		t.Errorf("expected output to be streamed, got %q", stdout.String())
+	}
+
+	mock.SetError("exec dev2 -- false", "exit status 1")
+	if err := ExecStream(context.Background(), "dev2", io.Discard, io.Discard, "false"); err == nil || !strings.Contains(err.Error(), "exec failed") {
+		t.Errorf("expected exec error, got %v", err)
+	}
+}
+
+func TestCopyWithOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CopyOptions
+		want string
+	}{
+		{"defaults", CopyOptions{}, "copy dev1 dev2"},
+		{"stateless", CopyOptions{Stateless: true}, "copy dev1 dev2 --stateless"},
+		{"refresh", CopyOptions{Refresh: true}, "copy dev1 dev2 --refresh"},
+		{"stateless refresh", CopyOptions{Stateless: true, Refresh: true}, "copy dev1 dev2 --stateless --refresh"},
+		{"snapshot", CopyOptions{Snapshot: "checkpoint"}, "copy dev1/checkpoint dev2"},
+		{"instance only", CopyOptions{InstanceOnly: true}, "copy dev1 dev2 --instance-only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := setupMock(t)
+
+			if err := CopyWithOptions("dev1", "dev2", tt.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(mock.LastCall().Args, " "); got != tt.want {
+				t.Errorf("args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyWithOptions_SnapshotError(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("copy dev1/checkpoint dev2", "not found")
+
+	err := CopyWithOptions("dev1", "dev2", CopyOptions{Snapshot: "checkpoint"})
+	var lxcErr *LXCError
+	if !errors.As(err, &lxcErr) || lxcErr.Name != "dev1/checkpoint" {
+		t.Errorf("expected error naming the snapshot, got %v", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRemote_DefaultUnchanged(t *testing.T) {
+	mock := setupMock(t)
+
+	Start("dev1")
+	if !mock.HasCall("start", "dev1") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+	if InstanceRef("dev1") != "dev1" {
+		t.Errorf("expected unqualified name, got %s", InstanceRef("dev1"))
+	}
+}
+
+func TestParseInfo_Success(t *testing.T) {
+	output := `Name: dev1
+Status: RUNNING
+Type: container
+
+Resources:
+  Processes: 12
+`
+	details, err := ParseInfo("dev1", output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := details.Fields
+	if fields["Name"] != "dev1" || fields["Status"] != "RUNNING" || fields["Type"] != "container" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if _, ok := fields["Processes"]; ok {
+		t.Error("nested fields should be skipped")
+	}
+}
+
+func TestParseInfo_MissingName(t *testing.T) {
+	_, err := ParseInfo("dev1", "nonsense")
+	var parseErr *InfoParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected InfoParseError, got %v", err)
+	}
+	if parseErr.Container != "dev1" {
+		t.Errorf("expected container dev1, got %s", parseErr.Container)
+	}
+}
+
+func TestInfo_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info dev1", "not found")
+
+	_, err := Info("dev1")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestExecOutput_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- cat /etc/hostname", "dev1\n")
+
+	out, err := ExecOutput("dev1", "cat", "/etc/hostname")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "dev1" {
+		t.Errorf("expected trimmed %q, got %q", "dev1", out)
+	}
+}
+
+func TestExecOutput_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("exec dev1 -- cat /missing", []byte("cat: /missing: No such file or directory"), errors.New("exit status 1"))
+
+	_, err := ExecOutput("dev1", "cat", "/missing")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "No such file or directory") {
+		t.Errorf("expected error to include output, got %v", err)
+	}
+}
+
+func TestExecOutput_CommandError(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("exec dev1 -- false", "exit status 1")
+
+	out, err := ExecOutput("dev1", "false")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if out != "" {
+		t.Errorf("expected empty output on error, got %q", out)
+	}
+	if !strings.Contains(err.Error(), "exit status 1") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExecOutput_Empty(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("exec dev1 -- true", "")
+
+	out, err := ExecOutput("dev1", "true")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "" {
+		t.Errorf("expected empty output, got %q", out)
+	}
+	if !mock.HasCall("exec", "dev1", "--", "true") {
+		t.Error("expected exec command to be called")
+	}
+}
+
+func TestBuildSetEnvScript(t *testing.T) {
+	script := buildSetEnvScript(map[string]string{"TZ": "UTC", "NODE_ENV": "development"})
+
+	expected := `set -e
+tmp=$(mktemp /etc/environment.XXXXXX)
+sed '/^# BEGIN lxc-dev-manager$/,/^# END lxc-dev-manager$/d' /etc/environment > "$tmp" 2>/dev/null || true
+cat >> "$tmp" <<'LXC_DEV_MANAGER_ENV'
+# BEGIN lxc-dev-manager
+NODE_ENV="development"
+TZ="UTC"
+# END lxc-dev-manager
+LXC_DEV_MANAGER_ENV
+chmod 644 "$tmp"
+mv "$tmp" /etc/environment
+`
+	if script != expected {
+		t.Errorf("unexpected script:\n%s", script)
+	}
+}
+
+func TestBuildSetEnvScript_Empty(t *testing.T) {
+	script := buildSetEnvScript(nil)
+
+	// Only clears the managed block
+	if strings.Contains(script, "cat >>") {
+		t.Errorf("expected no append for empty env, got:\n%s", script)
+	}
+	if !strings.Contains(script, "mv \"$tmp\" /etc/environment") {
+		t.Error("expected managed block to be cleared")
+	}
+}
+
+func TestSetEnv_Success(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := SetEnv("dev1", map[string]string{"A": "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCallPrefix("exec", "dev1", "--", "bash", "-c") {
+		t.Error("expected script to run in container")
+	}
+}
+
+func TestBuildDeploySSHKeyScript(t *testing.T) {
+	script := buildDeploySSHKeyScript("dev", "ssh-ed25519 AAAAC3Nz me@laptop")
+
+	for _, want := range []string{
+		"home=$(getent passwd dev | cut -d: -f6)",
+		"mkdir -p \"$home/.ssh\"",
+		"grep -qxF 'ssh-ed25519 AAAAC3Nz me@laptop' \"$home/.ssh/authorized_keys\" || echo 'ssh-ed25519 AAAAC3Nz me@laptop' >> \"$home/.ssh/authorized_keys\"",
+		"chmod 700 \"$home/.ssh\"",
+		"chmod 600 \"$home/.ssh/authorized_keys\"",
+		"chown -R dev: \"$home/.ssh\"",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected %q in script:\n%s", want, script)
+		}
+	}
+}
+
+func TestDeploySSHKey_Success(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := DeploySSHKey("dev1", "dev", "ssh-ed25519 AAAAC3Nz me@laptop"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCallPrefix("exec", "dev1", "--", "bash", "-c") {
+		t.Error("expected script to run in container")
+	}
+}
+
+func TestConfigGet_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("config get dev1 limits.cpu", "2\n")
+
+	value, err := ConfigGet("dev1", "limits.cpu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "2" {
+		t.Errorf("expected '2', got %q", value)
+	}
+}
+
+func TestConfigGet_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("config get dev1 limits.cpu", "not found")
+
+	_, err := ConfigGet("dev1", "limits.cpu")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "failed to get config limits.cpu") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConfigGet_Unset(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("config get dev1 boot.autostart", "\n")
+
+	value, err := ConfigGet("dev1", "boot.autostart")
+	if err != nil {
+		t.Fatalf("unset key should not be an error: %v", err)
+	}
+	if value != "" {
+		t.Errorf("expected empty value, got %q", value)
+	}
+}
+
+func TestConfigSetGet_RoundTrip(t *testing.T) {
+	mock := setupMock(t)
+	// Make the mock remember what was set, like LXD does
+	mock.SetOutput("config set", "")
+	mock.SetCallback("config set", func(args []string) {
+		mock.SetOutput("config get "+args[2]+" "+args[3], args[4]+"\n")
+	})
+
+	if value, _ := ConfigGet("dev1", "security.nesting"); value != "" {
+		t.Errorf("expected unset key before set, got %q", value)
+	}
+	if err := ConfigSet("dev1", "security.nesting", "true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, err := ConfigGet("dev1", "security.nesting")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "true" {
+		t.Errorf("expected 'true' after set, got %q", value)
+	}
+}
+
+func TestSetCPULimit_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("config set dev1 limits.cpu 2", "")
+
+	if err := SetCPULimit("dev1", "2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("config", "set", "dev1", "limits.cpu", "2") {
+		t.Error("expected limits.cpu to be set")
+	}
+}
+
+func TestSetCPULimit_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("config set dev1 limits.cpu abc", "invalid value")
+
+	err := SetCPULimit("dev1", "abc")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "limits.cpu") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSetMemoryLimit_Success(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("config set dev1 limits.memory 2GiB", "")
+
+	if err := SetMemoryLimit("dev1", "2GiB"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("config", "set", "dev1", "limits.memory", "2GiB") {
+		t.Error("expected limits.memory to be set")
+	}
+}
+
+func TestSetMemoryLimit_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("config set dev1 limits.memory lots", "invalid value")
+
+	err := SetMemoryLimit("dev1", "lots")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "limits.memory") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSetLimits_UsesRemote(t *testing.T) {
+	mock := setupMock(t)
+	SetRemote("lab")
+
+	if err := SetMemoryLimit("dev1", "1GiB"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("config", "set", "lab:dev1", "limits.memory", "1GiB") {
+		t.Errorf("expected remote instance ref, got %v", mock.LastCall())
+	}
+}
+
+func TestSetLimits_Both(t *testing.T) {
+	mock := setupMock(t)
+	mock.DefaultResponse = MockResponse{Output: []byte("")}
+
+	if err := SetLimits("dev1", "2", "2GB"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("config", "set", "dev1", "limits.cpu", "2") {
+		t.Error("expected limits.cpu to be set")
+	}
+	if !mock.HasCall("config", "set", "dev1", "limits.memory", "2GB") {
+		t.Error("expected limits.memory to be set")
+	}
+}
+
+func TestSetLimits_SkipsEmpty(t *testing.T) {
+	mock := setupMock(t)
+	mock.DefaultResponse = MockResponse{Output: []byte("")}
+
+	if err := SetLimits("dev1", "", "512MB"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.HasCallPrefix("config", "set", "dev1", "limits.cpu") {
+		t.Error("empty CPU limit should not be set")
+	}
+
+	if err := SetLimits("dev1", "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("expected 1 call, got %d", len(mock.Calls))
+	}
+}
+
+func TestSetLimits_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("config set dev1 limits.cpu 64", "not enough CPUs")
+
+	if err := SetLimits("dev1", "64", "2GB"); err == nil {
+		t.Fatal("expected error")
+	}
+	if mock.HasCallPrefix("config", "set", "dev1", "limits.memory") {
+		t.Error("should stop after first failure")
+	}
+}
+
+const runningInfoOutput = `Name: test-dev1
+Status: RUNNING
+Type: container
+Architecture: x86_64
+PID: 4242
+Created: 2024/01/15 10:30 UTC
+Last Used: 2024/01/15 10:31 UTC
+
+Resources:
+  Processes: 42
+  CPU usage:
+    CPU usage (in seconds): 12
+  Memory usage:
+    Memory (current): 256.50MiB
+    Memory (peak): 300.00MiB
+  Network usage:
+    eth0:
+      Type: broadcast
+      State: UP
+      IP addresses:
+        inet:  10.10.10.5/24 (global)
+        inet6: fd42::1/64 (global)
+    lo:
+      Type: loopback
+      State: UP
+      IP addresses:
+        inet:  127.0.0.1/8 (local)
+`
+
+func TestGetInfo_Running(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", runningInfoOutput)
+
+	info, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Name != "test-dev1" || info.Status != "RUNNING" {
+		t.Errorf("unexpected name/status: %+v", info)
+	}
+	if info.PID != 4242 {
+		t.Errorf("expected PID 4242, got %d", info.PID)
+	}
+	if info.Memory != "256.50MiB" {
+		t.Errorf("expected memory 256.50MiB, got %q", info.Memory)
+	}
+	if info.IP != "10.10.10.5" {
+		t.Errorf("expected IP 10.10.10.5, got %q", info.IP)
+	}
+}
+
+func TestGetInfo_Stopped(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", `Name: test-dev1
+Status: STOPPED
+Type: container
+Architecture: x86_64
+Created: 2024/01/15 10:30 UTC
+`)
+
+	info, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Status != "STOPPED" {
+		t.Errorf("expected STOPPED, got %s", info.Status)
+	}
+	if info.PID != 0 || info.Memory != "" || info.IP != "" {
+		t.Errorf("expected no runtime fields, got %+v", info)
+	}
+}
+
+func TestGetInfo_LegacyFormat(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", `Name: test-dev1
+Status: Running
+Pid: 99
+`)
+
+	info, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Status != "RUNNING" || info.PID != 99 {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestGetInfo_InvalidPID(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", "Name: test-dev1\nPID: abc\n")
+
+	_, err := GetInfo("test-dev1")
+	var parseErr *InfoParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected InfoParseError, got %v", err)
+	}
+}
+
+func TestGetInfo_Error(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info test-dev1", "Error: Instance not found")
+
+	_, err := GetInfo("test-dev1")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestGetInfo_Details(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", runningInfoOutput+`
+Snapshots:
++-------+----------------------+------------+----------+
+| NAME  |       TAKEN AT       | EXPIRES AT | STATEFUL |
++-------+----------------------+------------+----------+
+| clean | 2024/01/15 10:40 UTC |            | NO       |
++-------+----------------------+------------+----------+
+| setup | 2024/01/16 09:00 UTC |            | NO       |
++-------+----------------------+------------+----------+
+`)
+
+	info, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Architecture != "x86_64" {
+		t.Errorf("expected architecture x86_64, got %q", info.Architecture)
+	}
+	want := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	if !info.Created.Equal(want) {
+		t.Errorf("expected created %v, got %v", want, info.Created)
+	}
+	if info.Snapshots != 2 {
+		t.Errorf("expected 2 snapshots, got %d", info.Snapshots)
+	}
+}
+
+func TestGetInfo_LegacySnapshots(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", `Name: test-dev1
+Status: Stopped
+Created: not a date
+Snapshots:
+  clean (taken at 2021/03/04 15:14 UTC) (stateless)
+  setup (taken at 2021/03/05 09:00 UTC) (stateless)
+  before-upgrade (taken at 2021/03/06 12:00 UTC) (stateless)
+`)
+
+	info, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Snapshots != 3 {
+		t.Errorf("expected 3 snapshots, got %d", info.Snapshots)
+	}
+	if !info.Created.IsZero() {
+		t.Errorf("unparseable Created should be zero, got %v", info.Created)
+	}
+}
+
+func TestGetInfo_NoSnapshots(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", runningInfoOutput)
+
+	info, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Snapshots != 0 {
+		t.Errorf("expected 0 snapshots, got %d", info.Snapshots)
+	}
+}
+
+func TestGetInfo_Cached(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", runningInfoOutput)
+
+	first, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.Status = "MODIFIED"
+
+	second, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("expected 1 lxc info call, got %d", mock.CallCount())
+	}
+	if second.Status != "RUNNING" {
+		t.Error("callers should not be able to modify the cached value")
+	}
+}
+
+func TestGetInfo_CacheExpires(t *testing.T) {
+	mock := setupMock(t)
+	oldTTL := InfoCacheTTL
+	InfoCacheTTL = 0
+	t.Cleanup(func() { InfoCacheTTL = oldTTL })
+	mock.SetOutput("info test-dev1", runningInfoOutput)
+
+	GetInfo("test-dev1")
+	GetInfo("test-dev1")
+
+	if mock.CallCount() != 2 {
+		t.Errorf("expected 2 lxc info calls, got %d", mock.CallCount())
+	}
+}
+
+func TestGetInfo_InvalidatedByStateChange(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("info test-dev1", "Name: test-dev1\nStatus: Stopped\n")
+
+	if info, _ := GetInfo("test-dev1"); info.Status != "STOPPED" {
+		t.Fatalf("unexpected status %q", info.Status)
+	}
+
+	mock.SetOutput("info test-dev1", "Name: test-dev1\nStatus: Running\n")
+	if err := Start("test-dev1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := GetInfo("test-dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Status != "RUNNING" {
+		t.Errorf("expected fresh status after Start, got %q", info.Status)
+	}
+}
+
+func TestGetInfo_ErrorNotCached(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("info test-dev1", "Error: Instance not found")
+
+	GetInfo("test-dev1")
+	mock.SetOutput("info test-dev1", runningInfoOutput)
+
+	if _, err := GetInfo("test-dev1"); err != nil {
+		t.Errorf("errors should not be cached: %v", err)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := Freeze("dev1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("pause", "dev1") {
+		t.Errorf("expected pause call, got %v", mock.Calls)
+	}
+}
+
+func TestUnfreeze(t *testing.T) {
+	mock := setupMock(t)
+
+	if err := Unfreeze("dev1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("start", "dev1") {
+		t.Errorf("expected start call, got %v", mock.Calls)
+	}
+}
+
+func TestErrors_NotFound(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("start dev1", []byte("Error: Instance not found"), errors.New("exit status 1"))
+	mock.SetResponse("info dev1", []byte("Error: Instance not found"), errors.New("exit status 1"))
+	mock.SetResponse("delete dev1/snap", []byte("Error: Not Found"), errors.New("exit status 1"))
+
+	if err := Start("dev1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Start: expected ErrNotFound, got %v", err)
+	}
+	if _, err := Info("dev1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Info: expected ErrNotFound, got %v", err)
+	}
+	if err := DeleteSnapshot("dev1", "snap"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteSnapshot: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStopStateful(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("stop dev1 --stateful", "")
+
+	if err := StopStateful("dev1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.HasCall("stop", "dev1", "--stateful") {
+		t.Errorf("expected stop --stateful, got %v", mock.Calls)
+	}
+}
+
+func TestStopStateful_NoCRIU(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("stop dev1 --stateful", []byte("Error: Unable to perform container live migration. CRIU isn't installed on the source server"), errors.New("exit status 1"))
+	mock.SetResponse("stop dev2 --stateful", []byte("Error: Stateful stop requires migration.stateful to be set to true"), errors.New("exit status 1"))
+
+	for _, name := range []string{"dev1", "dev2"} {
+		if err := StopStateful(name); !errors.Is(err, ErrStatefulUnsupported) {
+			t.Errorf("%s: expected ErrStatefulUnsupported, got %v", name, err)
+		}
+	}
+}
+
+func TestHasSavedState(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("query /1.0/instances/dev1?recursion=1", `{"name": "dev1", "status": "Stopped", "stateful": true}`)
+
+	saved, err := HasSavedState("dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !saved {
+		t.Error("expected saved state to be reported")
+	}
+
+	saved, err = HasSavedState("dev2")
+	if err == nil && saved {
+		t.Error("expected no saved state when the query returns nothing")
+	}
+}
+
+func TestErrors_AlreadyExists(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("snapshot dev1 snap", []byte("Error: Snapshot \"snap\" already exists"), errors.New("exit status 1"))
+
+	err := Snapshot("dev1", "snap")
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("did not expect ErrNotFound")
+	}
+}
+
+func TestErrors_LXCErrorFields(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("stop dev1", []byte("Error: The instance is busy"), errors.New("exit status 1"))
+
+	err := Stop("dev1", 0)
+	var lerr *LXCError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("expected *LXCError, got %T", err)
+	}
+	if lerr.Op != "stop container" || lerr.Name != "dev1" {
+		t.Errorf("Op = %q, Name = %q", lerr.Op, lerr.Name)
+	}
+	if lerr.Output != "Error: The instance is busy" {
+		t.Errorf("Output = %q", lerr.Output)
+	}
+	if err.Error() != "failed to stop container: Error: The instance is busy" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrAlreadyExists) {
+		t.Error("unclassified error should not match sentinels")
+	}
+}
+
+func TestErrors_ExecutorErrorWithoutOutput(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("list dev1 -c4 -f csv", "instance not found")
+
+	_, err := GetIP("dev1")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "instance not found") {
+		t.Errorf("expected executor message, got %v", err)
+	}
+}
+
+func TestErrors_GetIPNoIP(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("list dev1 -c4 -f csv", "")
+
+	if _, err := GetIP("dev1"); !errors.Is(err, ErrNoIP) {
+		t.Errorf("expected ErrNoIP, got %v", err)
+	}
+}
+
+func TestErrors_ImageFingerprintNotFound(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("image list my-base --format=csv -c f", "")
+
+	_, err := GetImageFingerprint("my-base")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestErrors_FilePushNotFound(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("file push", []byte("Error: not found"), errors.New("exit status 1"))
+
+	err := FilePush("dev1", "/tmp/x", "/missing/x", false)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "destination path '/missing/x' not found") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestGetSnapshotInfo(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("query /1.0/instances/dev1/snapshots/snap1",
+		`{"name":"snap1","created_at":"2024-01-15T10:30:00Z","stateful":false,"size":2048}`)
+
+	info, err := GetSnapshotInfo("dev1", "snap1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Name != "snap1" || info.CreatedAt != "2024-01-15T10:30:00Z" || info.Stateful || info.Size != 2048 {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestGetSnapshotInfo_Errors(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("query /1.0/instances/dev1/snapshots/missing", []byte("Error: Not Found"), errors.New("exit status 1"))
+	mock.SetOutput("query /1.0/instances/dev1/snapshots/garbled", "not json")
+
+	if _, err := GetSnapshotInfo("dev1", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := GetSnapshotInfo("dev1", "garbled"); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+const sampleInstanceJSON = `{
+	"name": "dev1",
+	"type": "container",
+	"status": "Running",
+	"created_at": "2024-01-15T10:30:00Z",
+	"config": {"limits.cpu": "2", "volatile.eth0.hwaddr": "00:16:3e:00:00:01"},
+	"state": {
+		"status": "Running",
+		"network": {
+			"eth0": {"addresses": [
+				{"family": "inet", "address": "10.87.167.42", "scope": "global"},
+				{"family": "inet6", "address": "fe80::1", "scope": "link"}
+			]},
+			"lo": {"addresses": [{"family": "inet", "address": "127.0.0.1", "scope": "local"}]}
+		}
+	}
+}`
+
+func TestQueryInstance(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("query /1.0/instances/dev1?recursion=1", sampleInstanceJSON)
+
+	raw, err := QueryInstance("dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(raw) != sampleInstanceJSON {
+		t.Errorf("expected raw response, got %s", raw)
+	}
+}
+
+func TestQueryInstance_Errors(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("query /1.0/instances/missing?recursion=1", []byte("Error: Not Found"), errors.New("exit status 1"))
+	mock.SetOutput("query /1.0/instances/garbled?recursion=1", "not json")
+
+	if _, err := QueryInstance("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := QueryInstance("garbled"); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestParseInstanceInfo(t *testing.T) {
+	info, err := ParseInstanceInfo(json.RawMessage(sampleInstanceJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Name != "dev1" || info.Type != "container" || info.Status != "Running" || info.CreatedAt != "2024-01-15T10:30:00Z" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if info.Config["limits.cpu"] != "2" {
+		t.Errorf("expected limits.cpu=2, got %v", info.Config)
+	}
+	if info.State == nil {
+		t.Fatal("expected state to be parsed")
+	}
+	eth0 := info.State.Network["eth0"].Addresses
+	if len(eth0) != 2 || eth0[0].Address != "10.87.167.42" || eth0[0].Family != "inet" || eth0[0].Scope != "global" {
+		t.Errorf("unexpected eth0 addresses: %+v", eth0)
+	}
+}
+
+func TestParseInstanceInfo_Invalid(t *testing.T) {
+	if _, err := ParseInstanceInfo(json.RawMessage(`{"name": `)); err == nil || !strings.Contains(err.Error(), "failed to parse instance info") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestListSnapshotsWithMetadata(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("query /1.0/instances/dev1/snapshots",
+		`["/1.0/instances/dev1/snapshots/snap2","/1.0/instances/dev1/snapshots/snap1"]`)
+	mock.SetOutput("query /1.0/instances/dev1/snapshots/snap1",
+		`{"name":"snap1","created_at":"2024-01-15T10:30:00.123456Z","stateful":true,"size":2048}`)
+	mock.SetError("query /1.0/instances/dev1/snapshots/snap2", "Error: not found")
+
+	snapshots, err := ListSnapshotsWithMetadata("dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "snap1" || snapshots[1].Name != "snap2" {
+		t.Fatalf("expected snapshots sorted by name, got %+v", snapshots)
+	}
+	info := snapshots[0].Info
+	if info == nil || info.CreatedAt != "2024-01-15T10:30:00.123456Z" || !info.Stateful {
+		t.Errorf("unexpected details: %+v", info)
+	}
+	if snapshots[1].Info != nil {
+		t.Errorf("expected no details for a failed lookup, got %+v", snapshots[1].Info)
+	}
+}
+
+func TestListSnapshotsWithMetadata_Cached(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("query /1.0/instances/dev1/snapshots", `["/1.0/instances/dev1/snapshots/snap1"]`)
+	mock.SetOutput("query /1.0/instances/dev1/snapshots/snap1", `{"name":"snap1","created_at":"2024-01-15T10:30:00Z"}`)
+
+	first, err := ListSnapshotsWithMetadata("dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first[0].Info.CreatedAt = "MODIFIED"
+
+	second, err := ListSnapshotsWithMetadata("dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.CallCount() != 2 {
+		t.Errorf("expected 2 lxc query calls, got %d", mock.CallCount())
+	}
+	if second[0].Info.CreatedAt != "2024-01-15T10:30:00Z" {
+		t.Error("callers should not be able to modify the cached value")
+	}
+}
+
+func TestListSnapshotsWithMetadata_InvalidatedBySnapshot(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("query /1.0/instances/dev1/snapshots", `[]`)
+
+	ListSnapshotsWithMetadata("dev1")
+	if err := Snapshot("dev1", "snap1"); err != nil {
+		t.Fatal(err)
+	}
+	mock.SetOutput("query /1.0/instances/dev1/snapshots", `["/1.0/instances/dev1/snapshots/snap1"]`)
+
+	snapshots, err := ListSnapshotsWithMetadata("dev1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("expected the new snapshot after Snapshot, got %+v", snapshots)
+	}
+}
+
+func TestListSnapshotsWithMetadata_ListError(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetError("query /1.0/instances/dev1/snapshots", "Error: not found")
+
+	if _, err := ListSnapshotsWithMetadata("dev1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFilePushWithProgress_StreamsOutput(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetOutput("file push -r ./src dev1//home/dev", "Pushing: 100%\n")
+
+	var stdout, stderr strings.Builder
+	if err := FilePushWithProgress(context.Background(), "dev1", "./src", "/home/dev", true, &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "Pushing: 100%\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	if !mock.HasCall("file", "push", "-r", "./src", "dev1//home/dev") {
+		t.Errorf("unexpected call: %v", mock.LastCall().Args)
+	}
+}
+
+func TestFilePushWithProgress_NotFound(t *testing.T) {
+	mock := setupMock(t)
+	mock.SetResponse("file push", []byte("Error: not found"), errors.New("exit status 1"))
+
+	var stdout, stderr strings.Builder
+	err := FilePushWithProgress(context.Background(), "dev1", "./src", "/missing/dir", true, &stdout, &stderr)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "destination path '/missing/dir' not found") {
+		t.Errorf("unexpected message: %v", err)
+	}
+	if stderr.String() != "Error: not found" {
+		t.Errorf("expected lxc's error on stderr, got %q", stderr.String())
+	}
+}
@@ -1,8 +1,12 @@
 package lxc
 
 import (
+	"context"
 	"errors"
+	"io"
 	"strings"
+	"sync"
+	"time"
 )
 
 // MockExecutor is a mock LXC executor for testing
@@ -19,6 +23,16 @@ type MockExecutor struct {
 	// Callbacks maps command patterns to functions called when the command is executed
 	// The callback receives the full args slice
 	Callbacks map[string]func(args []string)
+
+	// Sequences maps command patterns to responses returned one per call.
+	// The last response is repeated once the sequence is exhausted.
+	Sequences map[string][]MockResponse
+
+	// Delays maps command patterns to how long matching calls take to respond
+	Delays map[string]time.Duration
+
+	// mu guards Calls so the mock can be shared by parallel operations
+	mu sync.Mutex
 }
 
 // MockCall represents a single call to the executor
@@ -38,21 +52,64 @@ func NewMockExecutor() *MockExecutor {
 		Calls:     []MockCall{},
 		Responses: make(map[string]MockResponse),
 		Callbacks: make(map[string]func(args []string)),
+		Sequences: make(map[string][]MockResponse),
+		Delays:    make(map[string]time.Duration),
 	}
 }
 
 // Run implements Executor
 func (m *MockExecutor) Run(args ...string) ([]byte, error) {
-	m.Calls = append(m.Calls, MockCall{Args: args})
-	return m.getResponse(args)
+	return m.RunContext(context.Background(), args...)
 }
 
 // RunCombined implements Executor
 func (m *MockExecutor) RunCombined(args ...string) ([]byte, error) {
+	return m.RunCombinedContext(context.Background(), args...)
+}
+
+// RunContext implements Executor
+// The call is recorded even if ctx is already cancelled, in which case ctx.Err() is returned.
+// A delayed call returns ctx.Err() if ctx ends before the delay does.
+func (m *MockExecutor) RunContext(ctx context.Context, args ...string) ([]byte, error) {
+	m.mu.Lock()
 	m.Calls = append(m.Calls, MockCall{Args: args})
+	delay := m.delayFor(strings.Join(args, " "))
+	m.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.getResponse(args)
 }
 
+// RunCombinedContext implements Executor
+func (m *MockExecutor) RunCombinedContext(ctx context.Context, args ...string) ([]byte, error) {
+	return m.RunContext(ctx, args...)
+}
+
+// RunStream implements Executor. The mock output is written to stdout, or
+// to stderr when the response is an error.
+func (m *MockExecutor) RunStream(ctx context.Context, stdout, stderr io.Writer, args ...string) error {
+	output, err := m.RunContext(ctx, args...)
+	if err != nil {
+		stderr.Write(output)
+		return err
+	}
+	stdout.Write(output)
+	return nil
+}
+
 func (m *MockExecutor) getResponse(args []string) ([]byte, error) {
 	key := strings.Join(args, " ")
 
@@ -68,6 +125,11 @@ func (m *MockExecutor) getResponse(args []string) ([]byte, error) {
 		}
 	}
 
+	// Sequenced responses take precedence over fixed ones
+	if resp, ok := m.nextInSequence(key); ok {
+		return resp.Output, resp.Err
+	}
+
 	// Try exact match first
 	if resp, ok := m.Responses[key]; ok {
 		return resp.Output, resp.Err
@@ -84,19 +146,83 @@ func (m *MockExecutor) getResponse(args []string) ([]byte, error) {
 	return m.DefaultResponse.Output, m.DefaultResponse.Err
 }
 
-// SetResponse sets a response for a command pattern
+// delayFor returns the delay for key (exact match first, then prefix)
+func (m *MockExecutor) delayFor(key string) time.Duration {
+	if d, ok := m.Delays[key]; ok {
+		return d
+	}
+	for pattern, d := range m.Delays {
+		if strings.HasPrefix(key, pattern) {
+			return d
+		}
+	}
+	return 0
+}
+
+// nextInSequence pops the next sequenced response for key (exact match first, then prefix)
+func (m *MockExecutor) nextInSequence(key string) (MockResponse, bool) {
+	pattern := ""
+	if _, ok := m.Sequences[key]; ok {
+		pattern = key
+	} else {
+		for p := range m.Sequences {
+			if strings.HasPrefix(key, p) {
+				pattern = p
+				break
+			}
+		}
+	}
+	seq := m.Sequences[pattern]
+	if len(seq) == 0 {
+		return MockResponse{}, false
+	}
+
+	resp := seq[0]
+	if len(seq) > 1 {
+		m.Sequences[pattern] = seq[1:]
+	}
+	return resp, true
+}
+
+// SetResponses queues responses returned in order on successive calls matching
+// pattern. The last response is repeated once the queue is exhausted.
+func (m *MockExecutor) SetResponses(pattern string, resps ...MockResponse) {
+	m.Sequences[pattern] = resps
+}
+
+// SetOutputs queues successful outputs returned in order on successive calls
+// matching pattern. The last output is repeated once the queue is exhausted.
+func (m *MockExecutor) SetOutputs(pattern string, outputs ...string) {
+	resps := make([]MockResponse, len(outputs))
+	for i, output := range outputs {
+		resps[i] = MockResponse{Output: []byte(output)}
+	}
+	m.SetResponses(pattern, resps...)
+}
+
+// SetResponse sets a response for a command pattern, replacing any queued responses
 func (m *MockExecutor) SetResponse(pattern string, output []byte, err error) {
+	delete(m.Sequences, pattern)
 	m.Responses[pattern] = MockResponse{Output: output, Err: err}
 }
 
 // SetError sets an error response for a command pattern
 func (m *MockExecutor) SetError(pattern string, errMsg string) {
-	m.Responses[pattern] = MockResponse{Err: errors.New(errMsg)}
+	m.SetResponse(pattern, nil, errors.New(errMsg))
 }
 
 // SetOutput sets a successful output for a command pattern
 func (m *MockExecutor) SetOutput(pattern string, output string) {
-	m.Responses[pattern] = MockResponse{Output: []byte(output)}
+	m.SetResponse(pattern, []byte(output), nil)
+}
+
+// WithDelay makes calls matching pattern wait d before responding, to
+// simulate slow LXC operations. It returns m so it can be chained.
+func (m *MockExecutor) WithDelay(pattern string, d time.Duration) *MockExecutor {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Delays[pattern] = d
+	return m
 }
 
 // SetCallback sets a callback function for a command pattern
@@ -110,6 +236,8 @@ func (m *MockExecutor) Reset() {
 	m.Calls = []MockCall{}
 	m.Responses = make(map[string]MockResponse)
 	m.Callbacks = make(map[string]func(args []string))
+	m.Sequences = make(map[string][]MockResponse)
+	m.Delays = make(map[string]time.Duration)
 	m.DefaultResponse = MockResponse{}
 }
 
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,33 +17,119 @@ const (
 	ConnectionTimeout = 30 * time.Second
 	// DialTimeout is the timeout for establishing remote connections
 	DialTimeout = 5 * time.Second
+	// MaxDatagramSize is the largest UDP payload forwarded
+	MaxDatagramSize = 65535
+	// DefaultBindAddr is the local address proxies listen on unless told otherwise
+	DefaultBindAddr = "127.0.0.1"
 )
 
-// Proxy represents a TCP proxy for a single port
+// udpIdleTimeout is how long a UDP client mapping lives without traffic
+var udpIdleTimeout = ConnectionTimeout
+
+// Proxy represents a TCP or UDP proxy for a single port
 type Proxy struct {
+	BindAddr   string
 	LocalPort  int
 	RemoteAddr string
+	Protocol   string // "tcp" or "udp"
+	Container  string // Container forwarded to, if known; lets Refresh follow its IP
 	listener   net.Listener
+	packetConn net.PacketConn
 	done       chan struct{}
 	wg         sync.WaitGroup
-	connSem    chan struct{} // Semaphore for limiting concurrent connections
+	connSem    chan struct{} // Semaphore for limiting concurrent connections (or UDP clients)
+
+	sessionsMu sync.Mutex
+	sessions   map[string]*net.UDPConn // UDP client address -> remote socket
+
+	activeConns      int64 // Open connections (or UDP clients), updated atomically
+	totalConns       int64 // Connections (or UDP clients) accepted since Start
+	bytesTransferred int64 // Bytes forwarded in both directions
+}
+
+// ConnectionCount returns the number of open connections (UDP: active clients)
+func (p *Proxy) ConnectionCount() int64 {
+	return atomic.LoadInt64(&p.activeConns)
+}
+
+// TotalConnections returns the number of connections accepted since Start
+func (p *Proxy) TotalConnections() int64 {
+	return atomic.LoadInt64(&p.totalConns)
+}
+
+// BytesTransferred returns the bytes forwarded in both directions
+func (p *Proxy) BytesTransferred() int64 {
+	return atomic.LoadInt64(&p.bytesTransferred)
+}
+
+// HealthCheck reports whether something accepts connections on the remote
+// address. UDP has no handshake to check, so UDP proxies always pass.
+func (p *Proxy) HealthCheck() bool {
+	if p.Protocol == "udp" {
+		return true
+	}
+	conn, err := net.DialTimeout("tcp", p.RemoteAddr, DialTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// countingWriter adds the bytes written through it to a shared counter
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	atomic.AddInt64(c.n, int64(n))
+	return n, err
 }
 
-// New creates a new proxy
-func New(localPort int, remoteHost string, remotePort int) *Proxy {
+// New creates a new TCP proxy listening on bindAddr:localPort.
+// An empty bindAddr means DefaultBindAddr (loopback only).
+func New(bindAddr string, localPort int, remoteHost string, remotePort int) *Proxy {
+	if bindAddr == "" {
+		bindAddr = DefaultBindAddr
+	}
 	return &Proxy{
+		BindAddr:   bindAddr,
 		LocalPort:  localPort,
-		RemoteAddr: fmt.Sprintf("%s:%d", remoteHost, remotePort),
+		RemoteAddr: net.JoinHostPort(remoteHost, strconv.Itoa(remotePort)),
+		Protocol:   "tcp",
 		done:       make(chan struct{}),
 		connSem:    make(chan struct{}, MaxConnectionsPerProxy),
 	}
 }
 
+// NewUDP creates a new UDP proxy listening on bindAddr:localPort
+func NewUDP(bindAddr string, localPort int, remoteHost string, remotePort int) *Proxy {
+	p := New(bindAddr, localPort, remoteHost, remotePort)
+	p.Protocol = "udp"
+	p.sessions = make(map[string]*net.UDPConn)
+	return p
+}
+
 // Start begins listening and proxying connections
 func (p *Proxy) Start() error {
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", p.LocalPort))
+	if p.Protocol == "udp" {
+		conn, err := net.ListenPacket("udp", p.localAddr())
+		if err != nil {
+			return fmt.Errorf("failed to listen on udp %s: %w", p.localAddr(), err)
+		}
+		p.packetConn = conn
+
+		p.wg.Add(1)
+		go p.udpLoop()
+
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", p.localAddr())
 	if err != nil {
-		return fmt.Errorf("failed to listen on port %d: %w", p.LocalPort, err)
+		return fmt.Errorf("failed to listen on %s: %w", p.localAddr(), err)
 	}
 	p.listener = listener
 
@@ -51,12 +139,26 @@ func (p *Proxy) Start() error {
 	return nil
 }
 
+func (p *Proxy) localAddr() string {
+	return net.JoinHostPort(p.BindAddr, strconv.Itoa(p.LocalPort))
+}
+
 // Stop stops the proxy
 func (p *Proxy) Stop() {
 	close(p.done)
 	if p.listener != nil {
 		p.listener.Close()
 	}
+	if p.packetConn != nil {
+		p.packetConn.Close()
+
+		// Unblock per-client reply loops
+		p.sessionsMu.Lock()
+		for _, remote := range p.sessions {
+			remote.Close()
+		}
+		p.sessionsMu.Unlock()
+	}
 	p.wg.Wait()
 }
 
@@ -77,6 +179,8 @@ func (p *Proxy) acceptLoop() {
 		// Try to acquire semaphore (non-blocking)
 		select {
 		case p.connSem <- struct{}{}: // Acquired slot
+			atomic.AddInt64(&p.activeConns, 1)
+			atomic.AddInt64(&p.totalConns, 1)
 			p.wg.Add(1)
 			go p.handleConnection(conn)
 		default:
@@ -89,6 +193,7 @@ func (p *Proxy) acceptLoop() {
 func (p *Proxy) handleConnection(local net.Conn) {
 	defer func() {
 		local.Close()
+		atomic.AddInt64(&p.activeConns, -1)
 		<-p.connSem // Release semaphore slot
 		p.wg.Done()
 	}()
@@ -111,7 +216,7 @@ func (p *Proxy) handleConnection(local net.Conn) {
 	done := make(chan struct{}, 2)
 
 	go func() {
-		io.Copy(remote, local)
+		io.Copy(countingWriter{remote, &p.bytesTransferred}, local)
 		// Half-close: signal we're done writing to remote
 		if tc, ok := remote.(*net.TCPConn); ok {
 			tc.CloseWrite()
@@ -120,7 +225,7 @@ func (p *Proxy) handleConnection(local net.Conn) {
 	}()
 
 	go func() {
-		io.Copy(local, remote)
+		io.Copy(countingWriter{local, &p.bytesTransferred}, remote)
 		// Half-close: signal we're done writing to local
 		if tc, ok := local.(*net.TCPConn); ok {
 			tc.CloseWrite()
@@ -133,23 +238,150 @@ func (p *Proxy) handleConnection(local net.Conn) {
 	<-done
 }
 
+func (p *Proxy) udpLoop() {
+	defer p.wg.Done()
+
+	buf := make([]byte, MaxDatagramSize)
+	for {
+		n, client, err := p.packetConn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-p.done:
+				return
+			default:
+				continue
+			}
+		}
+
+		remote := p.udpSession(client)
+		if remote == nil {
+			continue // At capacity or remote unreachable - drop datagram
+		}
+
+		// Traffic from the client keeps the mapping alive
+		remote.SetReadDeadline(time.Now().Add(udpIdleTimeout))
+		if written, err := remote.Write(buf[:n]); err == nil {
+			atomic.AddInt64(&p.bytesTransferred, int64(written))
+		}
+	}
+}
+
+// udpSession returns the remote socket for a client, creating it if needed
+func (p *Proxy) udpSession(client net.Addr) *net.UDPConn {
+	key := client.String()
+
+	p.sessionsMu.Lock()
+	defer p.sessionsMu.Unlock()
+
+	if remote, ok := p.sessions[key]; ok {
+		return remote
+	}
+
+	// Try to acquire semaphore (non-blocking)
+	select {
+	case p.connSem <- struct{}{}:
+	default:
+		return nil
+	}
+
+	raddr, err := net.ResolveUDPAddr("udp", p.RemoteAddr)
+	if err != nil {
+		<-p.connSem
+		return nil
+	}
+	remote, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		<-p.connSem
+		return nil
+	}
+
+	p.sessions[key] = remote
+	atomic.AddInt64(&p.activeConns, 1)
+	atomic.AddInt64(&p.totalConns, 1)
+	p.wg.Add(1)
+	go p.udpReplyLoop(client, remote)
+
+	return remote
+}
+
+// udpReplyLoop copies datagrams from the remote back to one client until
+// the mapping has been idle for udpIdleTimeout or the proxy stops
+func (p *Proxy) udpReplyLoop(client net.Addr, remote *net.UDPConn) {
+	defer func() {
+		p.sessionsMu.Lock()
+		delete(p.sessions, client.String())
+		p.sessionsMu.Unlock()
+		remote.Close()
+		atomic.AddInt64(&p.activeConns, -1)
+		<-p.connSem // Release semaphore slot
+		p.wg.Done()
+	}()
+
+	buf := make([]byte, MaxDatagramSize)
+	for {
+		remote.SetReadDeadline(time.Now().Add(udpIdleTimeout))
+		n, err := remote.Read(buf)
+		if err != nil {
+			// Idle timeout, proxy stopped, or remote gone
+			return
+		}
+		written, err := p.packetConn.WriteTo(buf[:n], client)
+		if err != nil {
+			return
+		}
+		atomic.AddInt64(&p.bytesTransferred, int64(written))
+	}
+}
+
+// Stats summarizes traffic across the proxies of a Manager
+type Stats struct {
+	Proxies           int
+	ActiveConnections int64
+	TotalConnections  int64
+	BytesTransferred  int64
+}
+
 // Manager manages multiple proxies
 type Manager struct {
-	proxies []*Proxy
-	mu      sync.Mutex
+	bindAddr string
+	proxies  []*Proxy
+	stopped  Stats // Totals of proxies removed by StopAll or Remove
+	mu       sync.Mutex
 }
 
-// NewManager creates a new proxy manager
-func NewManager() *Manager {
-	return &Manager{}
+// NewManager creates a new proxy manager whose proxies listen on bindAddr.
+// An empty bindAddr means DefaultBindAddr.
+func NewManager(bindAddr string) *Manager {
+	return &Manager{bindAddr: bindAddr}
 }
 
 // Add adds a proxy for a port
 func (m *Manager) Add(localPort int, remoteHost string, remotePort int) error {
+	return m.AddForContainer("", localPort, remoteHost, remotePort)
+}
+
+// AddForContainer adds a proxy for a port of a container, so that Refresh
+// and Watch can follow the container when its IP changes
+func (m *Manager) AddForContainer(container string, localPort int, remoteHost string, remotePort int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	proxy := New(m.bindAddr, localPort, remoteHost, remotePort)
+	proxy.Container = container
+	if err := proxy.Start(); err != nil {
+		return err
+	}
+
+	m.proxies = append(m.proxies, proxy)
+	return nil
+}
+
+// AddUDP adds a UDP proxy for a port
+func (m *Manager) AddUDP(localPort int, remoteHost string, remotePort int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	proxy := New(localPort, remoteHost, remotePort)
+	proxy := NewUDP(m.bindAddr, localPort, remoteHost, remotePort)
 	if err := proxy.Start(); err != nil {
 		return err
 	}
@@ -158,13 +390,131 @@ func (m *Manager) Add(localPort int, remoteHost string, remotePort int) error {
 	return nil
 }
 
+// ProxySpec describes one proxy for AddAll
+type ProxySpec struct {
+	Container  string // Container forwarded to, if known (see AddForContainer)
+	LocalPort  int
+	RemoteHost string
+	RemotePort int
+	Protocol   string // "tcp" (default) or "udp"
+}
+
+// addAllConcurrency bounds how many proxies AddAll starts at once
+var addAllConcurrency = 8
+
+// AddAll starts a proxy for every spec concurrently. If any of them fails,
+// the ones already started by this call are stopped, the rest are skipped,
+// and the first error is returned; proxies added earlier keep running.
+func (m *Manager) AddAll(specs []ProxySpec) error {
+	started := make([]*Proxy, len(specs))
+	sem := make(chan struct{}, addAllConcurrency)
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	var firstErr error
+	var once sync.Once
+
+	for i, spec := range specs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, spec ProxySpec) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if failed.Load() {
+				return
+			}
+
+			var p *Proxy
+			if spec.Protocol == "udp" {
+				p = NewUDP(m.bindAddr, spec.LocalPort, spec.RemoteHost, spec.RemotePort)
+			} else {
+				p = New(m.bindAddr, spec.LocalPort, spec.RemoteHost, spec.RemotePort)
+			}
+			p.Container = spec.Container
+			if err := p.Start(); err != nil {
+				once.Do(func() { firstErr = err })
+				failed.Store(true)
+				return
+			}
+			started[i] = p
+		}(i, spec)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		for _, p := range started {
+			if p != nil {
+				p.Stop()
+			}
+		}
+		return firstErr
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.proxies = append(m.proxies, started...)
+	return nil
+}
+
 // StopAll stops all proxies
 func (m *Manager) StopAll() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for _, p := range m.proxies {
-		p.Stop()
+		m.stop(p)
 	}
 	m.proxies = nil
 }
+
+// Remove stops the proxies listening on localPort (TCP and UDP) and drops
+// them from the manager. The other proxies keep running.
+func (m *Manager) Remove(localPort int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.proxies[:0]
+	removed := 0
+	for _, p := range m.proxies {
+		if p.LocalPort != localPort {
+			kept = append(kept, p)
+			continue
+		}
+		m.stop(p)
+		removed++
+	}
+	// Clear the tail so stopped proxies can be garbage collected
+	for i := len(kept); i < len(m.proxies); i++ {
+		m.proxies[i] = nil
+	}
+	m.proxies = kept
+
+	if removed == 0 {
+		return fmt.Errorf("no proxy on port %d", localPort)
+	}
+	return nil
+}
+
+// stop stops p and adds its traffic to the stopped totals; m.mu must be held
+func (m *Manager) stop(p *Proxy) {
+	p.Stop()
+	m.stopped.Proxies++
+	m.stopped.TotalConnections += p.TotalConnections()
+	m.stopped.BytesTransferred += p.BytesTransferred()
+}
+
+// Stats aggregates traffic across all proxies, including ones already stopped
+func (m *Manager) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stopped
+	for _, p := range m.proxies {
+		stats.Proxies++
+		stats.ActiveConnections += p.ConnectionCount()
+		stats.TotalConnections += p.TotalConnections()
+		stats.BytesTransferred += p.BytesTransferred()
+	}
+	return stats
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -57,13 +59,16 @@ func TestProxy_Start(t *testing.T) {
 	localPort := getFreePort(t)
 	remotePort := getFreePort(t)
 
-	proxy := New(localPort, "127.0.0.1", remotePort)
+	proxy := New("", localPort, "127.0.0.1", remotePort)
+	if proxy.BindAddr != DefaultBindAddr {
+		t.Errorf("expected default bind address %s, got %s", DefaultBindAddr, proxy.BindAddr)
+	}
 	if err := proxy.Start(); err != nil {
 		t.Fatalf("failed to start proxy: %v", err)
 	}
 	defer proxy.Stop()
 
-	// Verify listening
+	// Verify listening on loopback
 	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", localPort), time.Second)
 	if err != nil {
 		t.Fatalf("failed to connect to proxy: %v", err)
@@ -71,6 +76,64 @@ func TestProxy_Start(t *testing.T) {
 	conn.Close()
 }
 
+// nonLoopbackIP returns an IPv4 address of a local non-loopback interface
+func nonLoopbackIP(t *testing.T) string {
+	t.Helper()
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		t.Skipf("cannot list interfaces: %v", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	t.Skip("no non-loopback IPv4 interface available")
+	return ""
+}
+
+func TestProxy_DefaultBindIsLoopbackOnly(t *testing.T) {
+	ip := nonLoopbackIP(t)
+	localPort := getFreePort(t)
+
+	proxy := New("", localPort, "127.0.0.1", 8080)
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", ip, localPort), time.Second)
+	if err == nil {
+		conn.Close()
+		t.Errorf("proxy should not be reachable on %s", ip)
+	}
+}
+
+func TestProxy_BindNonLoopback(t *testing.T) {
+	ip := nonLoopbackIP(t)
+	localPort := getFreePort(t)
+
+	proxy := New(ip, localPort, "127.0.0.1", 8080)
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	// Reachable on the chosen interface
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", ip, localPort), time.Second)
+	if err != nil {
+		t.Fatalf("proxy not reachable on %s: %v", ip, err)
+	}
+	conn.Close()
+
+	// ...but not on loopback
+	conn, err = net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", localPort), time.Second)
+	if err == nil {
+		conn.Close()
+		t.Error("proxy should not be reachable on 127.0.0.1")
+	}
+}
+
 func TestProxy_StartPortInUse(t *testing.T) {
 	port := getFreePort(t)
 
@@ -82,7 +145,7 @@ func TestProxy_StartPortInUse(t *testing.T) {
 	defer listener.Close()
 
 	// Try to start proxy on same port
-	proxy := New(port, "127.0.0.1", 8080)
+	proxy := New("", port, "127.0.0.1", 8080)
 	err = proxy.Start()
 	if err == nil {
 		proxy.Stop()
@@ -93,7 +156,7 @@ func TestProxy_StartPortInUse(t *testing.T) {
 func TestProxy_Stop(t *testing.T) {
 	localPort := getFreePort(t)
 
-	proxy := New(localPort, "127.0.0.1", 8080)
+	proxy := New("", localPort, "127.0.0.1", 8080)
 	if err := proxy.Start(); err != nil {
 		t.Fatal(err)
 	}
@@ -121,7 +184,7 @@ func TestProxy_ForwardsData(t *testing.T) {
 	}()
 
 	// Start proxy
-	proxy := New(localPort, "127.0.0.1", remotePort)
+	proxy := New("", localPort, "127.0.0.1", remotePort)
 	if err := proxy.Start(); err != nil {
 		t.Fatal(err)
 	}
@@ -165,7 +228,7 @@ func TestProxy_BidirectionalData(t *testing.T) {
 	}()
 
 	// Start proxy
-	proxy := New(localPort, "127.0.0.1", remotePort)
+	proxy := New("", localPort, "127.0.0.1", remotePort)
 	if err := proxy.Start(); err != nil {
 		t.Fatal(err)
 	}
@@ -206,7 +269,7 @@ func TestProxy_MultipleConnections(t *testing.T) {
 	}()
 
 	// Start proxy
-	proxy := New(localPort, "127.0.0.1", remotePort)
+	proxy := New("", localPort, "127.0.0.1", remotePort)
 	if err := proxy.Start(); err != nil {
 		t.Fatal(err)
 	}
@@ -256,7 +319,7 @@ func TestProxy_RemoteUnavailable(t *testing.T) {
 	localPort := getFreePort(t)
 	remotePort := getFreePort(t) // No server listening
 
-	proxy := New(localPort, "127.0.0.1", remotePort)
+	proxy := New("", localPort, "127.0.0.1", remotePort)
 	if err := proxy.Start(); err != nil {
 		t.Fatal(err)
 	}
@@ -278,6 +341,34 @@ func TestProxy_RemoteUnavailable(t *testing.T) {
 	}
 }
 
+func TestProxy_HealthCheck(t *testing.T) {
+	remotePort := getFreePort(t)
+	listener, done := startEchoServer(t, remotePort)
+	defer func() {
+		close(done)
+		listener.Close()
+	}()
+
+	proxy := New("", getFreePort(t), "127.0.0.1", remotePort)
+	if !proxy.HealthCheck() {
+		t.Error("expected health check to pass with a listening remote")
+	}
+}
+
+func TestProxy_HealthCheckUnreachable(t *testing.T) {
+	proxy := New("", getFreePort(t), "127.0.0.1", getFreePort(t)) // No server listening
+	if proxy.HealthCheck() {
+		t.Error("expected health check to fail without a listening remote")
+	}
+}
+
+func TestProxy_HealthCheckUDP(t *testing.T) {
+	proxy := NewUDP("", getFreePort(t), "127.0.0.1", getFreePort(t))
+	if !proxy.HealthCheck() {
+		t.Error("UDP proxies have nothing to check and should pass")
+	}
+}
+
 func TestProxy_LargeData(t *testing.T) {
 	localPort := getFreePort(t)
 	remotePort := getFreePort(t)
@@ -290,7 +381,7 @@ func TestProxy_LargeData(t *testing.T) {
 	}()
 
 	// Start proxy
-	proxy := New(localPort, "127.0.0.1", remotePort)
+	proxy := New("", localPort, "127.0.0.1", remotePort)
 	if err := proxy.Start(); err != nil {
 		t.Fatal(err)
 	}
@@ -337,7 +428,7 @@ func TestProxy_LargeData(t *testing.T) {
 func TestManager_Add(t *testing.T) {
 	localPort := getFreePort(t)
 
-	manager := NewManager()
+	manager := NewManager("")
 	defer manager.StopAll()
 
 	if err := manager.Add(localPort, "127.0.0.1", 8080); err != nil {
@@ -357,7 +448,7 @@ func TestManager_AddMultiple(t *testing.T) {
 	port2 := getFreePort(t)
 	port3 := getFreePort(t)
 
-	manager := NewManager()
+	manager := NewManager("")
 	defer manager.StopAll()
 
 	for _, port := range []int{port1, port2, port3} {
@@ -381,7 +472,7 @@ func TestManager_StopAll(t *testing.T) {
 	port1 := getFreePort(t)
 	port2 := getFreePort(t)
 
-	manager := NewManager()
+	manager := NewManager("")
 
 	manager.Add(port1, "127.0.0.1", 8080)
 	manager.Add(port2, "127.0.0.1", 8080)
@@ -401,10 +492,97 @@ func TestManager_StopAll(t *testing.T) {
 	}
 }
 
+func TestManager_Remove(t *testing.T) {
+	port1 := getFreePort(t)
+	port2 := getFreePort(t)
+
+	manager := NewManager("")
+	defer manager.StopAll()
+
+	for _, port := range []int{port1, port2} {
+		if err := manager.Add(port, "127.0.0.1", 8080); err != nil {
+			t.Fatalf("failed to add proxy for port %d: %v", port, err)
+		}
+	}
+
+	if err := manager.Remove(port1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The removed port is released
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port1))
+	if err != nil {
+		t.Errorf("port %d should be released: %v", port1, err)
+	} else {
+		listener.Close()
+	}
+
+	// The other proxy keeps running
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port2), time.Second)
+	if err != nil {
+		t.Errorf("proxy on port %d should still be listening: %v", port2, err)
+	} else {
+		conn.Close()
+	}
+
+	stats := manager.Stats()
+	if stats.Proxies != 2 {
+		t.Errorf("expected the removed proxy to stay in the totals, got %d proxies", stats.Proxies)
+	}
+
+	// The port can be proxied again
+	if err := manager.Add(port1, "127.0.0.1", 8080); err != nil {
+		t.Errorf("failed to re-add proxy on port %d: %v", port1, err)
+	}
+}
+
+func TestManager_RemoveTCPAndUDP(t *testing.T) {
+	port := getFreePort(t)
+
+	manager := NewManager("")
+	defer manager.StopAll()
+
+	if err := manager.Add(port, "127.0.0.1", 8080); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddUDP(port, "127.0.0.1", 8080); err != nil {
+		t.Skipf("UDP port %d not available: %v", port, err)
+	}
+
+	if err := manager.Remove(port); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conn, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Errorf("UDP port %d should be released: %v", port, err)
+	} else {
+		conn.Close()
+	}
+}
+
+func TestManager_RemoveUnknownPort(t *testing.T) {
+	port := getFreePort(t)
+
+	manager := NewManager("")
+	defer manager.StopAll()
+
+	if err := manager.Add(port, "127.0.0.1", 8080); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Remove(port + 1); err == nil {
+		t.Error("expected error for a port without a proxy")
+	}
+	if stats := manager.Stats(); stats.Proxies != 1 {
+		t.Errorf("expected the proxy to be kept, got %d proxies", stats.Proxies)
+	}
+}
+
 func TestManager_AddDuplicatePort(t *testing.T) {
 	port := getFreePort(t)
 
-	manager := NewManager()
+	manager := NewManager("")
 	defer manager.StopAll()
 
 	if err := manager.Add(port, "127.0.0.1", 8080); err != nil {
@@ -417,3 +595,542 @@ func TestManager_AddDuplicatePort(t *testing.T) {
 		t.Error("expected error when adding duplicate port")
 	}
 }
+
+func TestManager_AddAll(t *testing.T) {
+	remotePort := getFreePort(t)
+	listener, done := startEchoServer(t, remotePort)
+	defer func() {
+		close(done)
+		listener.Close()
+	}()
+
+	manager := NewManager("")
+	defer manager.StopAll()
+
+	var specs []ProxySpec
+	for i := 0; i < 5; i++ {
+		specs = append(specs, ProxySpec{Container: "dev1", LocalPort: getFreePort(t), RemoteHost: "127.0.0.1", RemotePort: remotePort})
+	}
+	if err := manager.AddAll(specs); err != nil {
+		t.Fatalf("AddAll failed: %v", err)
+	}
+
+	if got := manager.Stats().Proxies; got != len(specs) {
+		t.Errorf("expected %d proxies, got %d", len(specs), got)
+	}
+	for _, spec := range specs {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", spec.LocalPort), time.Second)
+		if err != nil {
+			t.Errorf("proxy on port %d not listening: %v", spec.LocalPort, err)
+			continue
+		}
+		conn.Close()
+	}
+	if ips := manager.containerIPs(); ips["dev1"] != "127.0.0.1" {
+		t.Errorf("expected proxies to be tied to dev1, got %v", ips)
+	}
+}
+
+func TestManager_AddAllFailureTearsDown(t *testing.T) {
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer occupied.Close()
+	occupiedPort := occupied.Addr().(*net.TCPAddr).Port
+
+	free := []int{getFreePort(t), getFreePort(t), getFreePort(t)}
+	udpPort := getFreeUDPPort(t)
+	specs := []ProxySpec{
+		{LocalPort: free[0], RemoteHost: "127.0.0.1", RemotePort: 8080},
+		{LocalPort: free[1], RemoteHost: "127.0.0.1", RemotePort: 8080},
+		{LocalPort: occupiedPort, RemoteHost: "127.0.0.1", RemotePort: 8080},
+		{LocalPort: free[2], RemoteHost: "127.0.0.1", RemotePort: 8080},
+		{LocalPort: udpPort, RemoteHost: "127.0.0.1", RemotePort: 8080, Protocol: "udp"},
+	}
+
+	manager := NewManager("")
+	defer manager.StopAll()
+
+	err = manager.AddAll(specs)
+	if err == nil {
+		t.Fatal("expected an error for the occupied port")
+	}
+	if !strings.Contains(err.Error(), strconv.Itoa(occupiedPort)) {
+		t.Errorf("expected the error to name port %d, got %v", occupiedPort, err)
+	}
+
+	if got := manager.Stats().Proxies; got != 0 {
+		t.Errorf("expected no proxies after a failed AddAll, got %d", got)
+	}
+	// Every port started by the failed call must be released again
+	for _, port := range free {
+		l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			t.Errorf("port %d still in use after teardown: %v", port, err)
+			continue
+		}
+		l.Close()
+	}
+	pc, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%d", udpPort))
+	if err != nil {
+		t.Errorf("udp port %d still in use after teardown: %v", udpPort, err)
+	} else {
+		pc.Close()
+	}
+}
+
+func TestManager_AddAllKeepsEarlierProxies(t *testing.T) {
+	manager := NewManager("")
+	defer manager.StopAll()
+
+	earlier := getFreePort(t)
+	if err := manager.Add(earlier, "127.0.0.1", 8080); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same local port twice in one batch: one of them fails
+	port := getFreePort(t)
+	err := manager.AddAll([]ProxySpec{
+		{LocalPort: port, RemoteHost: "127.0.0.1", RemotePort: 8080},
+		{LocalPort: port, RemoteHost: "127.0.0.1", RemotePort: 8081},
+	})
+	if err == nil {
+		t.Fatal("expected an error for the duplicate port")
+	}
+
+	if got := manager.Stats().Proxies; got != 1 {
+		t.Errorf("expected the earlier proxy to be kept, got %d proxies", got)
+	}
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", earlier), time.Second)
+	if err != nil {
+		t.Fatalf("earlier proxy stopped: %v", err)
+	}
+	conn.Close()
+}
+
+func TestManager_AddAllEmpty(t *testing.T) {
+	manager := NewManager("")
+	if err := manager.AddAll(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := manager.Stats().Proxies; got != 0 {
+		t.Errorf("expected no proxies, got %d", got)
+	}
+}
+
+// getFreeUDPPort returns an available UDP port
+func getFreeUDPPort(t *testing.T) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	conn.Close()
+	return port
+}
+
+// startUDPEchoServer starts a UDP server that echoes each datagram back to its sender
+func startUDPEchoServer(t *testing.T, port int) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		buf := make([]byte, MaxDatagramSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	return conn
+}
+
+// udpRoundTrip sends msg through conn and returns the reply
+func udpRoundTrip(t *testing.T, conn net.Conn, msg string) string {
+	t.Helper()
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestUDPProxy_ForwardsData(t *testing.T) {
+	localPort := getFreeUDPPort(t)
+	remotePort := getFreeUDPPort(t)
+
+	echoServer := startUDPEchoServer(t, remotePort)
+	defer echoServer.Close()
+
+	proxy := NewUDP("", localPort, "127.0.0.1", remotePort)
+	if proxy.Protocol != "udp" {
+		t.Errorf("expected protocol udp, got %s", proxy.Protocol)
+	}
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	if got := udpRoundTrip(t, conn, "Hello, World!"); got != "Hello, World!" {
+		t.Errorf("expected %q, got %q", "Hello, World!", got)
+	}
+}
+
+func TestUDPProxy_MultipleClients(t *testing.T) {
+	localPort := getFreeUDPPort(t)
+	remotePort := getFreeUDPPort(t)
+
+	echoServer := startUDPEchoServer(t, remotePort)
+	defer echoServer.Close()
+
+	proxy := NewUDP("", localPort, "127.0.0.1", remotePort)
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", localPort))
+			if err != nil {
+				t.Errorf("client %d: failed to connect: %v", id, err)
+				return
+			}
+			defer conn.Close()
+
+			msg := fmt.Sprintf("client-%d", id)
+			conn.Write([]byte(msg))
+			buf := make([]byte, 64)
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			n, err := conn.Read(buf)
+			if err != nil {
+				t.Errorf("client %d: failed to read: %v", id, err)
+				return
+			}
+			if string(buf[:n]) != msg {
+				t.Errorf("client %d: expected %q, got %q", id, msg, string(buf[:n]))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestUDPProxy_IdleSessionsCleanedUp(t *testing.T) {
+	old := udpIdleTimeout
+	udpIdleTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { udpIdleTimeout = old })
+
+	localPort := getFreeUDPPort(t)
+	remotePort := getFreeUDPPort(t)
+
+	echoServer := startUDPEchoServer(t, remotePort)
+	defer echoServer.Close()
+
+	proxy := NewUDP("", localPort, "127.0.0.1", remotePort)
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	udpRoundTrip(t, conn, "ping")
+
+	proxy.sessionsMu.Lock()
+	active := len(proxy.sessions)
+	proxy.sessionsMu.Unlock()
+	if active != 1 {
+		t.Fatalf("expected 1 session, got %d", active)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	proxy.sessionsMu.Lock()
+	active = len(proxy.sessions)
+	proxy.sessionsMu.Unlock()
+	if active != 0 {
+		t.Errorf("expected idle session to be removed, got %d", active)
+	}
+	if len(proxy.connSem) != 0 {
+		t.Errorf("expected semaphore slot to be released, got %d in use", len(proxy.connSem))
+	}
+
+	// A new datagram from the same client opens a fresh session
+	if got := udpRoundTrip(t, conn, "again"); got != "again" {
+		t.Errorf("expected %q, got %q", "again", got)
+	}
+}
+
+func TestUDPProxy_Stop(t *testing.T) {
+	localPort := getFreeUDPPort(t)
+	remotePort := getFreeUDPPort(t)
+
+	echoServer := startUDPEchoServer(t, remotePort)
+	defer echoServer.Close()
+
+	proxy := NewUDP("", localPort, "127.0.0.1", remotePort)
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	udpRoundTrip(t, conn, "ping")
+	conn.Close()
+
+	// Stop must not hang on the open session
+	stopped := make(chan struct{})
+	go func() {
+		proxy.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+
+	pc, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		t.Fatalf("port should be released after stop: %v", err)
+	}
+	pc.Close()
+}
+
+func TestManager_AddUDP(t *testing.T) {
+	localPort := getFreeUDPPort(t)
+	remotePort := getFreeUDPPort(t)
+
+	echoServer := startUDPEchoServer(t, remotePort)
+	defer echoServer.Close()
+
+	manager := NewManager("")
+	defer manager.StopAll()
+
+	if err := manager.AddUDP(localPort, "127.0.0.1", remotePort); err != nil {
+		t.Fatalf("failed to add UDP proxy: %v", err)
+	}
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if got := udpRoundTrip(t, conn, "via manager"); got != "via manager" {
+		t.Errorf("expected %q, got %q", "via manager", got)
+	}
+}
+
+// waitForCondition polls cond until it holds or a second has passed
+func waitForCondition(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProxy_Metrics(t *testing.T) {
+	localPort := getFreePort(t)
+	remotePort := getFreePort(t)
+
+	echoServer, done := startEchoServer(t, remotePort)
+	defer func() {
+		close(done)
+		echoServer.Close()
+	}()
+
+	proxy := New("", localPort, "127.0.0.1", remotePort)
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	if proxy.ConnectionCount() != 0 || proxy.BytesTransferred() != 0 {
+		t.Fatalf("expected zero metrics before any traffic")
+	}
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Write([]byte("hello"))
+	buf := make([]byte, 5)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if got := proxy.ConnectionCount(); got != 1 {
+		t.Errorf("expected 1 active connection, got %d", got)
+	}
+
+	conn.Close()
+	waitForCondition(t, "connection to close", func() bool { return proxy.ConnectionCount() == 0 })
+
+	if got := proxy.TotalConnections(); got != 1 {
+		t.Errorf("expected 1 total connection, got %d", got)
+	}
+	if got := proxy.BytesTransferred(); got != 10 {
+		t.Errorf("expected 10 bytes (5 each way), got %d", got)
+	}
+}
+
+func TestUDPProxy_Metrics(t *testing.T) {
+	localPort := getFreeUDPPort(t)
+	remotePort := getFreeUDPPort(t)
+
+	echoServer := startUDPEchoServer(t, remotePort)
+	defer echoServer.Close()
+
+	proxy := NewUDP("", localPort, "127.0.0.1", remotePort)
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Stop()
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	udpRoundTrip(t, conn, "ping")
+
+	if got := proxy.ConnectionCount(); got != 1 {
+		t.Errorf("expected 1 active client, got %d", got)
+	}
+	if got := proxy.BytesTransferred(); got != 8 {
+		t.Errorf("expected 8 bytes (4 each way), got %d", got)
+	}
+}
+
+func TestManager_Stats(t *testing.T) {
+	remotePort := getFreePort(t)
+	echoServer, done := startEchoServer(t, remotePort)
+	defer func() {
+		close(done)
+		echoServer.Close()
+	}()
+
+	manager := NewManager("")
+	ports := []int{getFreePort(t), getFreePort(t)}
+	for _, port := range ports {
+		if err := manager.Add(port, "127.0.0.1", remotePort); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, port := range ports {
+		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write([]byte("abc"))
+		buf := make([]byte, 3)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		io.ReadFull(conn, buf)
+		conn.Close()
+	}
+	waitForCondition(t, "connections to close", func() bool { return manager.Stats().ActiveConnections == 0 })
+
+	want := Stats{Proxies: 2, TotalConnections: 2, BytesTransferred: 12}
+	if got := manager.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	manager.StopAll()
+	if got := manager.Stats(); got != want {
+		t.Errorf("expected stats to survive StopAll, got %+v", got)
+	}
+}
+
+func TestProxy_IPv6RemoteAddr(t *testing.T) {
+	p := New("", 8000, "fd42::5", 80)
+	if p.RemoteAddr != "[fd42::5]:80" {
+		t.Errorf("expected bracketed IPv6 address, got %q", p.RemoteAddr)
+	}
+
+	p = New("", 8000, "10.0.0.5", 80)
+	if p.RemoteAddr != "10.0.0.5:80" {
+		t.Errorf("expected 10.0.0.5:80, got %q", p.RemoteAddr)
+	}
+}
+
+func TestProxy_ForwardsToIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				io.Copy(c, c)
+			}(conn)
+		}
+	}()
+
+	localPort := getFreePort(t)
+	p := New("", localPort, "::1", listener.Addr().(*net.TCPAddr).Port)
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop()
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", localPort), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("expected echo from the IPv6 server: %v", err)
+	}
+	if string(buf) != "ping" {
+		t.Errorf("expected 'ping', got %q", buf)
+	}
+}
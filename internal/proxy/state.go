@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+)
+
+// StateFile records background proxies started with 'proxy --daemon'
+const StateFile = ".lxc-proxies.json"
+
+// PortForward is one local -> remote port pair served by a daemon
+type PortForward struct {
+	Local  int `json:"local"`
+	Remote int `json:"remote"`
+}
+
+// DaemonEntry describes a background proxy process for one container
+type DaemonEntry struct {
+	Container string        `json:"container"`
+	IP        string        `json:"ip"`
+	Bind      string        `json:"bind"`
+	Ports     []PortForward `json:"ports"`
+	PID       int           `json:"pid"`
+	StartedAt string        `json:"started_at"`
+}
+
+// State is the content of the state file, keyed by container name
+type State struct {
+	Proxies map[string]DaemonEntry `json:"proxies"`
+}
+
+// LoadState reads the state file at path. A missing file is an empty state.
+func LoadState(path string) (*State, error) {
+	state := &State{Proxies: make(map[string]DaemonEntry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid proxy state in %s: %w", path, err)
+	}
+	if state.Proxies == nil {
+		state.Proxies = make(map[string]DaemonEntry)
+	}
+	return state, nil
+}
+
+// SaveState writes the state file atomically. An empty state removes the file.
+func SaveState(path string, state *State) error {
+	if len(state.Proxies) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".lxc-proxies.tmp.*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	return nil
+}
+
+// Names returns the recorded container names in sorted order
+func (s *State) Names() []string {
+	names := make([]string, 0, len(s.Proxies))
+	for name := range s.Proxies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PruneStale drops entries whose process no longer exists and returns their names
+func (s *State) PruneStale() []string {
+	var stale []string
+	for _, name := range s.Names() {
+		if !ProcessRunning(s.Proxies[name].PID) {
+			delete(s.Proxies, name)
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
+
+// ProcessRunning reports whether a process with the given PID exists
+func ProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
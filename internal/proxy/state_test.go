@@ -0,0 +1,95 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+
+	want := &State{Proxies: map[string]DaemonEntry{
+		"dev1": {
+			Container: "dev1",
+			IP:        "10.0.0.2",
+			Bind:      "127.0.0.1",
+			Ports:     []PortForward{{Local: 8080, Remote: 80}, {Local: 5432, Remote: 5432}},
+			PID:       4242,
+			StartedAt: "2024-01-15T10:30:00Z",
+		},
+	}}
+
+	if err := SaveState(path, want); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	got, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestLoadState_MissingFile(t *testing.T) {
+	state, err := LoadState(filepath.Join(t.TempDir(), StateFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Proxies == nil || len(state.Proxies) != 0 {
+		t.Errorf("expected empty state, got %+v", state)
+	}
+}
+
+func TestLoadState_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+	os.WriteFile(path, []byte("{not json"), 0644)
+
+	if _, err := LoadState(path); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+func TestSaveState_EmptyRemovesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+	os.WriteFile(path, []byte(`{"proxies":{}}`), 0644)
+
+	if err := SaveState(path, &State{Proxies: map[string]DaemonEntry{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected state file to be removed")
+	}
+
+	// Removing an already missing file is fine
+	if err := SaveState(path, &State{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestState_PruneStale(t *testing.T) {
+	state := &State{Proxies: map[string]DaemonEntry{
+		"live": {Container: "live", PID: os.Getpid()},
+		"dead": {Container: "dead", PID: 0},
+	}}
+
+	stale := state.PruneStale()
+	if !reflect.DeepEqual(stale, []string{"dead"}) {
+		t.Errorf("stale = %v, want [dead]", stale)
+	}
+	if _, ok := state.Proxies["live"]; !ok {
+		t.Error("live entry should be kept")
+	}
+}
+
+func TestProcessRunning(t *testing.T) {
+	if !ProcessRunning(os.Getpid()) {
+		t.Error("current process should be running")
+	}
+	if ProcessRunning(0) || ProcessRunning(-1) {
+		t.Error("non-positive PIDs are never running")
+	}
+}
@@ -0,0 +1,135 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Resolver returns the current IP address of a container
+type Resolver func(container string) (string, error)
+
+// Change reports a container whose IP changed during Refresh. Err is set if
+// some of its proxies could not be restarted on the new address.
+type Change struct {
+	Container string
+	OldIP     string
+	NewIP     string
+	Err       error
+}
+
+// Refresh re-resolves the IP of every container that has proxies and
+// restarts the proxies of containers whose IP changed, so new connections go
+// to the new address. Containers that cannot be resolved (e.g. while
+// restarting) keep their proxies as they are.
+func (m *Manager) Refresh(resolve Resolver) []Change {
+	// Resolve outside the lock: lookups can be slow
+	var changes []Change
+	for container, oldIP := range m.containerIPs() {
+		newIP, err := resolve(container)
+		if err != nil || newIP == "" || newIP == oldIP {
+			continue
+		}
+		changes = append(changes, Change{Container: container, OldIP: oldIP, NewIP: newIP})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Container < changes[j].Container })
+
+	for i := range changes {
+		changes[i].Err = m.retarget(changes[i].Container, changes[i].NewIP)
+	}
+	return changes
+}
+
+// Watch calls Refresh every interval in a background goroutine until ctx is
+// cancelled, passing each change to report
+func (m *Manager) Watch(ctx context.Context, interval time.Duration, resolve Resolver, report func(Change)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, change := range m.Refresh(resolve) {
+					report(change)
+				}
+			}
+		}
+	}()
+}
+
+// containerIPs returns the remote host currently used for each container
+func (m *Manager) containerIPs() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ips := make(map[string]string)
+	for _, p := range m.proxies {
+		if p.Container == "" {
+			continue
+		}
+		if host, _, err := net.SplitHostPort(p.RemoteAddr); err == nil {
+			ips[p.Container] = host
+		}
+	}
+	return ips
+}
+
+// retarget replaces the container's proxies with ones forwarding to ip.
+// A proxy that cannot be restarted is dropped and reported in the error.
+func (m *Manager) retarget(container, ip string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var failed []int
+	kept := m.proxies[:0]
+	for _, p := range m.proxies {
+		if p.Container != container {
+			kept = append(kept, p)
+			continue
+		}
+
+		_, portStr, err := net.SplitHostPort(p.RemoteAddr)
+		if err != nil {
+			kept = append(kept, p)
+			continue
+		}
+		remotePort, err := strconv.Atoi(portStr)
+		if err != nil {
+			kept = append(kept, p)
+			continue
+		}
+
+		p.Stop()
+		// Keep the old proxy's traffic in the totals without counting it as another port
+		m.stopped.TotalConnections += p.TotalConnections()
+		m.stopped.BytesTransferred += p.BytesTransferred()
+
+		var replacement *Proxy
+		if p.Protocol == "udp" {
+			replacement = NewUDP(p.BindAddr, p.LocalPort, ip, remotePort)
+		} else {
+			replacement = New(p.BindAddr, p.LocalPort, ip, remotePort)
+		}
+		replacement.Container = container
+		if err := replacement.Start(); err != nil {
+			m.stopped.Proxies++
+			failed = append(failed, p.LocalPort)
+			continue
+		}
+		kept = append(kept, replacement)
+	}
+	for i := len(kept); i < len(m.proxies); i++ {
+		m.proxies[i] = nil
+	}
+	m.proxies = kept
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not restart proxies on port(s) %v", failed)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// remoteAddrs returns the remote address of each proxy, keyed by local port
+func remoteAddrs(m *Manager) map[int]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	addrs := make(map[int]string)
+	for _, p := range m.proxies {
+		addrs[p.LocalPort] = p.RemoteAddr
+	}
+	return addrs
+}
+
+func TestManager_RefreshUpdatesRemoteAddr(t *testing.T) {
+	m := NewManager("127.0.0.1")
+	defer m.StopAll()
+
+	port1 := getFreePort(t)
+	port2 := getFreePort(t)
+	if err := m.AddForContainer("dev1", port1, "10.0.0.5", 80); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddForContainer("dev2", port2, "10.0.0.6", 80); err != nil {
+		t.Fatal(err)
+	}
+
+	ips := map[string]string{"dev1": "10.0.0.9", "dev2": "10.0.0.6"}
+	changes := m.Refresh(func(c string) (string, error) { return ips[c], nil })
+
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", changes)
+	}
+	if changes[0].Container != "dev1" || changes[0].OldIP != "10.0.0.5" || changes[0].NewIP != "10.0.0.9" || changes[0].Err != nil {
+		t.Errorf("unexpected change: %+v", changes[0])
+	}
+
+	addrs := remoteAddrs(m)
+	if addrs[port1] != "10.0.0.9:80" {
+		t.Errorf("expected dev1 proxy to forward to 10.0.0.9:80, got %q", addrs[port1])
+	}
+	if addrs[port2] != "10.0.0.6:80" {
+		t.Errorf("expected dev2 proxy to be unchanged, got %q", addrs[port2])
+	}
+}
+
+func TestManager_RefreshForwardsToNewIP(t *testing.T) {
+	remotePort := getFreePort(t)
+	listener, done := startEchoServer(t, remotePort)
+	defer func() {
+		close(done)
+		listener.Close()
+	}()
+
+	m := NewManager("127.0.0.1")
+	defer m.StopAll()
+
+	localPort := getFreePort(t)
+	// Start on an address nothing listens on, then move to the echo server
+	if err := m.AddForContainer("dev1", localPort, "127.0.0.2", remotePort); err != nil {
+		t.Fatal(err)
+	}
+	m.Refresh(func(string) (string, error) { return "127.0.0.1", nil })
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", localPort), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := conn.Read(buf); err != nil {
+		t.Fatalf("expected echo through the new address: %v", err)
+	}
+	if string(buf) != "ping" {
+		t.Errorf("expected 'ping', got %q", buf)
+	}
+}
+
+func TestManager_RefreshIgnoresResolveErrors(t *testing.T) {
+	m := NewManager("127.0.0.1")
+	defer m.StopAll()
+
+	port := getFreePort(t)
+	if err := m.AddForContainer("dev1", port, "10.0.0.5", 80); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := m.Refresh(func(string) (string, error) { return "", errors.New("container stopped") })
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+	changes = m.Refresh(func(string) (string, error) { return "", nil })
+	if len(changes) != 0 {
+		t.Errorf("expected no changes for an empty IP, got %+v", changes)
+	}
+	if addr := remoteAddrs(m)[port]; addr != "10.0.0.5:80" {
+		t.Errorf("expected proxy to be unchanged, got %q", addr)
+	}
+}
+
+func TestManager_RefreshSkipsProxiesWithoutContainer(t *testing.T) {
+	m := NewManager("127.0.0.1")
+	defer m.StopAll()
+
+	port := getFreePort(t)
+	if err := m.Add(port, "10.0.0.5", 80); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	m.Refresh(func(string) (string, error) {
+		called = true
+		return "10.0.0.9", nil
+	})
+	if called {
+		t.Error("expected no lookups for proxies without a container")
+	}
+}
+
+func TestManager_RefreshKeepsStats(t *testing.T) {
+	m := NewManager("127.0.0.1")
+	defer m.StopAll()
+
+	if err := m.AddForContainer("dev1", getFreePort(t), "10.0.0.5", 80); err != nil {
+		t.Fatal(err)
+	}
+	m.Refresh(func(string) (string, error) { return "10.0.0.9", nil })
+
+	if stats := m.Stats(); stats.Proxies != 1 {
+		t.Errorf("expected 1 proxy after refresh, got %d", stats.Proxies)
+	}
+}
+
+func TestManager_Watch(t *testing.T) {
+	m := NewManager("127.0.0.1")
+	defer m.StopAll()
+
+	port := getFreePort(t)
+	if err := m.AddForContainer("dev1", port, "10.0.0.5", 80); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reported := make(chan Change, 1)
+	m.Watch(ctx, 10*time.Millisecond, func(string) (string, error) { return "10.0.0.9", nil }, func(c Change) {
+		reported <- c
+	})
+
+	select {
+	case c := <-reported:
+		if c.NewIP != "10.0.0.9" {
+			t.Errorf("expected new IP 10.0.0.9, got %+v", c)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a change to be reported")
+	}
+	if addr := remoteAddrs(m)[port]; addr != "10.0.0.9:80" {
+		t.Errorf("expected proxy to forward to 10.0.0.9:80, got %q", addr)
+	}
+}
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 )
@@ -9,6 +10,8 @@ import (
 const (
 	// MaxContainerNameLength is the max length for a container name
 	MaxContainerNameLength = 63
+	// MaxImageAliasLength is the max length for an image alias created by this tool
+	MaxImageAliasLength = 63
 	// MaxCombinedLength is LXC's limit for full container name (project-container)
 	MaxCombinedLength = 63
 	// MinPort is the minimum valid port number
@@ -23,6 +26,48 @@ var (
 	// LXC naming rules: start with letter, alphanumeric + hyphens
 	containerNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
 
+	// LXD remote names: alphanumeric, hyphens, underscores, dots (no colons)
+	remoteNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+	// Image aliases and fingerprints: ubuntu/24.04, debian/12/cloud, my-image, 24.04
+	imageAliasRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
+
+	// LXD profile names: alphanumeric, hyphens, underscores, dots
+	profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+	// Profiles that ship with LXD or are commonly set up alongside it
+	knownProfiles = map[string]bool{
+		"default": true,
+		"docker":  true,
+		"macvlan": true,
+	}
+
+	// Environment variable names: letters, digits, underscores, not starting with a digit
+	envKeyRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// Memory sizes: a number with an optional byte unit (2GB, 512MB, 2GiB), or a percentage
+	memoryLimitRegex = regexp.MustCompile(`^(\d+(\.\d+)?(B|[kKMGTPE]i?B)?|\d+%)$`)
+
+	// CPU limits: a CPU count (2) or a set of CPUs/ranges (0-3, 0,2)
+	cpuLimitRegex = regexp.MustCompile(`^(\d+)(-\d+)?(,\d+(-\d+)?)*$`)
+
+	// SSH public key types accepted in authorized_keys
+	sshKeyTypeRegex = regexp.MustCompile(`^(ssh-(rsa|dss|ed25519)|ecdsa-sha2-nistp(256|384|521)|sk-(ssh-ed25519|ecdsa-sha2-nistp256)@openssh\.com)$`)
+
+	// Ready strategies understood by lxc.WaitForReady
+	readyStrategies = map[string]bool{
+		"cloud-init": true,
+		"systemd":    true,
+		"custom":     true,
+	}
+
+	// Image pull policies understood by 'container create'
+	pullPolicies = map[string]bool{
+		"always":         true,
+		"never":          true,
+		"if-not-present": true,
+	}
+
 	// Reserved names that conflict with LXC commands/concepts
 	reservedNames = map[string]bool{
 		"list":     true,
@@ -124,3 +169,178 @@ func ValidatePorts(ports []int) error {
 
 	return nil
 }
+
+// ValidateRemoteName checks if an LXD remote name is valid
+func ValidateRemoteName(name string) error {
+	if name == "" {
+		return fmt.Errorf("remote name cannot be empty")
+	}
+	if strings.Contains(name, ":") {
+		return fmt.Errorf("remote name %q must not contain ':' (use 'myremote', not 'myremote:')", name)
+	}
+	if !remoteNameRegex.MatchString(name) {
+		return fmt.Errorf("remote name %q contains invalid characters (allowed: letters, numbers, '.', '-', '_')", name)
+	}
+	return nil
+}
+
+// ValidateImageName checks an image reference such as "ubuntu:24.04",
+// "images:debian/12" or a bare local alias like "my-image"
+func ValidateImageName(image string) error {
+	if image == "" {
+		return fmt.Errorf("image name cannot be empty")
+	}
+
+	alias := image
+	if remote, rest, ok := strings.Cut(image, ":"); ok {
+		if remote == "" || !remoteNameRegex.MatchString(remote) {
+			return fmt.Errorf("invalid image %q: remote %q contains invalid characters (expected remote:image, e.g. ubuntu:24.04)", image, remote)
+		}
+		if rest == "" {
+			return fmt.Errorf("invalid image %q: missing image after '%s:'", image, remote)
+		}
+		alias = rest
+	}
+
+	if !imageAliasRegex.MatchString(alias) {
+		return fmt.Errorf("invalid image %q (allowed: letters, numbers, '.', '-', '_', '/', with an optional remote: prefix)", image)
+	}
+	return nil
+}
+
+// ValidateImageAlias checks an alias given to a new image. It follows the
+// container naming rules, so any container name is also a valid alias.
+func ValidateImageAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("image alias cannot be empty")
+	}
+
+	if len(alias) > MaxImageAliasLength {
+		return fmt.Errorf("image alias too long: %d characters (max %d)",
+			len(alias), MaxImageAliasLength)
+	}
+
+	if !containerNameRegex.MatchString(alias) {
+		if alias[0] >= '0' && alias[0] <= '9' {
+			return fmt.Errorf("image alias must start with a letter, not '%c'", alias[0])
+		}
+		if strings.ContainsAny(alias, " \t") {
+			return fmt.Errorf("image alias cannot contain spaces")
+		}
+		return fmt.Errorf("image alias %q contains invalid characters (allowed: letters, numbers, hyphens)", alias)
+	}
+
+	if strings.HasSuffix(alias, "-") {
+		return fmt.Errorf("image alias cannot end with a hyphen")
+	}
+
+	return nil
+}
+
+// ValidateProfileName checks if an LXD profile name is valid
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("profile name %q contains invalid characters (allowed: letters, numbers, '.', '-', '_')", name)
+	}
+	return nil
+}
+
+// IsKnownProfile reports whether name is a built-in or common LXD profile.
+// Other profiles work too, but must be created with 'lxc profile create' first.
+func IsKnownProfile(name string) bool {
+	return knownProfiles[name]
+}
+
+// ValidateEnv checks environment variable names and values
+// Values are written to /etc/environment, so they cannot contain newlines or double quotes
+func ValidateEnv(env map[string]string) error {
+	for key, value := range env {
+		if !envKeyRegex.MatchString(key) {
+			return fmt.Errorf("invalid environment variable name %q (allowed: letters, numbers, underscores; must not start with a number)", key)
+		}
+		if strings.ContainsAny(value, "\n\r") {
+			return fmt.Errorf("environment variable %s cannot contain newlines", key)
+		}
+		if strings.Contains(value, `"`) {
+			return fmt.Errorf("environment variable %s cannot contain double quotes", key)
+		}
+	}
+	return nil
+}
+
+// ValidateMemoryLimit checks a limits.memory value such as "2GB", "512MiB" or "50%"
+func ValidateMemoryLimit(memory string) error {
+	if !memoryLimitRegex.MatchString(memory) {
+		return fmt.Errorf("invalid memory limit %q (expected a size like 512MB or 2GiB, or a percentage like 50%%)", memory)
+	}
+	return nil
+}
+
+// ValidateCPULimit checks a limits.cpu value such as "2" or "0-3"
+func ValidateCPULimit(cpu string) error {
+	if !cpuLimitRegex.MatchString(cpu) || cpu == "0" {
+		return fmt.Errorf("invalid CPU limit %q (expected a CPU count like 2, or a CPU set like 0-3)", cpu)
+	}
+	return nil
+}
+
+// ValidateBindAddr checks that a proxy bind address is an IP address
+func ValidateBindAddr(addr string) error {
+	if net.ParseIP(addr) == nil {
+		return fmt.Errorf("invalid bind address %q (expected an IP address such as 127.0.0.1 or 0.0.0.0)", addr)
+	}
+	return nil
+}
+
+// ValidateSSHPublicKey checks that key is a single OpenSSH public key line
+// ("<type> <base64> [comment]"), as found in a .pub file
+func ValidateSSHPublicKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("SSH public key is empty")
+	}
+	if strings.Contains(key, "PRIVATE KEY") {
+		return fmt.Errorf("this looks like a private key; pass the .pub file instead")
+	}
+	if strings.ContainsAny(key, "\r\n") {
+		return fmt.Errorf("SSH public key must be a single line")
+	}
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return fmt.Errorf("invalid SSH public key (expected '<type> <key> [comment]')")
+	}
+	if !sshKeyTypeRegex.MatchString(fields[0]) {
+		return fmt.Errorf("invalid SSH public key type %q", fields[0])
+	}
+	return nil
+}
+
+// ValidatePullPolicy checks an image pull policy
+func ValidatePullPolicy(policy string) error {
+	if !pullPolicies[policy] {
+		return fmt.Errorf("invalid image pull policy %q (allowed: always, never, if-not-present)", policy)
+	}
+	return nil
+}
+
+// ValidateReadyStrategy checks a ready_strategy value; "custom" needs a command
+func ValidateReadyStrategy(strategy, command string) error {
+	if strategy == "" {
+		if command != "" {
+			return fmt.Errorf("ready_command is only used with ready_strategy: custom")
+		}
+		return nil
+	}
+	if !readyStrategies[strategy] {
+		return fmt.Errorf("invalid ready strategy %q (allowed: cloud-init, systemd, custom)", strategy)
+	}
+	if strategy == "custom" && strings.TrimSpace(command) == "" {
+		return fmt.Errorf("ready strategy custom requires a ready_command")
+	}
+	if strategy != "custom" && command != "" {
+		return fmt.Errorf("ready_command is only used with ready_strategy: custom")
+	}
+	return nil
+}